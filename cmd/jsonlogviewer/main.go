@@ -4,21 +4,62 @@
 //
 // Usage:
 //
-//	jsonlogviewer [flags] [file]
+//	jsonlogviewer [flags] [file...]
 //	cat app.log | jsonlogviewer [flags]
 //
 // Flags:
 //
-//	-debug    Enable debug logging to ./logs/
+//	-debug      Enable debug logging to ./logs/
+//	-version    Print the version and build info, then exit
+//	-max-bytes  Cap on decompressed bytes read from .gz/.bz2/.xz files (0 = no limit)
+//	-save-index Save the line index to <file>.jlvidx for instant reopen
+//	-no-truncate Never cut detail lines; scroll horizontally with zh/zl
+//	-hscroll-reset Reset the table's message scroll when the cursor changes rows
+//	-relative-numbers Start with Row showing distances from the cursor (toggle with #)
+//	-match-numbers Add a Match column numbering the rows shown, next to Row's line numbers
+//	-page-keep-cursor Keep the cursor on the same screen row when paging, like less
+//	-format     Line format: auto (default), json, or logfmt
+//	-theme      Colors for a light or dark terminal: light, dark, or auto (default)
+//	-cache-size Parsed lines kept for redrawing while scrolling (0 = off)
+//	-indent     Indent pretty-printed JSON by N spaces (default 2) or a tab
+//	-time-field Read timestamps from this gjson path instead of auto-detecting
+//	-time-format Go time layout for table timestamps (default 2006-01-02 15:04:05)
+//	-since      Show only entries at or after a time (RFC 3339, or e.g. -1h for an hour ago)
+//	-until      Show only entries at or before a time, like -since
+//	-exclude-untimed Hide entries without a recognized timestamp
+//	-source-dir Base directory for relative source.file paths opened with gf
+//	-force      Overwrite existing files from :w without asking
+//	-count      Print the number of lines and exit (no TUI)
+//	-check      Index the input, verify the index against the data, and exit
+//	-pretty     Pretty-print every entry to stdout, blank-line separated, and exit (no TUI)
+//	-progress   Report scan progress on stderr in headless modes such as -count
+//	-follow     Keep reading lines appended to the file, like tail -f
+//	-columns    Comma-separated gjson paths shown as table columns (path[:Title])
+//	-fields-autodetect Add the most common fields of the first lines as columns
+//	-msg-len    Starting width of the message column
+//	-lazy       Show a large file while the rest of it is indexed in the background
+//	-tail       With -max-lines N, index only the last N lines of the file
+//	-max-lines  Number of lines -tail keeps
+//	-no-mmap    Read the file into memory instead of mapping it (for NFS/SMB)
+//	-encoding   Read input without a byte order mark as utf-16le, utf-16be, or utf-8
+//
+// Colors are read from ~/.config/jsonlogviewer/theme.json when it exists.
+// The column layout, including its order, is kept between runs in
+// ~/.config/jsonlogviewer/session.json unless -columns or
+// -fields-autodetect picks the columns.
 //
 // Navigation:
 //
 //	Arrow keys, j/k       Move cursor up/down
 //	Page Up/Down, C-b/C-f Page up/down
 //	Home/End, gg/G        First/last line
+//	{n}%                  Jump to n percent of the file
 //	C-e/C-y               Scroll view up/down
 //	C-u/C-d               Half page up/down
 //	H/M/L                 Cursor to top/middle/bottom of visible
+//	C-g                   Show line, percentage, and byte offset
+//	C-l                   Redraw the screen
+//	R                     Reload the file from disk
 //	F1, ?                 Toggle help
 //	q, Esc                Quit
 //
@@ -26,42 +67,198 @@
 //
 //	jsonlogviewer /var/log/app.json
 //	journalctl -o json | jsonlogviewer
+//	mkfifo /tmp/app.pipe && jsonlogviewer /tmp/app.pipe
 package main
 
 import (
+	"bufio"
+	"errors"
 	"flag"
 	"fmt"
 	"io"
+	"io/fs"
 	"log/slog"
 	"os"
 	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/lbe/jsonlogviewer/internal/index"
+	"github.com/lbe/jsonlogviewer/internal/parser"
 	"github.com/lbe/jsonlogviewer/internal/tui"
+	"github.com/tidwall/gjson"
 )
 
 // version is set during build.
 var version = "0.1.0"
 
+// buildDate is set during build with -ldflags "-X main.buildDate=...";
+// empty when it wasn't.
+var buildDate = ""
+
+// lazyInitialLines is how many lines -lazy indexes before starting the TUI.
+const lazyInitialLines = 10000
+
 // Config holds the application configuration.
 type Config struct {
 	// Debug enables debug logging when true.
 	Debug bool
+	// Version prints the version and exits without opening any input.
+	Version bool
 	// FilePath is the path to the log file (empty for stdin).
 	FilePath string
+	// FilePaths lists every file argument; with more than one, the files
+	// are viewed as one stream.
+	FilePaths []string
+	// MaxBytes caps how much decompressed data is read from a compressed
+	// file. Zero means no limit.
+	MaxBytes int64
+	// SaveIndex writes the line offset index to a sidecar file next to the
+	// log so later opens can skip the scan.
+	SaveIndex bool
+	// NoTruncate starts the detail pane in no-truncate mode.
+	NoTruncate bool
+	// HScrollReset scrolls table messages back to their start whenever the
+	// cursor moves to another row.
+	HScrollReset bool
+	// RelativeNumbers starts the table with relative row numbers.
+	RelativeNumbers bool
+	// MatchNumbers adds the Match column numbering the rows shown.
+	MatchNumbers bool
+	// PageKeepCursor moves the cursor with the view when paging so it
+	// keeps its screen row.
+	PageKeepCursor bool
+	// Format is the line format: auto, json, or logfmt.
+	Format string
+	// Theme picks the default colors: light, dark, or auto to ask the
+	// terminal.
+	Theme string
+	// CacheSize is how many parsed lines the TUI keeps for redrawing; 0
+	// turns the cache off.
+	CacheSize int
+	// Indent is the indentation of pretty-printed JSON: a number of spaces
+	// or "tab".
+	Indent string
+	// TimeField is the gjson path of the timestamp; empty auto-detects.
+	TimeField string
+	// TimeFormat is the Go time layout for table timestamps; empty uses
+	// the default.
+	TimeFormat string
+	// Since and Until bound the timestamps of the entries shown: a time
+	// or a duration back from now. Empty leaves that end open.
+	Since string
+	Until string
+	// ExcludeUntimed hides entries without a recognized timestamp.
+	ExcludeUntimed bool
+	// SourceDir resolves relative source.file paths for gf.
+	SourceDir string
+	// Force skips overwrite confirmations for file-writing commands.
+	Force bool
+	// Count prints the line count to stdout instead of starting the TUI.
+	Count bool
+	// Check indexes the input and prints a report on whether the index
+	// matches the data instead of starting the TUI.
+	Check bool
+	// Pretty writes every entry pretty-printed to stdout instead of
+	// starting the TUI.
+	Pretty bool
+	// Progress reports scan progress on stderr in headless modes.
+	Progress bool
+	// Follow re-reads the file as it grows.
+	Follow bool
+	// Columns lists the table columns as gjson paths; empty uses the
+	// default Time/Level/Message layout.
+	Columns string
+	// FieldsAutodetect adds columns for the most common fields of the
+	// first lines; Columns takes precedence.
+	FieldsAutodetect bool
+	// MsgLen is the starting message column width; zero uses the default.
+	MsgLen int
+	// Lazy indexes the first lines of the file and starts the TUI while
+	// the rest is indexed in the background.
+	Lazy bool
+	// NoMmap reads the file into memory instead of memory-mapping it.
+	NoMmap bool
+	// Tail indexes only the last MaxLines lines of the file.
+	Tail bool
+	// MaxLines is how many lines Tail keeps.
+	MaxLines int
+	// Encoding is the encoding of input without a byte order mark: auto,
+	// utf-8, utf-16le, or utf-16be.
+	Encoding string
 }
 
 func main() {
 	config := parseFlags()
 
+	if config.Version {
+		fmt.Println(versionInfo())
+		return
+	}
+
 	// Setup logging first
 	logger := setupLogging(config.Debug)
 	logger.Info("jsonlogviewer starting", "version", version)
 
+	// Headless modes scan the input once and never start the TUI
+	if config.Count {
+		if err := runCount(config); err != nil {
+			logger.Error("count failed", "error", err)
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if config.Check {
+		if err := runCheck(config); err != nil {
+			logger.Error("check failed", "error", err)
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	format, err := parser.ParseFormat(config.Format)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: -format: %v\n", err)
+		os.Exit(1)
+	}
+	background, err := tui.ParseBackground(config.Theme)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: -theme: %v\n", err)
+		os.Exit(1)
+	}
+	indent, err := parser.ParseIndent(config.Indent)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: -indent: %v\n", err)
+		os.Exit(1)
+	}
+	since, until, err := parseTimeRange(config.Since, config.Until, time.Now())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Pretty output is headless too, but honors the parsing flags above
+	if config.Pretty {
+		p := parser.New()
+		p.SetFormat(format)
+		p.SetIndent(indent)
+		p.SetTimeField(config.TimeField)
+		p.SetMaxMsgLen(0)
+		if err := runPretty(config, p, since, until); err != nil {
+			logger.Error("pretty failed", "error", err)
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	// Open the log source
-	idx, err := openSource(config)
+	idx, err := openSource(config, true)
 	if err != nil {
 		logger.Error("failed to open source", "error", err)
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
@@ -75,8 +272,97 @@ func main() {
 
 	logger.Info("index loaded", "lines", idx.LineCount(), "source", idx.Name())
 
+	if config.SaveIndex && config.FilePath != "" {
+		sidecar := index.SidecarPath(config.FilePath)
+		if err := idx.SaveIndex(sidecar); err != nil {
+			// Viewing still works without a saved index
+			logger.Warn("failed to save index", "error", err)
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		} else {
+			logger.Info("index saved", "path", sidecar)
+		}
+	}
+
+	if config.Follow {
+		// Following needs a plain file to re-read; catch stdin and
+		// compressed or UTF-16 input before the TUI starts
+		if _, err := idx.Refresh(); errors.Is(err, index.ErrNotRefreshable) {
+			fmt.Fprintf(os.Stderr, "Error: -follow needs an uncompressed UTF-8 file, not %s\n", idx.Name())
+			_ = idx.Close()
+			os.Exit(1)
+		}
+	}
+
+	if config.TimeField != "" {
+		if err := validateTimeField(idx, config.TimeField); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			_ = idx.Close()
+			os.Exit(1)
+		}
+	}
+
+	columns, err := tui.ParseColumns(config.Columns)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: -columns: %v\n", err)
+		_ = idx.Close()
+		os.Exit(1)
+	}
+
+	var autoFields []string
+	if config.FieldsAutodetect && config.Columns == "" {
+		autoFields = detectColumns(idx)
+		logger.Info("detected columns", "fields", autoFields)
+	}
+
+	// Files can be re-read from disk with R; stdin and named pipes can't
+	// be read again
+	var reopen func() (*index.Index, error)
+	if config.FilePath != "" && !index.IsPipe(config.FilePath) {
+		reopen = func() (*index.Index, error) {
+			return openSource(config, false)
+		}
+	}
+
+	// A saved layout only stands in for the columns nobody asked for
+	useSession := config.Columns == "" && !config.FieldsAutodetect
+	var session *tui.Session
+	if useSession {
+		session = loadSession(logger)
+	}
+
 	// Create and run the TUI program
-	model := tui.New(idx, version)
+	// Lines that fail to parse are only worth logging when someone will
+	// read the log
+	var lineLogger *slog.Logger
+	if config.Debug {
+		lineLogger = logger
+	}
+	model := tui.New(idx,
+		tui.WithVersion(version),
+		tui.WithBuildInfo(buildInfo()),
+		tui.WithColumns(columns),
+		tui.WithAutoColumns(autoFields),
+		tui.WithSession(session),
+		tui.WithFollow(config.Follow),
+		tui.WithTheme(loadTheme(logger)),
+		tui.WithBackground(background),
+		tui.WithEntryCache(config.CacheSize),
+		tui.WithReload(reopen),
+		tui.WithLogger(lineLogger),
+	)
+	model.SetMsgLen(config.MsgLen)
+	model.SetNoTruncate(config.NoTruncate)
+	model.SetResetHScroll(config.HScrollReset)
+	model.SetRelativeNumbers(config.RelativeNumbers)
+	model.SetMatchNumbers(config.MatchNumbers)
+	model.SetPageKeepCursor(config.PageKeepCursor)
+	model.SetFormat(format)
+	model.SetIndent(indent)
+	model.SetTimeField(config.TimeField)
+	model.SetTimeFormat(config.TimeFormat)
+	model.SetTimeRange(since, until, config.ExcludeUntimed)
+	model.SetSourceDir(config.SourceDir)
+	model.SetForce(config.Force)
 	p := tea.NewProgram(
 		&model,
 		tea.WithAltScreen(),
@@ -89,6 +375,10 @@ func main() {
 		os.Exit(1)
 	}
 
+	if useSession {
+		saveSession(logger, model.Session())
+	}
+
 	logger.Info("jsonlogviewer exiting normally")
 }
 
@@ -96,17 +386,66 @@ func main() {
 func parseFlags() Config {
 	var config Config
 	flag.BoolVar(&config.Debug, "debug", false, "Enable debug logging to ./logs/")
+	flag.BoolVar(&config.Version, "version", false, "Print the version, Go version, and build date, then exit")
+	flag.Int64Var(&config.MaxBytes, "max-bytes", 0, "Maximum decompressed bytes to read from compressed (.gz, .bz2, .xz) files; 0 means no limit")
+	flag.BoolVar(&config.SaveIndex, "save-index", false, "Save the line index to <file>"+index.SidecarSuffix+" for instant reopen")
+	flag.BoolVar(&config.NoTruncate, "no-truncate", false, "Never truncate detail lines; scroll horizontally with zh/zl instead")
+	flag.BoolVar(&config.RelativeNumbers, "relative-numbers", false, "Start with the Row column showing distances from the cursor row (toggle with #)")
+	flag.BoolVar(&config.MatchNumbers, "match-numbers", false, "Add a Match column numbering the rows shown 1, 2, 3..., while Row keeps the file line numbers")
+	flag.BoolVar(&config.PageKeepCursor, "page-keep-cursor", false, "Keep the cursor on the same screen row when paging with PgUp/PgDn and Ctrl+b/Ctrl+f, like less, instead of moving it to the edge of the new page")
+	flag.BoolVar(&config.HScrollReset, "hscroll-reset", false, "Scroll table messages back to their start (Shift+Left/Right) when the cursor moves to another row")
+	flag.StringVar(&config.Format, "format", "auto", "Line format: json, logfmt (key=value pairs), or auto to detect each line (JSON when it starts with '{')")
+	flag.StringVar(&config.Theme, "theme", "auto", "Default colors for a light or dark terminal background: light, dark, or auto to ask the terminal; theme.json colors apply on top")
+	flag.IntVar(&config.CacheSize, "cache-size", tui.DefaultEntryCacheSize, "Number of parsed lines kept for redrawing while scrolling; 0 turns the cache off")
+	flag.StringVar(&config.Indent, "indent", "2", "Indentation of pretty-printed JSON in the detail pane, pager, and copies: a number of spaces (1-8) or tab")
+	flag.StringVar(&config.TimeField, "time-field", "", "gjson path of the timestamp field (e.g. event_time), overriding auto-detection")
+	flag.StringVar(&config.TimeFormat, "time-format", "", "Go time layout for table timestamps (e.g. 15:04:05.000 or "+time.RFC3339+")")
+	flag.StringVar(&config.Since, "since", "", "Show only entries at or after this time: RFC 3339 (e.g. 2024-01-15T10:00:00Z) or a duration back from now (e.g. -1h)")
+	flag.StringVar(&config.Until, "until", "", "Show only entries at or before this time, given like -since")
+	flag.BoolVar(&config.ExcludeUntimed, "exclude-untimed", false, "Hide entries without a recognized timestamp, which -since and -until otherwise keep")
+	flag.StringVar(&config.SourceDir, "source-dir", "", "Base directory for relative source.file paths opened with gf")
+	flag.BoolVar(&config.Force, "force", false, "Overwrite existing files from :w without asking")
+	flag.BoolVar(&config.Count, "count", false, "Print the number of lines and exit without starting the TUI")
+	flag.BoolVar(&config.Check, "check", false, "Index the input, verify every line offset and the line count against the data, print a report, and exit without starting the TUI")
+	flag.BoolVar(&config.Pretty, "pretty", false, "Pretty-print every entry to stdout, separated by blank lines, and exit without starting the TUI; -columns picks the fields and -since/-until filter the entries")
+	flag.BoolVar(&config.Progress, "progress", false, "Report lines, bytes, and rate on stderr while scanning in headless modes")
+	flag.StringVar(&config.Columns, "columns", "", "Comma-separated gjson paths to show as table columns, each optionally path:Title (e.g. time,level,request_id,user.name:User)")
+	flag.BoolVar(&config.FieldsAutodetect, "fields-autodetect", false, fmt.Sprintf("Add the most common fields of the first %d lines as table columns, as many as fit; -columns overrides it", parser.DetectColumnsSample))
+	flag.IntVar(&config.MsgLen, "msg-len", 0, "Starting width of the table's message column (10-500; adjust with { and })")
+	flag.BoolVar(&config.Follow, "follow", false, "Keep reading lines appended to the file, like tail -f")
+	flag.BoolVar(&config.Lazy, "lazy", false, "Start showing a large file while the rest of it is indexed in the background")
+	flag.BoolVar(&config.NoMmap, "no-mmap", false, "Read the whole file into memory instead of memory-mapping it: uses RAM for the full file but is more reliable on network filesystems (NFS, SMB); turns off -lazy")
+	flag.BoolVar(&config.Tail, "tail", false, "Index only the last -max-lines lines of the file, reading backward from the end, for a quick look at a huge file")
+	flag.IntVar(&config.MaxLines, "max-lines", 0, "Number of lines -tail keeps")
+	flag.StringVar(&config.Encoding, "encoding", "auto", "Encoding of input without a byte order mark: utf-8, utf-16le, or utf-16be; auto reads UTF-8, and UTF-16 with a byte order mark")
 	flag.Parse()
 
 	// Remaining arguments are treated as the file path
 	args := flag.Args()
 	if len(args) > 0 {
 		config.FilePath = args[0]
+		config.FilePaths = args
 	}
 
 	return config
 }
 
+// versionInfo describes the build for -version, e.g.
+// "jsonlogviewer 0.1.0 (go1.22.1, built 2024-05-01T12:00:00Z)".
+func versionInfo() string {
+	return fmt.Sprintf("jsonlogviewer %s (%s)", version, buildInfo())
+}
+
+// buildInfo describes the Go version and build date, e.g.
+// "go1.22.1, built 2024-05-01T12:00:00Z".
+func buildInfo() string {
+	info := runtime.Version()
+	if buildDate != "" {
+		info += ", built " + buildDate
+	}
+	return info
+}
+
 // setupLogging configures the slog logger.
 // When debug is false, logs are discarded.
 // When debug is true, logs are written to ./logs/jsonlogviewer-YYYYMMDD-HHMMSS.log.
@@ -142,38 +481,460 @@ func setupLogging(debug bool) *slog.Logger {
 	}))
 }
 
-// openSource opens the log source (file or stdin).
-func openSource(config Config) (*index.Index, error) {
+// openSource opens the log source (file or stdin). With progress, a large
+// file draws a progress bar on stderr while it's indexed; a reload from
+// inside the TUI goes without, since the bar would garble the screen.
+func openSource(config Config, progress bool) (*index.Index, error) {
+	if config.MaxLines != 0 && !config.Tail {
+		return nil, fmt.Errorf("-max-lines needs -tail")
+	}
+	if config.Tail && config.MaxLines < 1 {
+		return nil, fmt.Errorf("-tail needs -max-lines with a count of at least 1")
+	}
+	if config.Tail && config.Follow {
+		return nil, fmt.Errorf("-tail can't be followed; use -follow alone to watch the end of a file")
+	}
+	encoding, err := index.ParseEncoding(config.Encoding)
+	if err != nil {
+		return nil, fmt.Errorf("-encoding: %w", err)
+	}
+	// A forced encoding is applied while the whole input is decoded at
+	// once, which rules out the inputs read in pieces
+	forced := encoding != index.EncodingAuto
+	if forced && (config.Tail || config.Lazy || len(config.FilePaths) > 1) {
+		return nil, fmt.Errorf("-encoding can't be combined with -tail, -lazy, or several files")
+	}
+	// The lazy scan runs over one mapped file, and the tail is read back
+	// from the end of one
+	if config.Lazy && len(config.FilePaths) > 1 {
+		return nil, fmt.Errorf("-lazy can't be combined with several files")
+	}
+	if config.Tail && len(config.FilePaths) > 1 {
+		return nil, fmt.Errorf("-tail can't be combined with several files")
+	}
+	opts := []index.OpenOption{index.WithEncoding(encoding)}
+
 	if config.FilePath == "" {
+		if config.Tail {
+			return nil, fmt.Errorf("-tail needs a file; stdin can't be read from the end")
+		}
 		// Read from stdin
 		if isStdinEmpty() {
 			return nil, fmt.Errorf("no input provided: specify a file or pipe data via stdin")
 		}
-		return index.OpenReader(os.Stdin, "stdin")
+		// -pretty writes every line, so it needs them all before it starts
+		if forced || config.Pretty {
+			return index.OpenReader(os.Stdin, "stdin", opts...)
+		}
+		// Lines are shown as they arrive, so a producer that never
+		// exits, such as journalctl -f, can be watched
+		return index.OpenReaderStream(os.Stdin, "stdin"), nil
 	}
 
-	// Check if file exists
-	info, err := os.Stat(config.FilePath)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return nil, fmt.Errorf("file not found: %s", config.FilePath)
+	for _, path := range config.FilePaths {
+		if err := checkFile(path); err != nil {
+			return nil, err
 		}
-		return nil, fmt.Errorf("cannot access file: %w", err)
 	}
 
-	if info.IsDir() {
-		return nil, fmt.Errorf("path is a directory: %s", config.FilePath)
+	// Several files are read as one stream, oldest first, each mapped or
+	// read into memory on its own
+	if len(config.FilePaths) > 1 {
+		paths, err := byModTime(config.FilePaths)
+		if err != nil {
+			return nil, err
+		}
+		if config.NoMmap {
+			return index.OpenMultiFile(paths)
+		}
+		return index.OpenMulti(paths)
 	}
 
-	// Try memory-mapped file first
-	idx, err := index.Open(config.FilePath)
+	// A pipe doesn't end while its writer is open, so its lines are
+	// indexed as they arrive instead of after reading it in full
+	if index.IsPipe(config.FilePath) {
+		if config.Tail {
+			return nil, fmt.Errorf("-tail needs a regular file; a named pipe can't be read from the end")
+		}
+		if forced {
+			return nil, fmt.Errorf("-encoding can't be used with a named pipe")
+		}
+		return index.OpenPipe(config.FilePath)
+	}
+
+	// Only the end of the file is read, so a huge file opens at once
+	if config.Tail {
+		return index.OpenTail(config.FilePath, config.MaxLines, config.MaxBytes)
+	}
+
+	// Compressed files can't be memory-mapped; decompress into memory
+	if format, err := index.DetectCompression(config.FilePath); err == nil && format != index.CompressionNone {
+		if forced {
+			return nil, fmt.Errorf("-encoding can't be used with a compressed file")
+		}
+		return index.OpenCompressed(config.FilePath, config.MaxBytes)
+	}
+
+	// Reading into memory skips mmap entirely, including the lazy scan
+	// over the mapping
+	if config.NoMmap {
+		return index.OpenFile(config.FilePath, opts...)
+	}
+
+	if config.Lazy {
+		return index.OpenLazy(config.FilePath, lazyInitialLines)
+	}
+
+	// Try memory-mapped file first, reusing a saved index when it's current
+	if progress {
+		if bar := indexProgressBar(config.FilePath); bar != nil {
+			opts = append(opts, index.WithProgress(bar))
+		}
+	}
+	idx, _, err := index.OpenIndexed(config.FilePath, opts...)
 	if err != nil {
 		// Fall back to regular file reading
-		return index.OpenFile(config.FilePath)
+		return index.OpenFile(config.FilePath, opts...)
 	}
 	return idx, nil
 }
 
+// progressMinSize is the smallest file that gets a progress bar while it's
+// indexed; smaller files are indexed before a bar would be noticed.
+const progressMinSize = 64 << 20
+
+// progressBarWidth is the number of cells between the bar's brackets.
+const progressBarWidth = 30
+
+// indexProgressBar returns a progress callback drawing a bar on stderr
+// while path is indexed, so a multi-gigabyte file doesn't look hung before
+// the TUI starts. It returns nil when stderr isn't a terminal or the file
+// is small. The bar is erased when indexing finishes.
+func indexProgressBar(path string) index.ProgressFunc {
+	info, err := os.Stat(path)
+	if err != nil || info.Size() < progressMinSize || !isTerminal(os.Stderr) {
+		return nil
+	}
+	name := filepath.Base(path)
+	last := -1
+	return func(done, total int64) {
+		percent := int(done * 100 / total)
+		if percent == last {
+			return
+		}
+		last = percent
+		line := progressBar(name, done, total)
+		fmt.Fprintf(os.Stderr, "\r%s", line)
+		if done == total {
+			fmt.Fprintf(os.Stderr, "\r%s\r", strings.Repeat(" ", len(line)))
+		}
+	}
+}
+
+// progressBar renders indexing progress as one line, e.g.
+// "Indexing app.log [#########---------------------] 30% 1.2 GiB/4.0 GiB".
+func progressBar(name string, done, total int64) string {
+	filled := int(done * progressBarWidth / total)
+	return fmt.Sprintf("Indexing %s [%s%s] %d%% %s/%s", name,
+		strings.Repeat("#", filled), strings.Repeat("-", progressBarWidth-filled),
+		done*100/total, index.FormatBytes(done), index.FormatBytes(total))
+}
+
+// isTerminal reports whether f is a terminal rather than a file or pipe.
+func isTerminal(f *os.File) bool {
+	stat, err := f.Stat()
+	return err == nil && stat.Mode()&os.ModeCharDevice != 0
+}
+
+// checkFile reports a path that doesn't exist or isn't a regular file
+// before any opening is attempted.
+func checkFile(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("file not found: %s", path)
+		}
+		return fmt.Errorf("cannot access file: %w", err)
+	}
+	if info.IsDir() {
+		return fmt.Errorf("path is a directory: %s", path)
+	}
+	return nil
+}
+
+// byModTime returns paths ordered by modification time, oldest first, so
+// rotated logs such as app.log.2, app.log.1, app.log read in the order
+// they were written. Files with the same time keep their argument order.
+func byModTime(paths []string) ([]string, error) {
+	modTimes := make(map[string]time.Time, len(paths))
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, fmt.Errorf("cannot access file: %w", err)
+		}
+		modTimes[path] = info.ModTime()
+	}
+	sorted := slices.Clone(paths)
+	slices.SortStableFunc(sorted, func(a, b string) int {
+		return modTimes[a].Compare(modTimes[b])
+	})
+	return sorted, nil
+}
+
+// loadTheme reads the optional theme file. A missing file or one that
+// doesn't parse leaves the default colors; parse errors are only logged.
+func loadTheme(logger *slog.Logger) *tui.Theme {
+	path, err := themePath()
+	if err != nil {
+		logger.Debug("no theme path", "error", err)
+		return nil
+	}
+	theme, err := tui.LoadTheme(path)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			logger.Warn("failed to load theme, using default colors", "path", path, "error", err)
+		}
+		return nil
+	}
+	logger.Info("theme loaded", "path", path)
+	return theme
+}
+
+// themePath returns the location of the theme file,
+// ~/.config/jsonlogviewer/theme.json.
+func themePath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".config", "jsonlogviewer", "theme.json"), nil
+}
+
+// loadSession reads the optional session file. Like the theme, a missing
+// file or one that doesn't parse is ignored; parse errors are only logged.
+func loadSession(logger *slog.Logger) *tui.Session {
+	path, err := sessionPath()
+	if err != nil {
+		logger.Debug("no session path", "error", err)
+		return nil
+	}
+	session, err := tui.LoadSession(path)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			logger.Warn("failed to load session, using default columns", "path", path, "error", err)
+		}
+		return nil
+	}
+	logger.Info("session loaded", "path", path)
+	return session
+}
+
+// saveSession writes the session file for the next run. Failing to save
+// only loses the layout, so the error is logged rather than reported.
+func saveSession(logger *slog.Logger, session tui.Session) {
+	path, err := sessionPath()
+	if err != nil {
+		logger.Debug("no session path", "error", err)
+		return
+	}
+	if err := tui.SaveSession(path, session); err != nil {
+		logger.Warn("failed to save session", "path", path, "error", err)
+		return
+	}
+	logger.Info("session saved", "path", path)
+}
+
+// sessionPath returns the location of the session file,
+// ~/.config/jsonlogviewer/session.json.
+func sessionPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".config", "jsonlogviewer", "session.json"), nil
+}
+
+// validateTimeField checks that the first line has a parseable timestamp
+// at path, so a mistyped -time-field fails at startup rather than silently
+// leaving every time-based feature empty.
+func validateTimeField(idx *index.Index, path string) error {
+	line, err := idx.GetLine(1)
+	if errors.Is(err, index.ErrNotIndexed) {
+		// A stream that hasn't sent its first line yet can't be checked
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("cannot check -time-field: %w", err)
+	}
+	value := gjson.GetBytes(line, path)
+	if !value.Exists() {
+		return fmt.Errorf("-time-field %q not found in the first line", path)
+	}
+	if _, ok := parser.ParseTime(value.String()); !ok {
+		return fmt.Errorf("-time-field %q: %q in the first line is not a recognized timestamp", path, value.String())
+	}
+	return nil
+}
+
+// parseTimeRange parses -since and -until against now, leaving an empty
+// bound as the zero time, and rejects a range that ends before it starts.
+func parseTimeRange(since, until string, now time.Time) (time.Time, time.Time, error) {
+	var from, to time.Time
+	var err error
+	if since != "" {
+		if from, err = parser.ParseTimeBound(since, now); err != nil {
+			return from, to, fmt.Errorf("-since: %w", err)
+		}
+	}
+	if until != "" {
+		if to, err = parser.ParseTimeBound(until, now); err != nil {
+			return from, to, fmt.Errorf("-until: %w", err)
+		}
+	}
+	if !from.IsZero() && !to.IsZero() && to.Before(from) {
+		return from, to, fmt.Errorf("-until %s is before -since %s", to.Format(time.RFC3339), from.Format(time.RFC3339))
+	}
+	return from, to, nil
+}
+
+// detectColumns suggests extra columns from the first lines of idx; see
+// parser.DetectColumns. A file it can't read gets none.
+func detectColumns(idx *index.Index) []string {
+	count := min(idx.LineCount(), parser.DetectColumnsSample)
+	if count == 0 {
+		return nil
+	}
+	lines, err := idx.GetLines(1, count)
+	if err != nil {
+		return nil
+	}
+	return parser.DetectColumns(lines)
+}
+
+// runCount streams the input and prints its line count to stdout.
+// Progress, when enabled, goes to stderr so stdout holds only the count.
+func runCount(config Config) error {
+	inputs := []io.Reader{os.Stdin}
+	if len(config.FilePaths) > 0 {
+		inputs = inputs[:0]
+		for _, path := range config.FilePaths {
+			f, err := index.OpenStream(path)
+			if err != nil {
+				return err
+			}
+			defer func() { _ = f.Close() }()
+			inputs = append(inputs, f)
+		}
+	} else if isStdinEmpty() {
+		return fmt.Errorf("no input provided: specify a file or pipe data via stdin")
+	}
+
+	var progress *index.ProgressReporter
+	if config.Progress {
+		progress = index.NewProgressReporter(os.Stderr, index.DefaultProgressInterval)
+	}
+
+	// Each file is scanned on its own so a last line without a newline
+	// isn't joined to the next file's first line
+	count := 0
+	var err error
+	for _, r := range inputs {
+		err = index.ScanLines(r, func(line []byte, lineNum int) error {
+			count++
+			if progress != nil {
+				progress.Add(len(line))
+			}
+			return nil
+		})
+		if err != nil {
+			break
+		}
+	}
+	if progress != nil {
+		progress.Done()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to scan input: %w", err)
+	}
+
+	fmt.Println(count)
+	return nil
+}
+
+// runCheck indexes the input as the TUI would and verifies the index with
+// index.Validate, printing what was checked to stdout. A failed check is
+// returned as an error.
+func runCheck(config Config) error {
+	idx, err := openSource(config, true)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = idx.Close() }()
+
+	// Validate waits for a lazy scan, so count lines after it
+	err = idx.Validate()
+	fmt.Printf("source:  %s\n", idx.Name())
+	fmt.Printf("size:    %d bytes\n", idx.Size())
+	fmt.Printf("lines:   %d\n", idx.LineCount())
+	fmt.Printf("indexed: %s\n", idx.IndexDuration().Round(time.Microsecond))
+	if err != nil {
+		return fmt.Errorf("index check failed: %w", err)
+	}
+	fmt.Println("index:   ok")
+	return nil
+}
+
+// runPretty writes each entry of the input to stdout pretty-printed by p,
+// with a blank line between entries, skipping those outside the time
+// range. With -columns each entry is cut down to those fields first.
+// Lines that don't pretty-print, such as logfmt, are written as they are.
+func runPretty(config Config, p *parser.Parser, since, until time.Time) error {
+	columns, err := tui.ParseColumns(config.Columns)
+	if err != nil {
+		return fmt.Errorf("-columns: %w", err)
+	}
+	paths := columns.Paths()
+
+	// Every line is written, so there's nothing to gain from -lazy
+	config.Lazy = false
+	idx, err := openSource(config, true)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = idx.Close() }()
+
+	// As in the TUI, a time range hides lines that don't parse
+	timeFiltering := !since.IsZero() || !until.IsZero() || config.ExcludeUntimed
+	out := bufio.NewWriter(os.Stdout)
+	var entry parser.LogEntry
+	first := true
+	for n, count := 1, idx.LineCount(); n <= count; n++ {
+		raw, err := idx.GetLine(n)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", n, err)
+		}
+		parsed := p.ParseInto(raw, n, &entry) == nil
+		if timeFiltering && (!parsed || !parser.InTimeRange(entry.Timestamp, since, until, config.ExcludeUntimed)) {
+			continue
+		}
+
+		text := string(raw)
+		if parsed && paths != nil {
+			raw = parser.SelectFields(&entry, paths)
+		}
+		if pretty, err := p.FormatPretty(raw); err == nil {
+			text = pretty
+		}
+		if !first {
+			_ = out.WriteByte('\n')
+		}
+		first = false
+		_, _ = out.WriteString(text)
+		_ = out.WriteByte('\n')
+	}
+	return out.Flush()
+}
+
 // isStdinEmpty checks if stdin has any data available.
 func isStdinEmpty() bool {
 	stat, err := os.Stdin.Stat()
@@ -0,0 +1,199 @@
+package index
+
+import (
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/ulikunitz/xz"
+)
+
+// Compression identifies the compression format of a log file.
+type Compression int
+
+const (
+	// CompressionNone is an uncompressed file.
+	CompressionNone Compression = iota
+	// CompressionBzip2 is a bzip2 stream (magic "BZh").
+	CompressionBzip2
+	// CompressionXz is an xz stream (magic FD 37 7A 58 5A 00).
+	CompressionXz
+	// CompressionGzip is a gzip stream (magic 1F 8B).
+	CompressionGzip
+)
+
+// String returns the conventional name of the compression format.
+func (c Compression) String() string {
+	switch c {
+	case CompressionBzip2:
+		return "bzip2"
+	case CompressionXz:
+		return "xz"
+	case CompressionGzip:
+		return "gzip"
+	default:
+		return "none"
+	}
+}
+
+var (
+	bzip2Magic = []byte("BZh")
+	xzMagic    = []byte{0xFD, '7', 'z', 'X', 'Z', 0x00}
+	gzipMagic  = []byte{0x1F, 0x8B}
+)
+
+// DetectCompression reports the compression format of the file at path.
+// The magic bytes at the start of the file are checked first; the file
+// suffix is used as a fallback so a mislabeled file still gets a clear
+// decompression error rather than being shown as binary garbage.
+func DetectCompression(path string) (Compression, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return CompressionNone, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	header := make([]byte, len(xzMagic))
+	n, err := io.ReadFull(f, header)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return CompressionNone, fmt.Errorf("failed to read file header: %w", err)
+	}
+
+	return detectCompression(header[:n], path), nil
+}
+
+// detectCompression identifies the compression format from the file header,
+// falling back to the path suffix.
+func detectCompression(header []byte, path string) Compression {
+	switch {
+	case bytes.HasPrefix(header, xzMagic):
+		return CompressionXz
+	case bytes.HasPrefix(header, gzipMagic):
+		return CompressionGzip
+	case len(header) > len(bzip2Magic) && bytes.HasPrefix(header, bzip2Magic) &&
+		header[3] >= '1' && header[3] <= '9':
+		// The byte after "BZh" is the block size, '1' through '9'
+		return CompressionBzip2
+	}
+
+	switch {
+	case strings.HasSuffix(path, ".xz"):
+		return CompressionXz
+	case strings.HasSuffix(path, ".bz2"):
+		return CompressionBzip2
+	case strings.HasSuffix(path, ".gz"):
+		return CompressionGzip
+	}
+	return CompressionNone
+}
+
+// OpenCompressed decompresses the file at path into memory and builds the
+// line offset index. Compressed data can't be memory-mapped, so the whole
+// decompressed stream is held in memory.
+//
+// If maxBytes is positive, at most maxBytes of decompressed data are read
+// and the data is cut back to the last complete line. Pass 0 to read
+// everything.
+// The caller must call Close when done.
+func OpenCompressed(path string, maxBytes int64) (*Index, error) {
+	format, err := DetectCompression(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if format == CompressionNone {
+		return nil, fmt.Errorf("%s is not a compressed file", path)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	r, err := decompressor(f, format, path)
+	if err != nil {
+		return nil, err
+	}
+
+	if maxBytes > 0 {
+		r = io.LimitReader(r, maxBytes)
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress %s stream in %s: %w", format, path, err)
+	}
+
+	// Don't index a partial trailing line when the cap cut the stream short
+	if maxBytes > 0 && int64(len(data)) == maxBytes {
+		if i := bytes.LastIndexByte(data, '\n'); i >= 0 {
+			data = data[:i+1]
+		}
+	}
+
+	idx := &Index{
+		data:    data,
+		offsets: make([]uint64, 0, 1024),
+		reader:  nil, // Decompressed data lives in memory
+		name:    path,
+	}
+
+	if err := idx.buildOffsets(); err != nil {
+		return nil, err
+	}
+
+	return idx, nil
+}
+
+// decompressor wraps r in a reader for the given compression format.
+func decompressor(r io.Reader, format Compression, path string) (io.Reader, error) {
+	switch format {
+	case CompressionBzip2:
+		return bzip2.NewReader(r), nil
+	case CompressionXz:
+		xr, err := xz.NewReader(r)
+		if err != nil {
+			return nil, fmt.Errorf("invalid xz stream in %s: %w", path, err)
+		}
+		return xr, nil
+	case CompressionGzip:
+		gr, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, fmt.Errorf("invalid gzip stream in %s: %w", path, err)
+		}
+		return gr, nil
+	default:
+		return r, nil
+	}
+}
+
+// OpenStream opens the file at path for a single sequential read,
+// decompressing it on the fly if needed. Unlike Open it builds no index and
+// holds nothing in memory, which suits one-pass scans of huge files.
+// The caller must close the returned reader.
+func OpenStream(path string) (io.ReadCloser, error) {
+	format, err := DetectCompression(path)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+
+	r, err := decompressor(f, format, path)
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	return struct {
+		io.Reader
+		io.Closer
+	}{r, f}, nil
+}
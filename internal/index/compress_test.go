@@ -0,0 +1,172 @@
+package index
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// sampleLines is the content of the testdata/sample.ndjson.* fixtures.
+var sampleLines = []string{
+	`{"time":"2024-01-15T10:30:00Z","level":"info","msg":"first"}`,
+	`{"time":"2024-01-15T10:30:01Z","level":"warn","msg":"second"}`,
+	`{"time":"2024-01-15T10:30:02Z","level":"error","msg":"third"}`,
+}
+
+// TestDetectCompression verifies magic-byte and suffix detection.
+func TestDetectCompression(t *testing.T) {
+	tests := []struct {
+		name   string
+		header []byte
+		path   string
+		want   Compression
+	}{
+		{"bzip2 magic", []byte("BZh91AY&SY"), "app.log", CompressionBzip2},
+		{"xz magic", []byte{0xFD, '7', 'z', 'X', 'Z', 0x00}, "app.log", CompressionXz},
+		{"gzip magic", []byte{0x1F, 0x8B, 0x08, 0x00}, "app.log.1", CompressionGzip},
+		{"bzip2 suffix", []byte("garbage"), "app.log.bz2", CompressionBzip2},
+		{"xz suffix", []byte("garbage"), "app.log.xz", CompressionXz},
+		{"gzip suffix", []byte("garbage"), "app.log.1.gz", CompressionGzip},
+		{"plain json", []byte(`{"time"`), "app.log", CompressionNone},
+		{"text starting with BZh", []byte("BZh is not"), "app.log", CompressionNone},
+		{"short file", []byte("{}"), "app.log", CompressionNone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := detectCompression(tt.header, tt.path); got != tt.want {
+				t.Errorf("detectCompression: expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+// TestOpenCompressed verifies compressed fixtures decompress to the
+// original lines through Open, OpenFile, and OpenCompressed.
+func TestOpenCompressed(t *testing.T) {
+	openers := map[string]func(string) (*Index, error){
+		"Open":           func(p string) (*Index, error) { return Open(p) },
+		"OpenFile":       func(p string) (*Index, error) { return OpenFile(p) },
+		"OpenCompressed": func(p string) (*Index, error) { return OpenCompressed(p, 0) },
+	}
+
+	for _, fixture := range []string{"sample.ndjson.bz2", "sample.ndjson.xz", "sample.ndjson.gz"} {
+		for name, open := range openers {
+			t.Run(fixture+"/"+name, func(t *testing.T) {
+				idx, err := open(filepath.Join("testdata", fixture))
+				if err != nil {
+					t.Fatalf("%s failed: %v", name, err)
+				}
+				defer closeIndex(idx)
+
+				if idx.LineCount() != len(sampleLines) {
+					t.Fatalf("expected %d lines, got %d", len(sampleLines), idx.LineCount())
+				}
+				for i, want := range sampleLines {
+					got, err := idx.GetLineString(i + 1)
+					if err != nil {
+						t.Fatalf("GetLine(%d) failed: %v", i+1, err)
+					}
+					if got != want {
+						t.Errorf("line %d: expected %q, got %q", i+1, want, got)
+					}
+				}
+			})
+		}
+	}
+}
+
+// TestOpenCompressedMaxBytes verifies the decompression cap keeps only
+// complete lines.
+func TestOpenCompressedMaxBytes(t *testing.T) {
+	// Enough for the first line plus part of the second
+	limit := int64(len(sampleLines[0]) + 10)
+
+	idx, err := OpenCompressed(filepath.Join("testdata", "sample.ndjson.xz"), limit)
+	if err != nil {
+		t.Fatalf("OpenCompressed failed: %v", err)
+	}
+	defer closeIndex(idx)
+
+	if idx.LineCount() != 1 {
+		t.Fatalf("expected 1 complete line, got %d", idx.LineCount())
+	}
+	if got, _ := idx.GetLineString(1); got != sampleLines[0] {
+		t.Errorf("expected %q, got %q", sampleLines[0], got)
+	}
+}
+
+// TestOpenCompressedCorrupt verifies a damaged stream reports an error.
+func TestOpenCompressedCorrupt(t *testing.T) {
+	for _, name := range []string{"bad.bz2", "bad.xz", "bad.gz"} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), name)
+			if err := os.WriteFile(path, []byte("definitely not compressed\n"), 0644); err != nil {
+				t.Fatalf("failed to write file: %v", err)
+			}
+
+			_, err := Open(path)
+			if err == nil {
+				t.Fatal("expected an error for a corrupt stream")
+			}
+			if !strings.Contains(err.Error(), path) {
+				t.Errorf("expected error to name the file, got %v", err)
+			}
+		})
+	}
+}
+
+// TestOpenStream verifies plain and compressed files stream the same lines.
+func TestOpenStream(t *testing.T) {
+	plain := createTestFile(t, strings.Join(sampleLines, "\n")+"\n")
+	paths := []string{
+		plain,
+		filepath.Join("testdata", "sample.ndjson.bz2"),
+		filepath.Join("testdata", "sample.ndjson.xz"),
+		filepath.Join("testdata", "sample.ndjson.gz"),
+	}
+
+	for _, path := range paths {
+		t.Run(filepath.Base(path), func(t *testing.T) {
+			r, err := OpenStream(path)
+			if err != nil {
+				t.Fatalf("OpenStream failed: %v", err)
+			}
+			defer func() { _ = r.Close() }()
+
+			var got []string
+			err = ScanLines(r, func(line []byte, lineNum int) error {
+				got = append(got, string(line))
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("ScanLines failed: %v", err)
+			}
+			if strings.Join(got, "\n") != strings.Join(sampleLines, "\n") {
+				t.Errorf("expected sample lines, got %q", got)
+			}
+		})
+	}
+}
+
+// TestOpenGzipTruncated verifies a gzip stream cut short reports an error
+// naming the file instead of returning partial data.
+func TestOpenGzipTruncated(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "sample.ndjson.gz"))
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "cut.log.gz")
+	if err := os.WriteFile(path, data[:len(data)-12], 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	_, err = Open(path)
+	if err == nil {
+		t.Fatal("expected an error for a truncated stream")
+	}
+	if !strings.Contains(err.Error(), path) {
+		t.Errorf("expected error to name the file, got %v", err)
+	}
+}
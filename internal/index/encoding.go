@@ -0,0 +1,114 @@
+package index
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"golang.org/x/text/encoding/unicode"
+)
+
+// Encoding is the text encoding of a log's data.
+type Encoding int
+
+const (
+	// EncodingAuto reads UTF-8 unless the data starts with a UTF-16 byte
+	// order mark.
+	EncodingAuto Encoding = iota
+	// EncodingUTF8 reads the data as UTF-8.
+	EncodingUTF8
+	// EncodingUTF16LE reads the data as little-endian UTF-16, as written
+	// by most Windows tools.
+	EncodingUTF16LE
+	// EncodingUTF16BE reads the data as big-endian UTF-16.
+	EncodingUTF16BE
+)
+
+// encodingNames are the names ParseEncoding accepts, indexed by Encoding.
+var encodingNames = [...]string{"auto", "utf-8", "utf-16le", "utf-16be"}
+
+// String returns the encoding's name as accepted by ParseEncoding.
+func (e Encoding) String() string {
+	if e < 0 || int(e) >= len(encodingNames) {
+		return fmt.Sprintf("Encoding(%d)", int(e))
+	}
+	return encodingNames[e]
+}
+
+// ParseEncoding returns the Encoding named name: "auto", "utf-8",
+// "utf-16le", or "utf-16be", in any case and with or without the hyphen.
+func ParseEncoding(name string) (Encoding, error) {
+	name = strings.ToLower(name)
+	for i, n := range encodingNames {
+		if name == n || name == strings.Replace(n, "-", "", 1) {
+			return Encoding(i), nil
+		}
+	}
+	return EncodingAuto, fmt.Errorf("unknown encoding %q (want auto, utf-8, utf-16le, or utf-16be)", name)
+}
+
+// WithEncoding reads data without a byte order mark as enc, for UTF-16
+// logs written without one. A byte order mark always wins.
+func WithEncoding(enc Encoding) OpenOption {
+	return func(idx *Index) {
+		idx.encoding = enc
+	}
+}
+
+// Byte order marks, as written at the start of a file.
+var (
+	bomUTF8    = []byte{0xEF, 0xBB, 0xBF}
+	bomUTF16LE = []byte{0xFF, 0xFE}
+	bomUTF16BE = []byte{0xFE, 0xFF}
+)
+
+// detectBOM returns the encoding named by the byte order mark data starts
+// with and the mark's length, or EncodingAuto and 0 when there's none.
+func detectBOM(data []byte) (Encoding, int) {
+	switch {
+	case bytes.HasPrefix(data, bomUTF8):
+		return EncodingUTF8, len(bomUTF8)
+	case bytes.HasPrefix(data, bomUTF16LE):
+		return EncodingUTF16LE, len(bomUTF16LE)
+	case bytes.HasPrefix(data, bomUTF16BE):
+		return EncodingUTF16BE, len(bomUTF16BE)
+	}
+	return EncodingAuto, 0
+}
+
+// isUTF16 reports whether enc is one of the UTF-16 encodings.
+func isUTF16(enc Encoding) bool {
+	return enc == EncodingUTF16LE || enc == EncodingUTF16BE
+}
+
+// decode prepares idx.data for indexing, so the offsets index the text as
+// it's shown: a byte order mark is dropped and UTF-16 is transcoded to
+// UTF-8, leaving idx.encoding set to the encoding found. Transcoded data
+// no longer matches the file byte for byte, so the index can't be
+// refreshed.
+func (idx *Index) decode() {
+	enc, bom := detectBOM(idx.data)
+	if bom == 0 {
+		enc = idx.encoding
+	}
+	idx.data = idx.data[bom:]
+	idx.bom, idx.encoding = bom, enc
+	if isUTF16(enc) {
+		idx.data = decodeUTF16(idx.data, enc)
+		idx.path = ""
+	}
+}
+
+// decodeUTF16 transcodes UTF-16 data in enc to UTF-8. Unpaired surrogates
+// and an odd trailing byte become U+FFFD.
+func decodeUTF16(data []byte, enc Encoding) []byte {
+	order := unicode.LittleEndian
+	if enc == EncodingUTF16BE {
+		order = unicode.BigEndian
+	}
+	// Any byte order mark was dropped by decode, so the decoder is told
+	// the order. It replaces invalid input rather than failing, so there
+	// is no error to report.
+	out, _ := unicode.UTF16(order, unicode.IgnoreBOM).NewDecoder().Bytes(data)
+	return out
+}
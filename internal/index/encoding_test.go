@@ -0,0 +1,147 @@
+package index
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"unicode/utf16"
+)
+
+// encodingLines are the lines the encoding tests write in each encoding.
+var encodingLines = []string{`{"msg":"héllo"}`, `{"msg":"日本 🙂"}`}
+
+// utf16Content encodes lines as UTF-16 in enc with CRLF line endings, as
+// Windows tools write them, after bom.
+func utf16Content(enc Encoding, bom []byte, lines []string) string {
+	var order binary.AppendByteOrder = binary.LittleEndian
+	if enc == EncodingUTF16BE {
+		order = binary.BigEndian
+	}
+	buf := append([]byte(nil), bom...)
+	for _, u := range utf16.Encode([]rune(strings.Join(lines, "\r\n") + "\r\n")) {
+		buf = order.AppendUint16(buf, u)
+	}
+	return string(buf)
+}
+
+// checkLines fails unless idx holds want, ignoring CRs left from CRLF.
+func checkLines(t *testing.T, idx *Index, want []string) {
+	t.Helper()
+	if idx.LineCount() != len(want) {
+		t.Fatalf("expected %d lines, got %d", len(want), idx.LineCount())
+	}
+	for i, w := range want {
+		line, err := idx.GetLine(i + 1)
+		if err != nil {
+			t.Fatalf("GetLine(%d) failed: %v", i+1, err)
+		}
+		if got := string(bytes.TrimSuffix(line, []byte("\r"))); got != w {
+			t.Errorf("line %d: expected %q, got %q", i+1, w, got)
+		}
+	}
+}
+
+// TestUTF8BOM verifies a UTF-8 byte order mark is dropped from the first
+// line, with offsets still reported in the file and follow still working.
+func TestUTF8BOM(t *testing.T) {
+	path := createTestFile(t, string(bomUTF8)+strings.Join(encodingLines, "\n")+"\n")
+	idx, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer closeIndex(idx)
+	checkLines(t, idx, encodingLines)
+
+	if off, err := idx.LineOffset(2); err != nil || off != uint64(len(bomUTF8)+len(encodingLines[0])+1) {
+		t.Errorf("expected line 2 at its file offset, got %d, %v", off, err)
+	}
+
+	appendFile(t, path, `{"msg":"more"}`+"\n")
+	if added, err := idx.Refresh(); err != nil || added != 1 {
+		t.Fatalf("expected Refresh to add 1 line, got %d, %v", added, err)
+	}
+	checkLines(t, idx, append(encodingLines[:2:2], `{"msg":"more"}`))
+}
+
+// TestUTF16 verifies UTF-16 files with a byte order mark are transcoded
+// by every opener, that -encoding covers files without one, and that a
+// transcoded index can't be refreshed.
+func TestUTF16(t *testing.T) {
+	le := createTestFile(t, utf16Content(EncodingUTF16LE, bomUTF16LE, encodingLines))
+	be := createTestFile(t, utf16Content(EncodingUTF16BE, bomUTF16BE, encodingLines))
+	bare := createTestFile(t, utf16Content(EncodingUTF16BE, nil, encodingLines))
+
+	openers := map[string]func() (*Index, error){
+		"Open LE":          func() (*Index, error) { return Open(le) },
+		"Open BE":          func() (*Index, error) { return Open(be) },
+		"OpenFile LE":      func() (*Index, error) { return OpenFile(le) },
+		"OpenLazy LE":      func() (*Index, error) { return OpenLazy(le, 1) },
+		"OpenTail LE":      func() (*Index, error) { return OpenTail(le, 2, 0) },
+		"Open without BOM": func() (*Index, error) { return Open(bare, WithEncoding(EncodingUTF16BE)) },
+		"OpenIndexed without BOM": func() (*Index, error) {
+			idx, _, err := OpenIndexed(bare, WithEncoding(EncodingUTF16BE))
+			return idx, err
+		},
+	}
+	for name, open := range openers {
+		t.Run(name, func(t *testing.T) {
+			idx, err := open()
+			if err != nil {
+				t.Fatalf("open failed: %v", err)
+			}
+			defer closeIndex(idx)
+			checkLines(t, idx, encodingLines)
+			if _, err := idx.Refresh(); !errors.Is(err, ErrNotRefreshable) {
+				t.Errorf("expected ErrNotRefreshable, got %v", err)
+			}
+		})
+	}
+}
+
+// TestReaderStreamBOM verifies stdin drops a UTF-8 byte order mark and
+// transcodes UTF-16.
+func TestReaderStreamBOM(t *testing.T) {
+	for name, content := range map[string]string{
+		"UTF-8":    string(bomUTF8) + strings.Join(encodingLines, "\n") + "\n",
+		"UTF-16LE": utf16Content(EncodingUTF16LE, bomUTF16LE, encodingLines),
+	} {
+		t.Run(name, func(t *testing.T) {
+			idx := OpenReaderStream(io.NopCloser(strings.NewReader(content)), "stdin")
+			defer closeIndex(idx)
+			if err := idx.waitIndexed(); err != nil {
+				t.Fatalf("waitIndexed failed: %v", err)
+			}
+			checkLines(t, idx, encodingLines)
+		})
+	}
+}
+
+// TestDecodeUTF16 verifies surrogate pairs decode, and that unpaired
+// surrogates and an odd trailing byte become U+FFFD.
+func TestDecodeUTF16(t *testing.T) {
+	data := []byte{0x3D, 0xD8, 0x42, 0xDE, 'a', 0, 0x00, 0xD8, 'b', 0, 'c'}
+	if got, want := string(decodeUTF16(data, EncodingUTF16LE)), "🙂a�b�"; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+// TestParseEncoding verifies the -encoding names, with or without the
+// hyphen, and rejects unknown ones.
+func TestParseEncoding(t *testing.T) {
+	for name, want := range map[string]Encoding{
+		"auto":     EncodingAuto,
+		"UTF-8":    EncodingUTF8,
+		"utf16le":  EncodingUTF16LE,
+		"utf-16be": EncodingUTF16BE,
+	} {
+		if got, err := ParseEncoding(name); err != nil || got != want {
+			t.Errorf("ParseEncoding(%q) = %v, %v; want %v", name, got, err, want)
+		}
+	}
+	if _, err := ParseEncoding("latin1"); err == nil {
+		t.Error("expected an error for an unsupported encoding")
+	}
+}
@@ -0,0 +1,155 @@
+package index
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+// framing is how the records of a log are laid out in its data.
+type framing int
+
+const (
+	// framingLines is NDJSON: one record per line.
+	framingLines framing = iota
+	// framingArray is a single top-level JSON array with one record per
+	// element.
+	framingArray
+	// framingObjects is a stream of JSON objects that may each span
+	// several lines, such as pretty-printed logs.
+	framingObjects
+)
+
+// detectFraming picks the framing of data from its first line, so NDJSON
+// keeps the fast newline scan. A first line opening an array it doesn't
+// close means the records span lines; an array that makes up the whole
+// data is also read element by element, even on one line. An object
+// spanning lines must be valid JSON and end its last line, so NDJSON whose
+// first line was cut off isn't taken for one huge record.
+func detectFraming(data []byte) framing {
+	start := skipSpace(data, 0)
+	if start == len(data) || (data[start] != '[' && data[start] != '{') {
+		return framingLines
+	}
+
+	lineEnd := len(data)
+	if nl := bytes.IndexByte(data[start:], '\n'); nl >= 0 {
+		lineEnd = start + nl
+	}
+	end := valueEnd(data, start, "")
+	closed := end <= lineEnd
+
+	switch {
+	case data[start] == '[' && (!closed || skipSpace(data, end) == len(data)):
+		return framingArray
+	case data[start] == '{' && !closed && json.Valid(data[start:end]):
+		if rest := bytes.TrimLeft(data[end:], " \t\r"); len(rest) == 0 || rest[0] == '\n' {
+			return framingObjects
+		}
+	}
+	return framingLines
+}
+
+// buildRecords indexes framed data from offset from onward, recording
+// where each record starts and ends. Whitespace between records, and the
+// commas and brackets around array elements, belong to no record.
+func (idx *Index) buildRecords(from int) {
+	data := idx.data
+	delims := "\n"
+	i := from
+	if idx.framing == framingArray {
+		delims = ","
+		if from == 0 {
+			i = skipSpace(data, 0) + 1 // past the opening [
+		}
+	}
+
+	for {
+		for i < len(data) && (isSpace(data[i]) || idx.framing == framingArray && data[i] == ',') {
+			i++
+		}
+		if i >= len(data) || idx.framing == framingArray && data[i] == ']' {
+			return
+		}
+
+		end := valueEnd(data, i, delims)
+		if end <= i {
+			// A stray closing bracket becomes a record of its own
+			end = i + 1
+		}
+		trimmed := end
+		for trimmed > i && isSpace(data[trimmed-1]) {
+			trimmed--
+		}
+		idx.offsets = append(idx.offsets, uint64(i))
+		idx.ends = append(idx.ends, uint64(trimmed))
+		i = end
+	}
+}
+
+// refreshRecords re-indexes framed data after bytes were appended from
+// offset oldLen. The last record is scanned again since it may have been
+// cut off by the end of the old data.
+func (idx *Index) refreshRecords(oldLen int) {
+	from := oldLen
+	if n := len(idx.offsets); n > 0 {
+		from = int(idx.offsets[n-1])
+		idx.offsets = idx.offsets[:n-1]
+		idx.ends = idx.ends[:n-1]
+	}
+	idx.buildRecords(from)
+}
+
+// valueEnd returns the end of the JSON value starting at data[i]. An
+// object or array ends after its matching close; brackets inside strings
+// don't count. Anything else ends at the first byte of delims outside a
+// string, or at a closing bracket belonging to an enclosing array.
+// Unterminated values run to the end of data.
+func valueEnd(data []byte, i int, delims string) int {
+	depth := 0
+	inString := false
+	for ; i < len(data); i++ {
+		c := data[i]
+		if inString {
+			switch c {
+			case '\\':
+				i++
+			case '"':
+				inString = false
+			}
+			continue
+		}
+
+		switch {
+		case c == '"':
+			inString = true
+		case c == '{' || c == '[':
+			depth++
+		case c == '}' || c == ']':
+			if depth == 0 {
+				return i
+			}
+			depth--
+			if depth == 0 {
+				return i + 1
+			}
+		case depth == 0 && strings.IndexByte(delims, c) >= 0:
+			return i
+		}
+	}
+	return len(data)
+}
+
+// skipSpace returns the index of the first non-whitespace byte of data at
+// or after i.
+func skipSpace(data []byte, i int) int {
+	for i < len(data) && isSpace(data[i]) {
+		i++
+	}
+	return i
+}
+
+// isSpace reports whether c is JSON whitespace.
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
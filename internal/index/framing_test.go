@@ -0,0 +1,151 @@
+package index
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestDetectFraming verifies NDJSON keeps the newline path and arrays and
+// multi-line objects are recognized from the first line.
+func TestDetectFraming(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want framing
+	}{
+		{"ndjson", "{\"a\":1}\n{\"a\":2}\n", framingLines},
+		{"plain text", "hello\nworld\n", framingLines},
+		{"ndjson arrays", "[1,2]\n[3,4]\n", framingLines},
+		{"multi-line array", "[\n  {\"a\":1},\n  {\"a\":2}\n]\n", framingArray},
+		{"one-line array", "  [{\"a\":1},{\"a\":2}]\n", framingArray},
+		{"pretty objects", "{\n  \"a\": 1\n}\n{\n  \"a\": 2\n}\n", framingObjects},
+		{"brace in string", "{\"a\":\"}\"}\n{\"a\":2}\n", framingLines},
+		{"one pretty object", "{\n  \"a\": 1\n}\n", framingObjects},
+		{"cut first line", "{\"a\":1,\"b\":\n{\"a\":2}\n{\"a\":3}\n{\"a\":4}\n", framingLines},
+		{"cut first line that balances", "{\"a\":{\"b\":1,\n{\"a\":2}}\n{\"a\":3}\n", framingLines},
+		{"object sharing its last line", "{\"a\":\n1} {\"a\":2}\n", framingLines},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := detectFraming([]byte(tt.data)); got != tt.want {
+				t.Errorf("expected framing %d, got %d", tt.want, got)
+			}
+		})
+	}
+}
+
+// TestFramedRecords verifies each array element or multi-line object is
+// one line of the index, with separators and surrounding whitespace left
+// out and brackets inside strings ignored.
+func TestFramedRecords(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want []string
+	}{
+		{
+			"array",
+			"[\n  {\"msg\":\"a\"},\n  {\"msg\":\"b, ]\"},\n  {\"msg\":\"c\"}\n]\n",
+			[]string{`{"msg":"a"}`, `{"msg":"b, ]"}`, `{"msg":"c"}`},
+		},
+		{
+			"array of scalars",
+			`[1, "two", null]`,
+			[]string{`1`, `"two"`, `null`},
+		},
+		{
+			"pretty objects",
+			"{\n  \"msg\": \"a\",\n  \"err\": {\"x\": \"\\\"}\"}\n}\n{\n  \"msg\": \"b\"\n}\n",
+			[]string{"{\n  \"msg\": \"a\",\n  \"err\": {\"x\": \"\\\"}\"}\n}", "{\n  \"msg\": \"b\"\n}"},
+		},
+		{
+			"objects with a stray line",
+			"{\n  \"msg\": \"a\"\n}\nnot json\n{\"msg\":\"b\"}",
+			[]string{"{\n  \"msg\": \"a\"\n}", "not json", `{"msg":"b"}`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			idx, err := OpenReader(strings.NewReader(tt.data), "test")
+			if err != nil {
+				t.Fatalf("OpenReader failed: %v", err)
+			}
+			defer closeIndex(idx)
+
+			var got []string
+			for n := 1; n <= idx.LineCount(); n++ {
+				line, err := idx.GetLineString(n)
+				if err != nil {
+					t.Fatalf("GetLine(%d) failed: %v", n, err)
+				}
+				got = append(got, line)
+			}
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("expected records %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+// TestFramedEmptyArray verifies an array with no elements is empty.
+func TestFramedEmptyArray(t *testing.T) {
+	if _, err := OpenReader(strings.NewReader("[ ]\n"), "test"); !errors.Is(err, ErrEmptyFile) {
+		t.Errorf("expected ErrEmptyFile, got %v", err)
+	}
+}
+
+// TestRefreshFramed verifies appended multi-line objects are indexed,
+// including one that was only partly written at the previous refresh.
+func TestRefreshFramed(t *testing.T) {
+	path := createTestFile(t, "{\n  \"msg\": \"a\"\n}\n")
+	idx, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer closeIndex(idx)
+
+	appendFile(t, path, "{\n  \"msg\": ")
+	if _, err := idx.Refresh(); err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+	appendFile(t, path, "\"b\"\n}\n{\"msg\":\"c\"}\n")
+	if _, err := idx.Refresh(); err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+
+	want := []string{"{\n  \"msg\": \"a\"\n}", "{\n  \"msg\": \"b\"\n}", `{"msg":"c"}`}
+	if idx.LineCount() != len(want) {
+		t.Fatalf("expected %d records, got %d", len(want), idx.LineCount())
+	}
+	for n, w := range want {
+		if got, _ := idx.GetLineString(n + 1); got != w {
+			t.Errorf("record %d: expected %q, got %q", n+1, w, got)
+		}
+	}
+}
+
+// TestOpenLazyFramed verifies OpenLazy indexes framed data in full rather
+// than by newlines, and that framed indexes can't be saved.
+func TestOpenLazyFramed(t *testing.T) {
+	setLazyChunkSize(t, 8)
+	path := createTestFile(t, "[\n  {\"msg\":\"a\"},\n  {\"msg\":\"b\"}\n]\n")
+
+	idx, err := OpenLazy(path, 1)
+	if err != nil {
+		t.Fatalf("OpenLazy failed: %v", err)
+	}
+	defer closeIndex(idx)
+
+	if done, lines := idx.IndexProgress(); !done || lines != 2 {
+		t.Errorf("expected 2 records indexed up front, got (%v, %d)", done, lines)
+	}
+	if got, _ := idx.GetLineString(2); got != `{"msg":"b"}` {
+		t.Errorf("expected the second element, got %q", got)
+	}
+	if err := idx.SaveIndex(SidecarPath(path)); err == nil {
+		t.Error("expected saving a framed index to fail")
+	}
+}
@@ -5,10 +5,13 @@ package index
 
 import (
 	"bufio"
+	"bytes"
 	"errors"
 	"fmt"
 	"io"
 	"os"
+	"sync"
+	"time"
 
 	"golang.org/x/exp/mmap"
 )
@@ -26,14 +29,72 @@ var (
 type Index struct {
 	data    []byte    // Memory-mapped file data
 	offsets []uint64  // Line start offsets (8 bytes per line)
+	ends    []uint64  // Record end offsets for framed data; nil for NDJSON
+	framing framing   // How records are laid out in data
 	reader  io.Closer // Underlying reader for cleanup
 	name    string    // File name for error messages
+	path    string    // Plain file backing data, for Refresh; empty if none
+
+	indexTime time.Duration // How long building the line index took
+	progress  ProgressFunc  // Called while building the line index; may be nil
+	encoding  Encoding      // Encoding of the data, as set or detected
+	bom       int           // Length of the byte order mark dropped from data
+
+	mu   sync.RWMutex // Guards offsets while a lazy scan extends them
+	lazy *lazyScan    // Background scan state for OpenLazy; nil otherwise
+
+	parts  []*Index // Files of an OpenMulti index, in order; nil otherwise
+	starts []int    // Lines before each part, then the total line count
 }
 
+// ProgressFunc is told how many of the total bytes have been scanned while
+// an index is built. The last call has done equal to total.
+type ProgressFunc func(done, total int64)
+
+// OpenOption configures how Open and OpenIndexed build an index.
+type OpenOption func(*Index)
+
+// WithProgress reports progress to fn while the line index is built, so a
+// multi-gigabyte file doesn't look hung. fn is called about every
+// progressStep bytes, on the goroutine doing the scan.
+func WithProgress(fn ProgressFunc) OpenOption {
+	return func(idx *Index) {
+		idx.progress = fn
+	}
+}
+
+// progressStep is how many bytes buildOffsets scans between progress
+// reports. It's a variable so tests can see several reports on a small
+// file.
+var progressStep = 16 << 20
+
 // Open memory-maps the file at the given path and builds an index of line offsets.
+// Compressed files (see DetectCompression) are decompressed into memory instead.
 // Returns an error if the file cannot be opened or mapped.
 // The caller must call Close when done to unmap the file.
-func Open(path string) (*Index, error) {
+func Open(path string, opts ...OpenOption) (*Index, error) {
+	if format, err := DetectCompression(path); err == nil && format != CompressionNone {
+		return OpenCompressed(path, 0)
+	}
+
+	idx, err := mapFile(path)
+	if err != nil {
+		return nil, err
+	}
+	for _, opt := range opts {
+		opt(idx)
+	}
+
+	if err := idx.buildOffsets(); err != nil {
+		_ = idx.Close()
+		return nil, err
+	}
+
+	return idx, nil
+}
+
+// mapFile memory-maps the file at path without building the offset index.
+func mapFile(path string) (*Index, error) {
 	readerAt, err := mmap.Open(path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to mmap file: %w", err)
@@ -45,25 +106,19 @@ func Open(path string) (*Index, error) {
 		return nil, fmt.Errorf("failed to read mmap data: %w", err)
 	}
 
-	idx := &Index{
+	return &Index{
 		data:    data,
 		offsets: make([]uint64, 0, 1024),
 		reader:  readerAt,
 		name:    path,
-	}
-
-	if err := idx.buildOffsets(); err != nil {
-		_ = readerAt.Close()
-		return nil, err
-	}
-
-	return idx, nil
+		path:    path,
+	}, nil
 }
 
 // OpenReader creates an index from a reader (for stdin or other streams).
 // This reads all data into memory and builds the offset index.
 // The caller must call Close when done.
-func OpenReader(r io.Reader, name string) (*Index, error) {
+func OpenReader(r io.Reader, name string, opts ...OpenOption) (*Index, error) {
 	data, err := io.ReadAll(r)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read data: %w", err)
@@ -75,6 +130,9 @@ func OpenReader(r io.Reader, name string) (*Index, error) {
 		reader:  nil, // No underlying reader to close for in-memory data
 		name:    name,
 	}
+	for _, opt := range opts {
+		opt(idx)
+	}
 
 	if err := idx.buildOffsets(); err != nil {
 		return nil, err
@@ -85,32 +143,65 @@ func OpenReader(r io.Reader, name string) (*Index, error) {
 
 // OpenFile opens a regular file and reads it into memory.
 // Use this for small files where memory mapping is not needed.
+// Compressed files are decompressed transparently.
 // The caller must call Close when done.
-func OpenFile(path string) (*Index, error) {
+func OpenFile(path string, opts ...OpenOption) (*Index, error) {
+	if format, err := DetectCompression(path); err == nil && format != CompressionNone {
+		return OpenCompressed(path, 0)
+	}
+
 	f, err := os.Open(path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open file: %w", err)
 	}
 	defer func() { _ = f.Close() }()
 
-	return OpenReader(f, path)
+	idx, err := OpenReader(f, path, opts...)
+	if err != nil {
+		return nil, err
+	}
+	// Transcoded data can't be refreshed from the file
+	if !isUTF16(idx.encoding) {
+		idx.path = path
+	}
+	return idx, nil
 }
 
-// buildOffsets scans the data and builds the line offset index.
+// buildOffsets decodes the data (see decode), then scans it and builds the
+// line offset index. Data framed as a JSON array or as multi-line objects
+// is indexed record by record instead (see detectFraming).
 func (idx *Index) buildOffsets() error {
+	idx.decode()
 	if len(idx.data) == 0 {
 		return ErrEmptyFile
 	}
+	start := time.Now()
+	defer func() { idx.indexTime = time.Since(start) }()
+
+	if idx.framing = detectFraming(idx.data); idx.framing != framingLines {
+		idx.ends = make([]uint64, 0, cap(idx.offsets))
+		idx.buildRecords(0)
+		if len(idx.offsets) == 0 {
+			return ErrEmptyFile
+		}
+		idx.reportProgress(len(idx.data))
+		return nil
+	}
 
 	// First line always starts at offset 0
 	idx.offsets = append(idx.offsets, 0)
 
-	// Scan for newline characters
-	for i := 0; i < len(idx.data); i++ {
-		if idx.data[i] == '\n' && i+1 < len(idx.data) {
-			// Next line starts after the newline
-			idx.offsets = append(idx.offsets, uint64(i+1))
+	// Scan for newline characters, a step at a time so progress can be
+	// reported between steps
+	for from := 0; from < len(idx.data); from += progressStep {
+		to := min(from+progressStep, len(idx.data))
+		for i := from; i < to; i++ {
+			if idx.data[i] == '\n' && i+1 < len(idx.data) {
+				// Next line starts after the newline
+				idx.offsets = append(idx.offsets, uint64(i+1))
+			}
 		}
+		idx.reportProgress(to)
 	}
 
 	// Remove trailing empty line (file ending with newline)
@@ -121,37 +212,218 @@ func (idx *Index) buildOffsets() error {
 	return nil
 }
 
-// LineCount returns the total number of lines indexed.
+// reportProgress tells the progress callback, if any, that done bytes of
+// data have been scanned.
+func (idx *Index) reportProgress(done int) {
+	if idx.progress != nil {
+		idx.progress(int64(done), int64(len(idx.data)))
+	}
+}
+
+// LineCount returns the total number of lines indexed. While a lazy index
+// is still scanning, this is the number of lines found so far.
 func (idx *Index) LineCount() int {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return idx.lineCount()
+}
+
+// lineCount returns the number of complete lines indexed. Until a lazy scan
+// reaches the end of the file, the last line start found may belong to a
+// line whose end hasn't been scanned, so it isn't counted. The caller must
+// hold the mutex.
+func (idx *Index) lineCount() int {
+	if idx.parts != nil {
+		return idx.starts[len(idx.parts)]
+	}
+	if idx.lazy != nil && !idx.lazy.done {
+		return len(idx.offsets) - 1
+	}
 	return len(idx.offsets)
 }
 
+// Size returns the number of bytes of data indexed: the file size, or the
+// decompressed size for compressed input. A multi-file index reports the
+// total of its files.
+func (idx *Index) Size() int64 {
+	if idx.parts != nil {
+		var size int64
+		for _, part := range idx.parts {
+			size += part.Size()
+		}
+		return size
+	}
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return int64(len(idx.data))
+}
+
+// IndexDuration returns how long building the line index took, or loading
+// it from a sidecar file. A lazy index reports zero until its background
+// scan has finished, and a multi-file index reports the total of its files.
+func (idx *Index) IndexDuration() time.Duration {
+	if idx.parts != nil {
+		var d time.Duration
+		for _, part := range idx.parts {
+			d += part.IndexDuration()
+		}
+		return d
+	}
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return idx.indexTime
+}
+
 // GetLine returns the raw bytes for the specified 1-indexed line number.
-// Returns ErrInvalidLine if the line number is out of range.
+// Returns ErrInvalidLine if the line number is out of range, or
+// ErrNotIndexed if a lazy index hasn't reached it yet.
 func (idx *Index) GetLine(n int) ([]byte, error) {
-	if n < 1 || n > len(idx.offsets) {
-		return nil, ErrInvalidLine
+	if idx.parts != nil {
+		return idx.partLine(n)
+	}
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	if err := idx.checkRange(n, n); err != nil {
+		return nil, err
+	}
+	return idx.line(n), nil
+}
+
+// GetLines returns the raw bytes of lines start through end, inclusive and
+// 1-indexed, checking the range once instead of per line. The slices point
+// into the index's data without copying, so they must not be modified and
+// become invalid after Close. Returns ErrInvalidLine if the range is empty
+// or out of bounds, or ErrNotIndexed if a lazy index hasn't reached end yet.
+func (idx *Index) GetLines(start, end int) ([][]byte, error) {
+	if idx.parts != nil {
+		if start < 1 || end < start {
+			return nil, ErrInvalidLine
+		}
+		lines := make([][]byte, 0, end-start+1)
+		for n := start; n <= end; n++ {
+			line, err := idx.partLine(n)
+			if err != nil {
+				return nil, err
+			}
+			lines = append(lines, line)
+		}
+		return lines, nil
+	}
+
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	if err := idx.checkRange(start, end); err != nil {
+		return nil, err
+	}
+	lines := make([][]byte, 0, end-start+1)
+	for n := start; n <= end; n++ {
+		lines = append(lines, idx.line(n))
+	}
+	return lines, nil
+}
+
+// GetRawRange returns the raw bytes of lines start through end, inclusive
+// and 1-indexed, as one block for copying a selection: the newlines
+// between the lines are kept, while the line ending after end is left off
+// as with GetLine, so the result is the same whether or not the file ends
+// in a newline. Like GetLine it doesn't copy, except for a range spanning
+// files of a multi-file index, which is joined with newlines. It returns
+// the same errors as GetLines.
+func (idx *Index) GetRawRange(start, end int) ([]byte, error) {
+	if idx.parts != nil {
+		return idx.partRange(start, end)
+	}
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	if err := idx.checkRange(start, end); err != nil {
+		return nil, err
 	}
+	from := idx.offsets[start-1]
+	to := idx.offsets[end-1] + uint64(len(idx.line(end)))
+	return idx.data[from:to], nil
+}
 
+// GetLineReader returns a reader over the raw bytes of the specified
+// 1-indexed line, for streaming one entry into another process. Like
+// GetLine it doesn't copy: the reader reads from the index's data, so it
+// must be drained before Close unmaps the file. It returns the same errors
+// as GetLine.
+func (idx *Index) GetLineReader(n int) (io.Reader, error) {
+	line, err := idx.GetLine(n)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.NewReader(line), nil
+}
+
+// LineOffset returns the byte offset at which the specified 1-indexed line
+// starts, for reporting a position in the file. For a compressed or
+// UTF-16 file it's the offset in the decoded data, and for a multi-file
+// index it's the offset in the file holding the line. It returns the same
+// errors as GetLine.
+func (idx *Index) LineOffset(n int) (uint64, error) {
+	if idx.parts != nil {
+		part, local, err := idx.partOf(n)
+		if err != nil {
+			return 0, err
+		}
+		return part.LineOffset(local)
+	}
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	if err := idx.checkRange(n, n); err != nil {
+		return 0, err
+	}
+	if isUTF16(idx.encoding) {
+		return idx.offsets[n-1], nil
+	}
+	return idx.offsets[n-1] + uint64(idx.bom), nil
+}
+
+// checkRange reports whether lines start through end can be read. The
+// caller holds mu.
+func (idx *Index) checkRange(start, end int) error {
+	if start < 1 || end < start {
+		return ErrInvalidLine
+	}
+	if end > idx.lineCount() {
+		if idx.scanning() {
+			return ErrNotIndexed
+		}
+		return ErrInvalidLine
+	}
+	return nil
+}
+
+// line returns line n without its line ending. The caller holds mu and has
+// checked n is in range.
+func (idx *Index) line(n int) []byte {
+	// Refresh and streaming indexes replace data as it grows, so the
+	// slice read here is the one the offsets refer to
+	data := idx.data
 	start := idx.offsets[n-1]
 	var end uint64
 
-	if n < len(idx.offsets) {
+	switch {
+	case idx.ends != nil:
+		end = idx.ends[n-1]
+	case n < len(idx.offsets):
 		end = idx.offsets[n]
-		// Don't include the newline in the returned data
-		if end > 0 && idx.data[end-1] == '\n' {
-			end--
-		}
-	} else {
-		end = uint64(len(idx.data))
+	default:
+		end = uint64(len(data))
+	}
+
+	// Don't include the newline in the returned data
+	if end > start && data[end-1] == '\n' {
+		end--
 	}
 
 	// Trim trailing carriage return (Windows line endings)
-	if end > start && idx.data[end-1] == '\r' {
+	if end > start && data[end-1] == '\r' {
 		end--
 	}
 
-	return idx.data[start:end], nil
+	return data[start:end]
 }
 
 // GetLineString returns the specified line as a string.
@@ -165,8 +437,18 @@ func (idx *Index) GetLineString(n int) (string, error) {
 }
 
 // Close releases resources associated with the index.
-// For memory-mapped files, this unmaps the memory.
+// For memory-mapped files, this unmaps the memory. A lazy index's
+// background scan is stopped first, and every file of a multi-file index
+// is closed.
 func (idx *Index) Close() error {
+	if idx.parts != nil {
+		var errs []error
+		for _, part := range idx.parts {
+			errs = append(errs, part.Close())
+		}
+		return errors.Join(errs...)
+	}
+	idx.stopScan()
 	if idx.reader != nil {
 		return idx.reader.Close()
 	}
@@ -178,10 +460,15 @@ func (idx *Index) Name() string {
 	return idx.name
 }
 
+// maxScanLine is the longest line ScanLines reads; single JSON records of
+// several megabytes are common, well past bufio.Scanner's 64 KiB default.
+const maxScanLine = 1 << 30
+
 // ScanLines reads lines from a reader and calls the provided function for each line.
 // This is useful for processing files without building a full index.
 func ScanLines(r io.Reader, fn func(line []byte, lineNum int) error) error {
 	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, maxScanLine)
 	lineNum := 0
 	for scanner.Scan() {
 		lineNum++
@@ -2,6 +2,7 @@ package index
 
 import (
 	"bytes"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -40,6 +41,40 @@ func TestOpen(t *testing.T) {
 	}
 }
 
+// TestSizeAndIndexDuration verifies an index reports its data size and a
+// nonzero time spent building the line index, with a multi-file index
+// totalling its files.
+func TestSizeAndIndexDuration(t *testing.T) {
+	content := strings.Repeat(`{"level":"info","msg":"hello"}`+"\n", 1000)
+	path := createTestFile(t, content)
+
+	idx, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer closeIndex(idx)
+
+	if idx.Size() != int64(len(content)) {
+		t.Errorf("expected size %d, got %d", len(content), idx.Size())
+	}
+	if idx.IndexDuration() <= 0 {
+		t.Errorf("expected a positive index duration, got %v", idx.IndexDuration())
+	}
+
+	multi, err := OpenMulti([]string{path, createTestFile(t, "a\nb\n")})
+	if err != nil {
+		t.Fatalf("OpenMulti failed: %v", err)
+	}
+	defer closeIndex(multi)
+
+	if multi.Size() != int64(len(content))+4 {
+		t.Errorf("expected size %d, got %d", len(content)+4, multi.Size())
+	}
+	if multi.IndexDuration() <= 0 {
+		t.Errorf("expected a positive index duration, got %v", multi.IndexDuration())
+	}
+}
+
 // TestOpenEmptyFile verifies handling of empty files.
 func TestOpenEmptyFile(t *testing.T) {
 	path := createTestFile(t, "")
@@ -183,6 +218,148 @@ func TestGetLineBytes(t *testing.T) {
 	}
 }
 
+// TestGetLines verifies range reads match GetLine at the boundaries,
+// including a last line with no trailing newline, and reject bad ranges.
+func TestGetLines(t *testing.T) {
+	path := createTestFile(t, "line1\nline2\r\nline3\nline4")
+	idx, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer closeIndex(idx)
+
+	tests := []struct {
+		name       string
+		start, end int
+		expected   []string
+		wantErr    error
+	}{
+		{"single first line", 1, 1, []string{"line1"}, nil},
+		{"whole file", 1, 4, []string{"line1", "line2", "line3", "line4"}, nil},
+		{"last line without newline", 3, 4, []string{"line3", "line4"}, nil},
+		{"line zero", 0, 2, nil, ErrInvalidLine},
+		{"past the end", 3, 5, nil, ErrInvalidLine},
+		{"reversed", 3, 2, nil, ErrInvalidLine},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines, err := idx.GetLines(tt.start, tt.end)
+			if err != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if len(lines) != len(tt.expected) {
+				t.Fatalf("expected %d lines, got %d", len(tt.expected), len(lines))
+			}
+			for i, line := range lines {
+				if string(line) != tt.expected[i] {
+					t.Errorf("line %d: expected %q, got %q", tt.start+i, tt.expected[i], line)
+				}
+			}
+		})
+	}
+}
+
+// TestGetLineReader verifies the reader yields exactly the line's bytes,
+// without its line ending, and that line numbers are checked.
+func TestGetLineReader(t *testing.T) {
+	path := createTestFile(t, "line1\n{\"msg\":\"two\"}\r\nline3")
+	idx, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer closeIndex(idx)
+
+	for n, want := range map[int]string{1: "line1", 2: `{"msg":"two"}`, 3: "line3"} {
+		r, err := idx.GetLineReader(n)
+		if err != nil {
+			t.Fatalf("GetLineReader(%d) failed: %v", n, err)
+		}
+		got, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatalf("line %d: read failed: %v", n, err)
+		}
+		if string(got) != want {
+			t.Errorf("line %d: expected %q, got %q", n, want, got)
+		}
+	}
+
+	for _, n := range []int{0, 4} {
+		if _, err := idx.GetLineReader(n); err != ErrInvalidLine {
+			t.Errorf("line %d: expected ErrInvalidLine, got %v", n, err)
+		}
+	}
+}
+
+// TestGetRawRange verifies a range keeps the line endings between its
+// lines but not after its last, whether or not the file ends in a newline,
+// and that the range is checked.
+func TestGetRawRange(t *testing.T) {
+	for name, content := range map[string]string{
+		"trailing newline":    "line1\r\nline2\nline3\n",
+		"no trailing newline": "line1\r\nline2\nline3",
+	} {
+		t.Run(name, func(t *testing.T) {
+			idx, err := Open(createTestFile(t, content))
+			if err != nil {
+				t.Fatalf("Open failed: %v", err)
+			}
+			defer closeIndex(idx)
+
+			ranges := []struct {
+				start, end int
+				want       string
+			}{
+				{1, 1, "line1"},
+				{3, 3, "line3"},
+				{1, 2, "line1\r\nline2"},
+				{2, 3, "line2\nline3"},
+				{1, 3, "line1\r\nline2\nline3"},
+			}
+			for _, r := range ranges {
+				got, err := idx.GetRawRange(r.start, r.end)
+				if err != nil {
+					t.Fatalf("GetRawRange(%d, %d) failed: %v", r.start, r.end, err)
+				}
+				if string(got) != r.want {
+					t.Errorf("GetRawRange(%d, %d): expected %q, got %q", r.start, r.end, r.want, got)
+				}
+			}
+
+			for _, r := range [][2]int{{0, 1}, {2, 1}, {1, 4}} {
+				if _, err := idx.GetRawRange(r[0], r[1]); err != ErrInvalidLine {
+					t.Errorf("GetRawRange(%d, %d): expected ErrInvalidLine, got %v", r[0], r[1], err)
+				}
+			}
+		})
+	}
+}
+
+// TestLineOffset verifies each line's start offset, counting the bytes of
+// CRLF endings, and that line numbers are checked.
+func TestLineOffset(t *testing.T) {
+	path := createTestFile(t, "line1\nline2\r\n\nline4")
+	idx, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer closeIndex(idx)
+
+	for n, want := range []uint64{0, 6, 13, 14} {
+		got, err := idx.LineOffset(n + 1)
+		if err != nil {
+			t.Fatalf("LineOffset(%d) failed: %v", n+1, err)
+		}
+		if got != want {
+			t.Errorf("line %d: expected offset %d, got %d", n+1, want, got)
+		}
+	}
+	for _, n := range []int{0, 5} {
+		if _, err := idx.LineOffset(n); err != ErrInvalidLine {
+			t.Errorf("line %d: expected ErrInvalidLine, got %v", n, err)
+		}
+	}
+}
+
 // TestLineCount verifies line counting.
 func TestLineCount(t *testing.T) {
 	tests := []struct {
@@ -215,6 +392,47 @@ func TestLineCount(t *testing.T) {
 	}
 }
 
+// TestOpenFileMatchesOpen verifies reading a file into memory indexes it
+// exactly as memory-mapping it does, so -no-mmap changes nothing but how
+// the bytes are held.
+func TestOpenFileMatchesOpen(t *testing.T) {
+	for _, content := range []string{
+		"single",
+		"line1\nline2\n",
+		"line1\r\nline2\r\nline3",
+		"\n\n\n",
+		"[\n  {\"a\":1},\n  {\"a\":2}\n]\n",
+	} {
+		path := createTestFile(t, content)
+		mapped, err := Open(path)
+		if err != nil {
+			t.Fatalf("Open failed for %q: %v", content, err)
+		}
+		read, err := OpenFile(path)
+		if err != nil {
+			t.Fatalf("OpenFile failed for %q: %v", content, err)
+		}
+
+		if mapped.LineCount() != read.LineCount() {
+			t.Errorf("content %q: Open has %d lines, OpenFile %d", content, mapped.LineCount(), read.LineCount())
+		}
+		for n := 0; n <= mapped.LineCount()+1; n++ {
+			want, wantErr := mapped.GetLine(n)
+			got, err := read.GetLine(n)
+			if !bytes.Equal(got, want) || (err == nil) != (wantErr == nil) {
+				t.Errorf("content %q line %d: Open gave (%q, %v), OpenFile (%q, %v)", content, n, want, wantErr, got, err)
+			}
+		}
+
+		if err := mapped.Close(); err != nil {
+			t.Errorf("Open: Close failed: %v", err)
+		}
+		if err := read.Close(); err != nil {
+			t.Errorf("OpenFile: Close failed: %v", err)
+		}
+	}
+}
+
 // TestScanLines verifies the line scanner.
 func TestScanLines(t *testing.T) {
 	content := "line1\nline2\nline3\n"
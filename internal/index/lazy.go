@@ -0,0 +1,219 @@
+package index
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"golang.org/x/exp/mmap"
+)
+
+// ErrNotIndexed is returned by GetLine for a line past the end of what a
+// lazy index has scanned so far. The line may become available later.
+var ErrNotIndexed = errors.New("line not indexed yet")
+
+// lazyChunkSize is how many bytes a lazy index reads and scans at a time.
+// It's a variable so tests can scan small files in several chunks.
+var lazyChunkSize = 4 << 20
+
+// lazyScan tracks the background scan of an index opened with OpenLazy or
+// OpenPipe. done and err are guarded by the index's mutex.
+type lazyScan struct {
+	stop     chan struct{} // closed by Close to end the scan early
+	stopOnce sync.Once
+	finished chan struct{} // closed when the scan goroutine exits
+	start    time.Time     // when indexing began, for the index duration
+	pipe     io.Closer     // pipe read by OpenPipe, closed to end a blocked read
+
+	done bool  // the whole file has been scanned
+	err  error // the read error that ended the scan, if any
+}
+
+// OpenLazy memory-maps the file at path like Open, but returns as soon as
+// the first initialLines lines are indexed and scans the rest in a
+// background goroutine. Use IndexProgress to watch the scan; until it
+// finishes, LineCount grows as lines are found and GetLine returns
+// ErrNotIndexed for lines beyond it.
+//
+// Compressed files, and files starting with a byte order mark, are
+// decompressed or decoded and indexed up front, as with Open. The caller
+// must call Close when done, which also stops the scan.
+func OpenLazy(path string, initialLines int) (*Index, error) {
+	if format, err := DetectCompression(path); err == nil && format != CompressionNone {
+		return OpenCompressed(path, 0)
+	}
+
+	readerAt, err := mmap.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to mmap file: %w", err)
+	}
+	if readerAt.Len() == 0 {
+		_ = readerAt.Close()
+		return nil, ErrEmptyFile
+	}
+	head := make([]byte, min(readerAt.Len(), len(bomUTF8)))
+	if _, err := readerAt.ReadAt(head, 0); err == nil {
+		if _, bom := detectBOM(head); bom > 0 {
+			_ = readerAt.Close()
+			return Open(path)
+		}
+	}
+
+	idx := &Index{
+		data:    make([]byte, readerAt.Len()),
+		offsets: append(make([]uint64, 0, 1024), 0),
+		reader:  readerAt,
+		name:    path,
+		path:    path,
+		lazy: &lazyScan{
+			stop:     make(chan struct{}),
+			finished: make(chan struct{}),
+			start:    time.Now(),
+		},
+	}
+
+	pos := 0
+	for pos < len(idx.data) && len(idx.offsets)-1 < initialLines {
+		if pos, err = idx.scanChunk(readerAt, pos); err != nil {
+			_ = readerAt.Close()
+			return nil, err
+		}
+	}
+
+	// Framed records can't be found by newlines alone, so framed data is
+	// read and indexed in full up front
+	framed := detectFraming(idx.data[:pos]) != framingLines
+	if framed && pos < len(idx.data) {
+		if _, err := readerAt.ReadAt(idx.data[pos:], int64(pos)); err != nil && err != io.EOF {
+			_ = readerAt.Close()
+			return nil, fmt.Errorf("failed to read mmap data: %w", err)
+		}
+		pos = len(idx.data)
+	}
+
+	if pos == len(idx.data) {
+		idx.lazy.done = true
+		close(idx.lazy.finished)
+		if framed {
+			idx.offsets = idx.offsets[:0]
+			if err := idx.buildOffsets(); err != nil {
+				_ = readerAt.Close()
+				return nil, err
+			}
+			idx.indexTime = time.Since(idx.lazy.start)
+		}
+		return idx, nil
+	}
+	go idx.scanRest(readerAt, pos)
+	return idx, nil
+}
+
+// scanRest indexes the file from offset pos onward until it reaches the
+// end, fails, or Close stops it.
+func (idx *Index) scanRest(r io.ReaderAt, pos int) {
+	defer close(idx.lazy.finished)
+
+	for pos < len(idx.data) {
+		select {
+		case <-idx.lazy.stop:
+			return
+		default:
+		}
+
+		next, err := idx.scanChunk(r, pos)
+		if err != nil {
+			idx.mu.Lock()
+			idx.lazy.err = err
+			idx.mu.Unlock()
+			return
+		}
+		pos = next
+	}
+}
+
+// scanChunk reads the next chunk of the file from offset pos into data,
+// publishes the line starts found in it, and returns where the chunk ended.
+// Bytes past the published lines are only ever written here, so readers
+// holding the mutex never see a partially read line.
+func (idx *Index) scanChunk(r io.ReaderAt, pos int) (int, error) {
+	end := min(pos+lazyChunkSize, len(idx.data))
+	if _, err := r.ReadAt(idx.data[pos:end], int64(pos)); err != nil && err != io.EOF {
+		return pos, fmt.Errorf("failed to read mmap data: %w", err)
+	}
+
+	var found []uint64
+	for i := pos; i < end; i++ {
+		if idx.data[i] == '\n' && i+1 < len(idx.data) {
+			found = append(found, uint64(i+1))
+		}
+	}
+
+	idx.mu.Lock()
+	idx.offsets = append(idx.offsets, found...)
+	idx.lazy.done = end == len(idx.data)
+	if idx.lazy.done {
+		idx.indexTime = time.Since(idx.lazy.start)
+	}
+	idx.mu.Unlock()
+	return end, nil
+}
+
+// IndexProgress reports whether line indexing has finished and how many
+// lines are available so far. Indexes not opened with OpenLazy are always
+// done. A scan that failed also reports done; see IndexErr.
+func (idx *Index) IndexProgress() (done bool, lines int) {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return !idx.scanning(), idx.lineCount()
+}
+
+// IndexErr returns the error that ended a lazy index's background scan
+// early, or nil.
+func (idx *Index) IndexErr() error {
+	if idx.lazy == nil {
+		return nil
+	}
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return idx.lazy.err
+}
+
+// scanning reports whether a lazy scan is still indexing lines. The caller
+// must hold the mutex.
+func (idx *Index) scanning() bool {
+	return idx.lazy != nil && !idx.lazy.done && idx.lazy.err == nil
+}
+
+// waitIndexed blocks until a lazy index's background scan has exited and
+// returns the error that ended it early, if any.
+func (idx *Index) waitIndexed() error {
+	if idx.lazy == nil {
+		return nil
+	}
+	<-idx.lazy.finished
+	if err := idx.IndexErr(); err != nil {
+		return err
+	}
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	if !idx.lazy.done {
+		return ErrNotIndexed
+	}
+	return nil
+}
+
+// stopScan ends a lazy index's background scan and waits for it to exit.
+func (idx *Index) stopScan() {
+	if idx.lazy == nil {
+		return
+	}
+	idx.lazy.stopOnce.Do(func() {
+		close(idx.lazy.stop)
+		if idx.lazy.pipe != nil {
+			_ = idx.lazy.pipe.Close()
+		}
+	})
+	<-idx.lazy.finished
+}
@@ -0,0 +1,170 @@
+package index
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// lazyTestContent returns n numbered lines, each ending in a newline.
+func lazyTestContent(n int) string {
+	var b strings.Builder
+	for i := 1; i <= n; i++ {
+		fmt.Fprintf(&b, "line%d\n", i)
+	}
+	return b.String()
+}
+
+// setLazyChunkSize shrinks lazyChunkSize for the duration of a test.
+func setLazyChunkSize(t *testing.T, size int) {
+	t.Helper()
+	old := lazyChunkSize
+	lazyChunkSize = size
+	t.Cleanup(func() { lazyChunkSize = old })
+}
+
+// TestScanChunk verifies a lazy index exposes only complete lines while
+// scanning, reports ErrNotIndexed beyond them, and counts the unterminated
+// last line once the scan reaches the end.
+func TestScanChunk(t *testing.T) {
+	setLazyChunkSize(t, 8)
+	content := "line1\nline2\nline3\nlast"
+	idx := &Index{
+		data:    make([]byte, len(content)),
+		offsets: []uint64{0},
+		lazy:    &lazyScan{},
+	}
+	r := strings.NewReader(content)
+
+	steps := []struct {
+		lines int
+		done  bool
+		last  string
+	}{
+		{1, false, "line1"}, // "line1\nli"
+		{2, false, "line2"}, // "ne2\nline"
+		{4, true, "last"},   // "3\nlast"
+	}
+
+	pos := 0
+	for i, s := range steps {
+		var err error
+		if pos, err = idx.scanChunk(r, pos); err != nil {
+			t.Fatalf("chunk %d: scanChunk failed: %v", i, err)
+		}
+		done, lines := idx.IndexProgress()
+		if done != s.done || lines != s.lines {
+			t.Errorf("chunk %d: expected progress (%v, %d), got (%v, %d)", i, s.done, s.lines, done, lines)
+		}
+		if !done {
+			if _, err := idx.GetLine(lines + 1); !errors.Is(err, ErrNotIndexed) {
+				t.Errorf("chunk %d: expected ErrNotIndexed for line %d, got %v", i, lines+1, err)
+			}
+		}
+		if line, err := idx.GetLineString(lines); err != nil || line != s.last {
+			t.Errorf("chunk %d: expected line %d %q, got %q (%v)", i, lines, s.last, line, err)
+		}
+	}
+
+	if _, err := idx.GetLine(5); !errors.Is(err, ErrInvalidLine) {
+		t.Errorf("expected ErrInvalidLine once done, got %v", err)
+	}
+}
+
+// TestOpenLazy verifies OpenLazy indexes at least the requested lines up
+// front and finishes with the same lines Open finds.
+func TestOpenLazy(t *testing.T) {
+	setLazyChunkSize(t, 16)
+	content := lazyTestContent(200)
+	path := createTestFile(t, content)
+
+	idx, err := OpenLazy(path, 10)
+	if err != nil {
+		t.Fatalf("OpenLazy failed: %v", err)
+	}
+	defer closeIndex(idx)
+
+	if _, lines := idx.IndexProgress(); lines < 10 {
+		t.Errorf("expected at least 10 lines indexed up front, got %d", lines)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for done, _ := idx.IndexProgress(); !done; done, _ = idx.IndexProgress() {
+		if time.Now().After(deadline) {
+			t.Fatal("lazy scan did not finish")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	if idx.LineCount() != 200 {
+		t.Fatalf("expected 200 lines, got %d", idx.LineCount())
+	}
+	for _, n := range []int{1, 100, 200} {
+		if line, _ := idx.GetLineString(n); line != fmt.Sprintf("line%d", n) {
+			t.Errorf("line %d: expected %q, got %q", n, fmt.Sprintf("line%d", n), line)
+		}
+	}
+	if err := idx.IndexErr(); err != nil {
+		t.Errorf("expected no scan error, got %v", err)
+	}
+	if idx.Size() != int64(len(content)) || idx.IndexDuration() <= 0 {
+		t.Errorf("expected size %d and a positive duration, got %d and %v",
+			len(content), idx.Size(), idx.IndexDuration())
+	}
+}
+
+// TestOpenLazyCloseEarly verifies Close stops a scan still in progress.
+func TestOpenLazyCloseEarly(t *testing.T) {
+	setLazyChunkSize(t, 8)
+	path := createTestFile(t, lazyTestContent(5000))
+
+	idx, err := OpenLazy(path, 1)
+	if err != nil {
+		t.Fatalf("OpenLazy failed: %v", err)
+	}
+	if err := idx.Close(); err != nil {
+		t.Errorf("Close failed: %v", err)
+	}
+	select {
+	case <-idx.lazy.finished:
+	default:
+		t.Error("expected the scan to have exited after Close")
+	}
+}
+
+// TestOpenLazySaveIndex verifies SaveIndex waits for the scan, so the
+// sidecar covers every line.
+func TestOpenLazySaveIndex(t *testing.T) {
+	setLazyChunkSize(t, 8)
+	path := createTestFile(t, lazyTestContent(500))
+
+	idx, err := OpenLazy(path, 1)
+	if err != nil {
+		t.Fatalf("OpenLazy failed: %v", err)
+	}
+	defer closeIndex(idx)
+
+	sidecar := SidecarPath(path)
+	if err := idx.SaveIndex(sidecar); err != nil {
+		t.Fatalf("SaveIndex failed: %v", err)
+	}
+
+	loaded, fromSidecar, err := OpenIndexed(path)
+	if err != nil {
+		t.Fatalf("OpenIndexed failed: %v", err)
+	}
+	defer closeIndex(loaded)
+	if !fromSidecar || loaded.LineCount() != 500 {
+		t.Errorf("expected 500 lines from the sidecar, got %d (sidecar %v)", loaded.LineCount(), fromSidecar)
+	}
+}
+
+// TestOpenLazyEmpty verifies an empty file is rejected like Open does.
+func TestOpenLazyEmpty(t *testing.T) {
+	path := createTestFile(t, "")
+	if _, err := OpenLazy(path, 10); !errors.Is(err, ErrEmptyFile) {
+		t.Errorf("expected ErrEmptyFile, got %v", err)
+	}
+}
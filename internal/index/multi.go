@@ -0,0 +1,114 @@
+package index
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// OpenMulti opens several files and presents them as one index, the lines
+// of each file following those of the one before it in the order given.
+// Each file is memory-mapped when possible and read into memory otherwise,
+// and compressed files are decompressed, as with Open. Empty files are
+// skipped; if every file is empty, ErrEmptyFile is returned.
+//
+// The combined index can't be refreshed or saved. The caller must call
+// Close when done, which closes every file.
+func OpenMulti(paths []string) (*Index, error) {
+	return openMulti(paths, openPart)
+}
+
+// OpenMultiFile is like OpenMulti but reads every file into memory, as
+// OpenFile does, instead of memory-mapping it.
+func OpenMultiFile(paths []string) (*Index, error) {
+	return openMulti(paths, func(path string) (*Index, error) { return OpenFile(path) })
+}
+
+// openMulti opens each of paths with open and joins them into one index.
+func openMulti(paths []string, open func(path string) (*Index, error)) (*Index, error) {
+	idx := &Index{
+		name:   fmt.Sprintf("%d files", len(paths)),
+		starts: []int{0},
+	}
+	for _, path := range paths {
+		part, err := open(path)
+		if errors.Is(err, ErrEmptyFile) {
+			continue
+		}
+		if err != nil {
+			_ = idx.Close()
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		idx.parts = append(idx.parts, part)
+		idx.starts = append(idx.starts, idx.starts[len(idx.starts)-1]+part.LineCount())
+	}
+	if len(idx.parts) == 0 {
+		return nil, ErrEmptyFile
+	}
+	return idx, nil
+}
+
+// openPart opens one file of a multi-file index, memory-mapping it and
+// falling back to reading it into memory when it can't be mapped.
+func openPart(path string) (*Index, error) {
+	idx, err := Open(path)
+	if err == nil || errors.Is(err, ErrEmptyFile) {
+		return idx, err
+	}
+	return OpenFile(path)
+}
+
+// partLine returns line n of a multi-file index from the file holding it.
+func (idx *Index) partLine(n int) ([]byte, error) {
+	part, local, err := idx.partOf(n)
+	if err != nil {
+		return nil, err
+	}
+	return part.GetLine(local)
+}
+
+// partRange returns lines start through end of a multi-file index as one
+// block, joining the raw ranges of each file they span with newlines.
+func (idx *Index) partRange(start, end int) ([]byte, error) {
+	if start < 1 || end < start {
+		return nil, ErrInvalidLine
+	}
+	first, from, err := idx.partOf(start)
+	if err != nil {
+		return nil, err
+	}
+	last, to, err := idx.partOf(end)
+	if err != nil {
+		return nil, err
+	}
+	if first == last {
+		return first.GetRawRange(from, to)
+	}
+
+	var block []byte
+	for n := start; n <= end; {
+		part, local, _ := idx.partOf(n)
+		partEnd := min(part.LineCount(), local+end-n)
+		raw, err := part.GetRawRange(local, partEnd)
+		if err != nil {
+			return nil, err
+		}
+		if block != nil {
+			block = append(block, '\n')
+		}
+		block = append(block, raw...)
+		n += partEnd - local + 1
+	}
+	return block, nil
+}
+
+// partOf returns the file of a multi-file index holding line n, and the
+// line's number within that file.
+func (idx *Index) partOf(n int) (part *Index, local int, err error) {
+	if n < 1 || n > idx.starts[len(idx.parts)] {
+		return nil, 0, ErrInvalidLine
+	}
+	// starts[i+1] is the last line of part i
+	i := sort.SearchInts(idx.starts[1:], n)
+	return idx.parts[i], n - idx.starts[i], nil
+}
@@ -0,0 +1,142 @@
+package index
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+// TestOpenMulti verifies lines of several files of different sizes and
+// kinds read as one stream in the order given, with each file's last line
+// kept apart from the next file's first.
+func TestOpenMulti(t *testing.T) {
+	paths := []string{
+		createTestFile(t, "a1\na2\na3\n"),
+		createTestFile(t, ""),
+		createTestFile(t, "b1"),
+		filepath.Join("testdata", "sample.ndjson.gz"),
+		createTestFile(t, "c1\r\nc2\r\n"),
+	}
+	idx, err := OpenMulti(paths)
+	if err != nil {
+		t.Fatalf("OpenMulti failed: %v", err)
+	}
+
+	want := []string{"a1", "a2", "a3", "b1"}
+	want = append(want, sampleLines...)
+	want = append(want, "c1", "c2")
+
+	if idx.LineCount() != len(want) {
+		t.Fatalf("expected %d lines, got %d", len(want), idx.LineCount())
+	}
+	for n, w := range want {
+		if got, err := idx.GetLineString(n + 1); err != nil || got != w {
+			t.Errorf("line %d: expected %q, got %q (%v)", n+1, w, got, err)
+		}
+	}
+	for _, n := range []int{0, len(want) + 1} {
+		if _, err := idx.GetLine(n); !errors.Is(err, ErrInvalidLine) {
+			t.Errorf("line %d: expected ErrInvalidLine, got %v", n, err)
+		}
+	}
+
+	// A range read crosses from one file into the next
+	if lines, err := idx.GetLines(3, 4); err != nil || string(lines[0]) != "a3" || string(lines[1]) != "b1" {
+		t.Errorf("expected lines 3-4 to be a3, b1, got %q (%v)", lines, err)
+	}
+
+	// Offsets are within the file holding the line
+	if off, err := idx.LineOffset(3); err != nil || off != 6 {
+		t.Errorf("expected line 3 at offset 6, got %d (%v)", off, err)
+	}
+	if off, err := idx.LineOffset(len(want)); err != nil || off != 4 {
+		t.Errorf("expected the last line at offset 4 of its file, got %d (%v)", off, err)
+	}
+
+	if idx.Name() != "5 files" {
+		t.Errorf("expected name %q, got %q", "5 files", idx.Name())
+	}
+	if done, lines := idx.IndexProgress(); !done || lines != len(want) {
+		t.Errorf("expected indexing done with %d lines, got (%v, %d)", len(want), done, lines)
+	}
+	if _, err := idx.Refresh(); !errors.Is(err, ErrNotRefreshable) {
+		t.Errorf("expected ErrNotRefreshable, got %v", err)
+	}
+	if err := idx.SaveIndex(filepath.Join(t.TempDir(), "multi.jlvidx")); err == nil {
+		t.Error("expected saving a multi-file index to fail")
+	}
+	if err := idx.Close(); err != nil {
+		t.Errorf("Close failed: %v", err)
+	}
+}
+
+// TestOpenMultiFile verifies OpenMultiFile reads every file into memory
+// and indexes the same lines as OpenMulti.
+func TestOpenMultiFile(t *testing.T) {
+	paths := []string{createTestFile(t, "a1\na2\n"), createTestFile(t, ""), createTestFile(t, "b1")}
+	idx, err := OpenMultiFile(paths)
+	if err != nil {
+		t.Fatalf("OpenMultiFile failed: %v", err)
+	}
+	defer func() { _ = idx.Close() }()
+
+	for _, part := range idx.parts {
+		if part.reader != nil {
+			t.Errorf("expected %s read into memory, not mapped", part.Name())
+		}
+	}
+	want := []string{"a1", "a2", "b1"}
+	if idx.LineCount() != len(want) {
+		t.Fatalf("expected %d lines, got %d", len(want), idx.LineCount())
+	}
+	for n, w := range want {
+		if got, err := idx.GetLineString(n + 1); err != nil || got != w {
+			t.Errorf("line %d: expected %q, got %q (%v)", n+1, w, got, err)
+		}
+	}
+}
+
+// TestOpenMultiErrors verifies a missing file fails the whole open and
+// only empty files is ErrEmptyFile.
+func TestOpenMultiErrors(t *testing.T) {
+	good := createTestFile(t, "a\n")
+	if _, err := OpenMulti([]string{good, filepath.Join(t.TempDir(), "missing.log")}); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+	empty := []string{createTestFile(t, ""), createTestFile(t, "")}
+	if _, err := OpenMulti(empty); !errors.Is(err, ErrEmptyFile) {
+		t.Errorf("expected ErrEmptyFile, got %v", err)
+	}
+}
+
+// TestMultiRawRange verifies a range within one file of a multi-file index
+// is read from it, and one spanning files is joined with newlines.
+func TestMultiRawRange(t *testing.T) {
+	idx, err := OpenMulti([]string{
+		createTestFile(t, "a1\na2\n"),
+		createTestFile(t, "b1"),
+		createTestFile(t, "c1\r\nc2\r\n"),
+	})
+	if err != nil {
+		t.Fatalf("OpenMulti failed: %v", err)
+	}
+	defer closeIndex(idx)
+
+	ranges := []struct {
+		start, end int
+		want       string
+	}{
+		{1, 2, "a1\na2"},
+		{2, 4, "a2\nb1\nc1"},
+		{1, 5, "a1\na2\nb1\nc1\r\nc2"},
+	}
+	for _, r := range ranges {
+		got, err := idx.GetRawRange(r.start, r.end)
+		if err != nil || string(got) != r.want {
+			t.Errorf("GetRawRange(%d, %d): expected %q, got %q (%v)", r.start, r.end, r.want, got, err)
+		}
+	}
+	if _, err := idx.GetRawRange(4, 6); !errors.Is(err, ErrInvalidLine) {
+		t.Errorf("expected ErrInvalidLine past the end, got %v", err)
+	}
+}
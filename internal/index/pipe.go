@@ -0,0 +1,225 @@
+package index
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// pipeChunkSize is the most a streaming index reads from its pipe at a
+// time.
+const pipeChunkSize = 64 << 10
+
+// IsPipe reports whether path is a named pipe (FIFO). A pipe has no end
+// until its writer closes it, so it must be indexed with OpenPipe rather
+// than read in full first.
+func IsPipe(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.Mode()&os.ModeNamedPipe != 0
+}
+
+// OpenPipe opens the named pipe at path and indexes lines in a background
+// goroutine as they're written to it, instead of reading to EOF first.
+// Opening blocks until a writer connects, as for any reader of a FIFO.
+//
+// The index behaves like one from OpenLazy: LineCount grows as complete
+// lines arrive, IndexProgress reports done once the writer closes the pipe,
+// and IndexErr holds a read error that ended the stream. Data is held in
+// memory and split on newlines only; JSON arrays and multi-line objects
+// aren't detected. The index can't be refreshed or saved. The caller must
+// call Close when done, which also closes the pipe.
+func OpenPipe(path string) (*Index, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open pipe: %w", err)
+	}
+	return indexPipe(f, path, nil), nil
+}
+
+// OpenReaderStream indexes r, such as stdin, as lines arrive, like
+// OpenPipe, so a producer that never ends (journalctl -f) can be viewed
+// while it runs. It returns at once with an empty index and detects the
+// layout in the background from the data up to the end of the first JSON
+// value, or the first framingProbeSize bytes: a JSON array or
+// pretty-printed objects, or a stream that ends that soon, is read to the
+// end and indexed like OpenReader instead, as is UTF-16, which is
+// transcoded. Until then LineCount stays zero; a failure, such as an empty
+// stream, is reported by IndexErr. A UTF-8 byte order mark is dropped. The
+// caller must call Close when done, which also closes r.
+func OpenReaderStream(r io.ReadCloser, name string) *Index {
+	idx := newPipeIndex(r, name)
+	go idx.readStream(r)
+	return idx
+}
+
+// framingProbeSize is the most OpenReaderStream reads waiting for the
+// first JSON value to close before settling the layout, so a stream whose
+// first line was cut off still starts showing lines.
+const framingProbeSize = 64 << 10
+
+// readStream detects the layout of r from its head and indexes it for
+// OpenReaderStream.
+func (idx *Index) readStream(r io.Reader) {
+	head, err := readHead(r)
+	head = bytes.TrimPrefix(head, bomUTF8)
+	enc, _ := detectBOM(head)
+	if err != nil || detectFraming(head) != framingLines || isUTF16(enc) {
+		defer close(idx.lazy.finished)
+		switch {
+		case errors.Is(err, io.EOF):
+			idx.readAll(bytes.NewReader(head))
+		case err != nil:
+			idx.endPipe(err)
+		default:
+			idx.readAll(io.MultiReader(bytes.NewReader(head), r))
+		}
+		return
+	}
+	idx.appendPipe(head)
+	idx.readPipe(r)
+}
+
+// readHead reads from r until the first JSON value, or the first line of
+// data that doesn't start with one, is complete up to its newline, or
+// framingProbeSize bytes have been read, returning everything read. The
+// error is io.EOF if r ended first.
+func readHead(r io.Reader) ([]byte, error) {
+	var data []byte
+	buf := make([]byte, pipeChunkSize)
+	for {
+		n, err := r.Read(buf)
+		data = append(data, buf[:n]...)
+		if headComplete(data) || len(data) >= framingProbeSize {
+			return data, nil
+		}
+		if err != nil {
+			return data, err
+		}
+	}
+}
+
+// headComplete reports whether data holds enough to pick its framing: a
+// newline after the end of the first value, or after the start of a first
+// line that isn't JSON.
+func headComplete(data []byte) bool {
+	start := skipSpace(data, 0)
+	if start == len(data) {
+		return false
+	}
+	end := start
+	if data[start] == '{' || data[start] == '[' {
+		end = valueEnd(data, start, "")
+	}
+	return bytes.IndexByte(data[end:], '\n') >= 0
+}
+
+// readAll reads r to the end and indexes it in full, framing and all, as
+// OpenReader does. The caller closes the finished channel.
+func (idx *Index) readAll(r io.Reader) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		idx.endPipe(err)
+		return
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.data, idx.offsets = data, idx.offsets[:0]
+	if err := idx.buildOffsets(); err != nil {
+		// Leave the single unfinished line start lineCount expects
+		idx.data, idx.offsets, idx.ends = nil, append(idx.offsets[:0], 0), nil
+		idx.lazy.err = err
+		return
+	}
+	idx.lazy.done = true
+	idx.indexTime = time.Since(idx.lazy.start)
+}
+
+// indexPipe starts indexing r in the background, after the data already
+// read from it in prefix. r is closed by Close.
+func indexPipe(r io.ReadCloser, name string, prefix []byte) *Index {
+	idx := newPipeIndex(r, name)
+	idx.appendPipe(prefix)
+	go idx.readPipe(r)
+	return idx
+}
+
+// newPipeIndex returns an empty index streaming from r, which Close
+// closes.
+func newPipeIndex(r io.ReadCloser, name string) *Index {
+	return &Index{
+		offsets: append(make([]uint64, 0, 1024), 0),
+		name:    name,
+		lazy: &lazyScan{
+			stop:     make(chan struct{}),
+			finished: make(chan struct{}),
+			start:    time.Now(),
+			pipe:     r,
+		},
+	}
+}
+
+// readPipe appends everything read from r to the index until r reaches
+// EOF, fails, or Close stops it.
+func (idx *Index) readPipe(r io.Reader) {
+	defer close(idx.lazy.finished)
+
+	buf := make([]byte, pipeChunkSize)
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			idx.appendPipe(buf[:n])
+		}
+		if err != nil {
+			idx.endPipe(err)
+			return
+		}
+	}
+}
+
+// endPipe records how the stream ended: complete at EOF, or with err.
+// An error from Close interrupting a read isn't a stream error.
+func (idx *Index) endPipe(err error) {
+	select {
+	case <-idx.lazy.stop:
+		return
+	default:
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	if errors.Is(err, io.EOF) {
+		idx.finishPipe()
+	} else {
+		idx.lazy.err = fmt.Errorf("failed to read %s: %w", idx.name, err)
+	}
+}
+
+// appendPipe adds chunk to the data and publishes the lines it completes.
+// Every newline starts a new line, even at the end of the data, since
+// more may follow; lineCount leaves that last, unfinished line out.
+func (idx *Index) appendPipe(chunk []byte) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	from := len(idx.data)
+	idx.data = append(idx.data, chunk...)
+	for i := from; i < len(idx.data); i++ {
+		if idx.data[i] == '\n' {
+			idx.offsets = append(idx.offsets, uint64(i+1))
+		}
+	}
+}
+
+// finishPipe marks the stream complete, dropping the empty line after a
+// final newline so every remaining line start is a real line. The caller
+// must hold the mutex.
+func (idx *Index) finishPipe() {
+	if last := len(idx.offsets) - 1; int(idx.offsets[last]) == len(idx.data) {
+		idx.offsets = idx.offsets[:last]
+	}
+	idx.lazy.done = true
+	idx.indexTime = time.Since(idx.lazy.start)
+}
@@ -0,0 +1,197 @@
+package index
+
+import (
+	"errors"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// waitLines polls idx until it reports want lines or the deadline passes.
+func waitLines(t *testing.T, idx *Index, want int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for idx.LineCount() != want {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %d lines, got %d", want, idx.LineCount())
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// TestPipeIndex verifies a streaming index publishes lines as they are
+// completed, holds back a line until its newline arrives, and finishes
+// when the writer closes.
+func TestPipeIndex(t *testing.T) {
+	r, w := io.Pipe()
+	idx := indexPipe(r, "pipe", nil)
+	defer closeIndex(idx)
+
+	if done, lines := idx.IndexProgress(); done || lines != 0 {
+		t.Fatalf("expected an empty index still streaming, got (%v, %d)", done, lines)
+	}
+
+	_, _ = w.Write([]byte("line1\nline2\nli"))
+	waitLines(t, idx, 2)
+	if _, err := idx.GetLine(3); !errors.Is(err, ErrNotIndexed) {
+		t.Errorf("expected the partial line to be ErrNotIndexed, got %v", err)
+	}
+
+	_, _ = w.Write([]byte("ne3\n"))
+	waitLines(t, idx, 3)
+	if line, _ := idx.GetLineString(3); line != "line3" {
+		t.Errorf("expected line3 completed across writes, got %q", line)
+	}
+
+	_, _ = w.Write([]byte("line4"))
+	_ = w.Close()
+	if err := idx.waitIndexed(); err != nil {
+		t.Fatalf("expected the stream to finish, got %v", err)
+	}
+	if idx.LineCount() != 4 || idx.Size() != 23 {
+		t.Errorf("expected 4 lines of 23 bytes, got %d lines of %d", idx.LineCount(), idx.Size())
+	}
+	if line, _ := idx.GetLineString(4); line != "line4" {
+		t.Errorf("expected the unterminated last line, got %q", line)
+	}
+	if err := idx.SaveIndex(filepath.Join(t.TempDir(), "pipe.jlvidx")); err == nil {
+		t.Error("expected saving a pipe's index to fail")
+	}
+}
+
+// TestPipeIndexError verifies a read error ends the stream and is
+// reported by IndexErr with the lines read before it kept.
+func TestPipeIndexError(t *testing.T) {
+	r, w := io.Pipe()
+	idx := indexPipe(r, "pipe", nil)
+	defer closeIndex(idx)
+
+	_, _ = w.Write([]byte("line1\n"))
+	boom := errors.New("boom")
+	_ = w.CloseWithError(boom)
+	<-idx.lazy.finished
+
+	if err := idx.IndexErr(); !errors.Is(err, boom) {
+		t.Errorf("expected the read error, got %v", err)
+	}
+	if done, lines := idx.IndexProgress(); !done || lines != 1 {
+		t.Errorf("expected done with 1 line, got (%v, %d)", done, lines)
+	}
+}
+
+// TestPipeIndexClose verifies Close interrupts a read waiting for data
+// and closes the reader.
+func TestPipeIndexClose(t *testing.T) {
+	r, w := io.Pipe()
+	idx := indexPipe(r, "pipe", nil)
+
+	closed := make(chan error, 1)
+	go func() { closed <- idx.Close() }()
+	select {
+	case err := <-closed:
+		if err != nil {
+			t.Errorf("Close failed: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Close blocked on a pending read")
+	}
+
+	if _, err := w.Write([]byte("late\n")); !errors.Is(err, io.ErrClosedPipe) {
+		t.Errorf("expected the reader closed, got %v", err)
+	}
+	if err := idx.IndexErr(); err != nil {
+		t.Errorf("expected no stream error after Close, got %v", err)
+	}
+}
+
+// TestIsPipe verifies regular files and missing paths aren't pipes.
+func TestIsPipe(t *testing.T) {
+	if IsPipe(createTestFile(t, "line\n")) {
+		t.Error("expected a regular file not to be a pipe")
+	}
+	if IsPipe(filepath.Join(t.TempDir(), "missing")) {
+		t.Error("expected a missing path not to be a pipe")
+	}
+}
+
+// TestOpenReaderStream verifies an NDJSON stream is indexed as it arrives,
+// starting from an empty index, while layouts needing the whole data and
+// streams shorter than a line are read to the end.
+func TestOpenReaderStream(t *testing.T) {
+	r, w := io.Pipe()
+	idx := OpenReaderStream(r, "stdin")
+	defer closeIndex(idx)
+	if done, lines := idx.IndexProgress(); done || lines != 0 {
+		t.Fatalf("expected an empty index still streaming, got (%v, %d)", done, lines)
+	}
+	_, _ = w.Write([]byte(`{"n":1}` + "\n" + `{"n"`))
+	waitLines(t, idx, 1)
+	_, _ = w.Write([]byte(":2}\n"))
+	waitLines(t, idx, 2)
+	_ = w.Close()
+	if err := idx.waitIndexed(); err != nil {
+		t.Fatalf("expected the stream to finish, got %v", err)
+	}
+	if line, _ := idx.GetLineString(2); line != `{"n":2}` {
+		t.Errorf("expected the second line, got %q", line)
+	}
+
+	tests := []struct {
+		name    string
+		input   string
+		want    []string
+		wantErr error
+	}{
+		{"array", "[\n{\"n\":1},\n{\"n\":2}\n]\n", []string{`{"n":1}`, `{"n":2}`}, nil},
+		{"no newline", `{"n":1}`, []string{`{"n":1}`}, nil},
+		{"empty", "", nil, ErrEmptyFile},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			idx := OpenReaderStream(io.NopCloser(strings.NewReader(tt.input)), "stdin")
+			defer closeIndex(idx)
+			if err := idx.waitIndexed(); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if done, _ := idx.IndexProgress(); !done || idx.LineCount() != len(tt.want) {
+				t.Fatalf("expected %d lines fully indexed, got %d (done %v)", len(tt.want), idx.LineCount(), done)
+			}
+			for i, want := range tt.want {
+				if line, _ := idx.GetLineString(i + 1); line != want {
+					t.Errorf("line %d: expected %q, got %q", i+1, want, line)
+				}
+			}
+		})
+	}
+}
+
+// TestOpenReaderStreamSplitObject verifies a pretty-printed first object
+// arriving over several reads is still detected as multi-line records
+// rather than indexed a physical line at a time.
+func TestOpenReaderStreamSplitObject(t *testing.T) {
+	r, w := io.Pipe()
+	idx := OpenReaderStream(r, "stdin")
+	defer closeIndex(idx)
+
+	go func() {
+		for _, chunk := range []string{"{\n", `  "level": "info",` + "\n", `  "msg": "a"` + "\n}", "\n{\n  \"msg\": \"b\"\n}\n"} {
+			_, _ = w.Write([]byte(chunk))
+			time.Sleep(5 * time.Millisecond)
+		}
+		_ = w.Close()
+	}()
+	if err := idx.waitIndexed(); err != nil {
+		t.Fatalf("expected the stream to finish, got %v", err)
+	}
+	want := []string{"{\n  \"level\": \"info\",\n  \"msg\": \"a\"\n}", "{\n  \"msg\": \"b\"\n}"}
+	if idx.LineCount() != len(want) {
+		t.Fatalf("expected %d records, got %d", len(want), idx.LineCount())
+	}
+	for i, w := range want {
+		if line, _ := idx.GetLineString(i + 1); line != w {
+			t.Errorf("record %d: expected %q, got %q", i+1, w, line)
+		}
+	}
+}
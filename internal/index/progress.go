@@ -0,0 +1,86 @@
+package index
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+// DefaultProgressInterval is how often a ProgressReporter writes by default.
+const DefaultProgressInterval = 500 * time.Millisecond
+
+// ProgressReporter writes periodic progress lines (lines, bytes, and rate)
+// while a long scan runs. Writes are throttled to one per interval so the
+// reporter can be called for every line without slowing the scan.
+//
+// Progress goes only to the writer given to NewProgressReporter, normally
+// os.Stderr, so it never mixes with a scan's real output on stdout.
+type ProgressReporter struct {
+	w        io.Writer
+	interval time.Duration
+	now      func() time.Time
+
+	start time.Time
+	last  time.Time
+	lines int
+	bytes int64
+}
+
+// NewProgressReporter creates a reporter writing to w at most once per
+// interval. The clock starts when the reporter is created.
+func NewProgressReporter(w io.Writer, interval time.Duration) *ProgressReporter {
+	r := &ProgressReporter{
+		w:        w,
+		interval: interval,
+		now:      time.Now,
+	}
+	r.start = r.now()
+	r.last = r.start
+	return r
+}
+
+// Add records one scanned line of n bytes, excluding the newline, and
+// writes a progress line if the interval has passed.
+func (r *ProgressReporter) Add(n int) {
+	r.lines++
+	r.bytes += int64(n) + 1
+	if now := r.now(); now.Sub(r.last) >= r.interval {
+		r.last = now
+		r.write(now, false)
+	}
+}
+
+// Done writes the final totals and ends the progress line.
+func (r *ProgressReporter) Done() {
+	r.write(r.now(), true)
+}
+
+// write prints the current totals. Intermediate lines end in a carriage
+// return so a terminal shows them updating in place.
+func (r *ProgressReporter) write(now time.Time, final bool) {
+	elapsed := now.Sub(r.start).Seconds()
+	rate := 0.0
+	if elapsed > 0 {
+		rate = float64(r.lines) / elapsed
+	}
+	end := "\r"
+	if final {
+		end = "\n"
+	}
+	_, _ = fmt.Fprintf(r.w, "%d lines, %s, %.0f lines/s%s", r.lines, FormatBytes(r.bytes), rate, end)
+}
+
+// FormatBytes renders a byte count with a binary unit suffix, e.g.
+// "1.3 GiB".
+func FormatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,115 @@
+package index
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestProgressReporter verifies progress is throttled, written only to the
+// reporter's writer, and finished with a newline.
+func TestProgressReporter(t *testing.T) {
+	var stderr, stdout bytes.Buffer
+	clock := time.Unix(0, 0)
+
+	r := NewProgressReporter(&stderr, time.Second)
+	r.now = func() time.Time { return clock }
+	r.start, r.last = clock, clock
+
+	// A scan writes its real output to stdout alongside the reporter
+	content := "a\nbb\nccc\n"
+	err := ScanLines(strings.NewReader(content), func(line []byte, lineNum int) error {
+		stdout.Write(line)
+		clock = clock.Add(400 * time.Millisecond)
+		r.Add(len(line))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ScanLines failed: %v", err)
+	}
+	r.Done()
+
+	// Only the third line crossed the 1s interval, plus the final report
+	reports := strings.Split(strings.TrimSuffix(stderr.String(), "\n"), "\r")
+	if len(reports) != 2 {
+		t.Fatalf("expected 1 throttled report and a final one, got %q", stderr.String())
+	}
+	if want := "3 lines, 9 B, 2 lines/s"; reports[1] != want {
+		t.Errorf("expected final report %q, got %q", want, reports[1])
+	}
+	if stdout.String() != "abbccc" {
+		t.Errorf("expected stdout to hold only scan output, got %q", stdout.String())
+	}
+}
+
+// TestFormatBytes verifies byte counts get readable units.
+func TestFormatBytes(t *testing.T) {
+	tests := []struct {
+		n    int64
+		want string
+	}{
+		{0, "0 B"},
+		{1023, "1023 B"},
+		{1536, "1.5 KiB"},
+		{5 * 1024 * 1024 * 1024, "5.0 GiB"},
+	}
+
+	for _, tt := range tests {
+		if got := FormatBytes(tt.n); got != tt.want {
+			t.Errorf("FormatBytes(%d) = %q, expected %q", tt.n, got, tt.want)
+		}
+	}
+}
+
+// TestWithProgress verifies building an index reports growing byte counts
+// ending at the file size, through Open and through OpenIndexed, and that
+// indexing in steps finds the same lines.
+func TestWithProgress(t *testing.T) {
+	old := progressStep
+	progressStep = 4
+	t.Cleanup(func() { progressStep = old })
+
+	content := "line1\nline2\nline3\n"
+	path := createTestFile(t, content)
+
+	openers := map[string]func(opt OpenOption) (*Index, error){
+		"Open": func(opt OpenOption) (*Index, error) { return Open(path, opt) },
+		"OpenIndexed": func(opt OpenOption) (*Index, error) {
+			idx, _, err := OpenIndexed(path, opt)
+			return idx, err
+		},
+	}
+	for name, open := range openers {
+		t.Run(name, func(t *testing.T) {
+			var reports []int64
+			idx, err := open(WithProgress(func(done, total int64) {
+				if total != int64(len(content)) {
+					t.Errorf("expected total %d, got %d", len(content), total)
+				}
+				reports = append(reports, done)
+			}))
+			if err != nil {
+				t.Fatalf("open failed: %v", err)
+			}
+			defer closeIndex(idx)
+
+			want := []int64{4, 8, 12, 16, 18}
+			if len(reports) != len(want) {
+				t.Fatalf("expected reports %v, got %v", want, reports)
+			}
+			for i := range want {
+				if reports[i] != want[i] {
+					t.Errorf("expected reports %v, got %v", want, reports)
+					break
+				}
+			}
+			if idx.LineCount() != 3 {
+				t.Errorf("expected 3 lines, got %d", idx.LineCount())
+			}
+			if line, _ := idx.GetLineString(2); line != "line2" {
+				t.Errorf("expected line2, got %q", line)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package index
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"golang.org/x/exp/mmap"
+)
+
+var (
+	// ErrNotRefreshable is returned by Refresh for an index that isn't
+	// backed by a plain file, such as stdin or a decompressed file.
+	ErrNotRefreshable = errors.New("index is not backed by a plain file")
+	// ErrTruncated is returned by Refresh when the file got smaller, so the
+	// indexed lines no longer match it.
+	ErrTruncated = errors.New("file was truncated")
+)
+
+// Refresh reads any bytes appended to the underlying file since it was
+// indexed and extends the line index to cover them, for following a log
+// as it's written. It returns how many lines were added.
+//
+// A memory-mapped file is remapped at its new size so the appended bytes
+// come from the current mapping rather than a stale one. A trailing line
+// that was still being written is completed in place. A lazy index isn't
+// refreshed until its background scan has finished.
+func (idx *Index) Refresh() (int, error) {
+	if idx.path == "" {
+		return 0, ErrNotRefreshable
+	}
+	if done, _ := idx.IndexProgress(); !done {
+		return 0, nil
+	}
+	if err := idx.IndexErr(); err != nil {
+		return 0, err
+	}
+
+	info, err := os.Stat(idx.path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to stat %s: %w", idx.path, err)
+	}
+	// The data starts after any byte order mark in the file
+	oldLen := len(idx.data)
+	fileLen := int64(idx.bom + oldLen)
+	switch size := info.Size(); {
+	case size == fileLen:
+		return 0, nil
+	case size < fileLen:
+		return 0, fmt.Errorf("%s: %w", idx.path, ErrTruncated)
+	}
+
+	appended, err := idx.readAppended(fileLen)
+	if err != nil {
+		return 0, err
+	}
+	if len(appended) == 0 {
+		return 0, nil
+	}
+
+	// Readers such as a background search may be walking the lines, so
+	// the data and offsets only change under the write lock.
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	before := len(idx.offsets)
+	idx.data = append(idx.data, appended...)
+	if idx.ends != nil {
+		idx.refreshRecords(oldLen)
+	} else {
+		idx.extendOffsets(oldLen)
+	}
+	return len(idx.offsets) - before, nil
+}
+
+// readAppended returns the file's bytes from offset from onward, remapping
+// the file first when the index is memory-mapped.
+func (idx *Index) readAppended(from int64) ([]byte, error) {
+	if _, ok := idx.reader.(*mmap.ReaderAt); ok {
+		readerAt, err := mmap.Open(idx.path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to remap file: %w", err)
+		}
+		buf := make([]byte, int64(readerAt.Len())-from)
+		if _, err := readerAt.ReadAt(buf, from); err != nil && err != io.EOF {
+			_ = readerAt.Close()
+			return nil, fmt.Errorf("failed to read mmap data: %w", err)
+		}
+		_ = idx.reader.Close()
+		idx.reader = readerAt
+		return buf, nil
+	}
+
+	f, err := os.Open(idx.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	if _, err := f.Seek(from, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("failed to seek in %s: %w", idx.path, err)
+	}
+	buf, err := io.ReadAll(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", idx.path, err)
+	}
+	return buf, nil
+}
+
+// extendOffsets adds the starts of lines found in data from offset from
+// onward. The scan starts one byte early so a newline that ended the old
+// data now starts the first new line.
+func (idx *Index) extendOffsets(from int) {
+	for i := max(from-1, 0); i < len(idx.data); i++ {
+		if idx.data[i] == '\n' && i+1 < len(idx.data) {
+			idx.offsets = append(idx.offsets, uint64(i+1))
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package index
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+// appendFile appends s to the file at path.
+func appendFile(t *testing.T, path, s string) {
+	t.Helper()
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatalf("failed to open file for append: %v", err)
+	}
+	defer func() { _ = f.Close() }()
+	if _, err := f.WriteString(s); err != nil {
+		t.Fatalf("failed to append: %v", err)
+	}
+}
+
+// TestRefresh verifies appended lines are indexed for both mapped and
+// in-memory files, including a trailing line completed by a later write.
+func TestRefresh(t *testing.T) {
+	openers := map[string]func(string) (*Index, error){
+		"Open":     func(p string) (*Index, error) { return Open(p) },
+		"OpenFile": func(p string) (*Index, error) { return OpenFile(p) },
+	}
+
+	for name, open := range openers {
+		t.Run(name, func(t *testing.T) {
+			path := createTestFile(t, "line1\nline2\n")
+			idx, err := open(path)
+			if err != nil {
+				t.Fatalf("%s failed: %v", name, err)
+			}
+			defer closeIndex(idx)
+
+			steps := []struct {
+				appended string
+				added    int
+				lines    []string
+			}{
+				{"", 0, []string{"line1", "line2"}},
+				{"line3\nli", 2, []string{"line1", "line2", "line3", "li"}},
+				{"ne4\n", 0, []string{"line1", "line2", "line3", "line4"}},
+				{"line5\n", 1, []string{"line1", "line2", "line3", "line4", "line5"}},
+			}
+
+			for _, s := range steps {
+				appendFile(t, path, s.appended)
+				added, err := idx.Refresh()
+				if err != nil {
+					t.Fatalf("Refresh after %q failed: %v", s.appended, err)
+				}
+				if added != s.added {
+					t.Errorf("after %q: expected %d new lines, got %d", s.appended, s.added, added)
+				}
+				var got []string
+				for n := 1; n <= idx.LineCount(); n++ {
+					line, _ := idx.GetLineString(n)
+					got = append(got, line)
+				}
+				if strings.Join(got, "|") != strings.Join(s.lines, "|") {
+					t.Errorf("after %q: expected lines %q, got %q", s.appended, s.lines, got)
+				}
+			}
+		})
+	}
+}
+
+// TestRefreshTruncated verifies a shrunken file reports ErrTruncated.
+func TestRefreshTruncated(t *testing.T) {
+	path := createTestFile(t, "line1\nline2\n")
+	idx, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer closeIndex(idx)
+
+	if err := os.WriteFile(path, []byte("x\n"), 0644); err != nil {
+		t.Fatalf("failed to rewrite file: %v", err)
+	}
+	if _, err := idx.Refresh(); !errors.Is(err, ErrTruncated) {
+		t.Errorf("expected ErrTruncated, got %v", err)
+	}
+}
+
+// TestRefreshNotRefreshable verifies streams can't be refreshed.
+func TestRefreshNotRefreshable(t *testing.T) {
+	idx, err := OpenReader(strings.NewReader("line1\n"), "stdin")
+	if err != nil {
+		t.Fatalf("OpenReader failed: %v", err)
+	}
+	defer closeIndex(idx)
+
+	if _, err := idx.Refresh(); !errors.Is(err, ErrNotRefreshable) {
+		t.Errorf("expected ErrNotRefreshable, got %v", err)
+	}
+}
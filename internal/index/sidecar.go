@@ -0,0 +1,180 @@
+package index
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// SidecarSuffix is appended to a log file's path to name its saved index.
+const SidecarSuffix = ".jlvidx"
+
+var (
+	// ErrStaleIndex is returned when a saved index doesn't match the
+	// current size or modification time of its source file.
+	ErrStaleIndex = errors.New("saved index is stale")
+	// ErrBadIndexFile is returned when a saved index is truncated or
+	// otherwise malformed.
+	ErrBadIndexFile = errors.New("malformed index file")
+)
+
+// sidecarMagic identifies the sidecar format; the last byte is the version.
+var sidecarMagic = []byte("JLVIDX\x00\x01")
+
+// sidecarHeaderSize is the magic followed by the source size, the source
+// modification time in Unix nanoseconds, and the line count, each 8 bytes.
+const sidecarHeaderSize = 8 + 3*8
+
+// SidecarPath returns the conventional sidecar path for a log file.
+func SidecarPath(path string) string {
+	return path + SidecarSuffix
+}
+
+// SaveIndex writes the line offsets to path, recording the size and
+// modification time of the source file so LoadIndex can detect changes.
+// Only indexes of whole, uncompressed files can be saved. For an index
+// opened with OpenLazy, SaveIndex waits for the background scan to finish.
+func (idx *Index) SaveIndex(path string) error {
+	if idx.lazy != nil && idx.lazy.pipe != nil {
+		return fmt.Errorf("cannot save index for %s: a pipe has no file to reopen", idx.name)
+	}
+	if err := idx.waitIndexed(); err != nil {
+		return fmt.Errorf("cannot save index for %s: %w", idx.name, err)
+	}
+	if idx.ends != nil {
+		return fmt.Errorf("cannot save index for %s: records span lines; only NDJSON indexes can be saved", idx.name)
+	}
+	if idx.parts != nil {
+		return fmt.Errorf("cannot save index for %s: only single-file indexes can be saved", idx.name)
+	}
+
+	info, err := os.Stat(idx.name)
+	if err != nil {
+		return fmt.Errorf("cannot save index for %s: %w", idx.name, err)
+	}
+	if info.Size() != int64(len(idx.data)) {
+		return fmt.Errorf("cannot save index for %s: indexed data is not the file contents", idx.name)
+	}
+
+	// Write to a temporary file first so a failed save never leaves a
+	// truncated sidecar behind
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return fmt.Errorf("failed to create index file: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	w := bufio.NewWriter(tmp)
+	header := make([]byte, 0, sidecarHeaderSize)
+	header = append(header, sidecarMagic...)
+	header = binary.LittleEndian.AppendUint64(header, uint64(info.Size()))
+	header = binary.LittleEndian.AppendUint64(header, uint64(info.ModTime().UnixNano()))
+	header = binary.LittleEndian.AppendUint64(header, uint64(len(idx.offsets)))
+	_, _ = w.Write(header)
+
+	var buf [8]byte
+	for _, off := range idx.offsets {
+		binary.LittleEndian.PutUint64(buf[:], off)
+		_, _ = w.Write(buf[:])
+	}
+
+	if err := w.Flush(); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write index file: %w", err)
+	}
+	if err := tmp.Chmod(0644); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write index file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write index file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write index file: %w", err)
+	}
+	return nil
+}
+
+// LoadIndex replaces the line offsets with those saved at path.
+// Returns ErrStaleIndex if the source file changed since the index was
+// saved, or ErrBadIndexFile if the sidecar is malformed. The current
+// offsets are left untouched on error.
+func (idx *Index) LoadIndex(path string) error {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read index file: %w", err)
+	}
+	if len(raw) < sidecarHeaderSize || !bytes.Equal(raw[:len(sidecarMagic)], sidecarMagic) {
+		return ErrBadIndexFile
+	}
+
+	header := raw[len(sidecarMagic):sidecarHeaderSize]
+	size := binary.LittleEndian.Uint64(header[0:8])
+	modTime := int64(binary.LittleEndian.Uint64(header[8:16]))
+	count := binary.LittleEndian.Uint64(header[16:24])
+
+	info, err := os.Stat(idx.name)
+	if err != nil {
+		return fmt.Errorf("cannot access %s: %w", idx.name, err)
+	}
+	if uint64(info.Size()) != size || info.ModTime().UnixNano() != modTime ||
+		uint64(len(idx.data)) != size {
+		return ErrStaleIndex
+	}
+
+	body := raw[sidecarHeaderSize:]
+	if count == 0 || uint64(len(body)) != count*8 {
+		return ErrBadIndexFile
+	}
+
+	offsets := make([]uint64, count)
+	for i := range offsets {
+		offsets[i] = binary.LittleEndian.Uint64(body[i*8:])
+		// Offsets must start at zero and strictly increase within the file
+		if (i == 0 && offsets[i] != 0) || (i > 0 && offsets[i] <= offsets[i-1]) || offsets[i] >= size {
+			return ErrBadIndexFile
+		}
+	}
+
+	idx.offsets = offsets
+	return nil
+}
+
+// OpenIndexed memory-maps the file at path like Open, but loads the line
+// offsets from its sidecar (see SidecarPath) when a valid one exists.
+// The returned bool reports whether the sidecar was used; a missing,
+// stale, or malformed sidecar silently falls back to scanning the file,
+// which reports to a WithProgress callback as Open does.
+// The caller must call Close when done.
+func OpenIndexed(path string, opts ...OpenOption) (*Index, bool, error) {
+	if format, err := DetectCompression(path); err == nil && format != CompressionNone {
+		idx, err := OpenCompressed(path, 0)
+		return idx, false, err
+	}
+
+	idx, err := mapFile(path)
+	if err != nil {
+		return nil, false, err
+	}
+	for _, opt := range opts {
+		opt(idx)
+	}
+
+	start := time.Now()
+	// Offsets into transcoded data can't have been saved
+	if len(idx.data) > 0 && !isUTF16(idx.encoding) && idx.LoadIndex(SidecarPath(path)) == nil {
+		idx.indexTime = time.Since(start)
+		return idx, true, nil
+	}
+
+	if err := idx.buildOffsets(); err != nil {
+		_ = idx.Close()
+		return nil, false, err
+	}
+	return idx, false, nil
+}
@@ -0,0 +1,198 @@
+package index
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestSaveLoadIndex verifies offsets survive a save/load round trip.
+func TestSaveLoadIndex(t *testing.T) {
+	path := createTestFile(t, "line1\nline22\nline333\n")
+
+	idx, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer closeIndex(idx)
+
+	sidecar := SidecarPath(path)
+	if err := idx.SaveIndex(sidecar); err != nil {
+		t.Fatalf("SaveIndex failed: %v", err)
+	}
+
+	loaded, err := mapFile(path)
+	if err != nil {
+		t.Fatalf("mapFile failed: %v", err)
+	}
+	defer closeIndex(loaded)
+
+	if err := loaded.LoadIndex(sidecar); err != nil {
+		t.Fatalf("LoadIndex failed: %v", err)
+	}
+	if loaded.LineCount() != idx.LineCount() {
+		t.Fatalf("expected %d lines, got %d", idx.LineCount(), loaded.LineCount())
+	}
+	for n := 1; n <= idx.LineCount(); n++ {
+		want, _ := idx.GetLineString(n)
+		got, _ := loaded.GetLineString(n)
+		if got != want {
+			t.Errorf("line %d: expected %q, got %q", n, want, got)
+		}
+	}
+}
+
+// TestLoadIndexStale verifies a sidecar is rejected once its source changes.
+func TestLoadIndexStale(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(t *testing.T, path string)
+	}{
+		{"appended", func(t *testing.T, path string) {
+			f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+			if err != nil {
+				t.Fatalf("failed to open file: %v", err)
+			}
+			_, _ = f.WriteString("line4\n")
+			_ = f.Close()
+		}},
+		{"touched", func(t *testing.T, path string) {
+			later := time.Now().Add(time.Hour)
+			if err := os.Chtimes(path, later, later); err != nil {
+				t.Fatalf("failed to touch file: %v", err)
+			}
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := createTestFile(t, "line1\nline2\nline3\n")
+			idx, err := Open(path)
+			if err != nil {
+				t.Fatalf("Open failed: %v", err)
+			}
+			if err := idx.SaveIndex(SidecarPath(path)); err != nil {
+				t.Fatalf("SaveIndex failed: %v", err)
+			}
+			closeIndex(idx)
+
+			tt.change(t, path)
+
+			reopened, err := mapFile(path)
+			if err != nil {
+				t.Fatalf("mapFile failed: %v", err)
+			}
+			defer closeIndex(reopened)
+
+			if err := reopened.LoadIndex(SidecarPath(path)); !errors.Is(err, ErrStaleIndex) {
+				t.Errorf("expected ErrStaleIndex, got %v", err)
+			}
+
+			// OpenIndexed falls back to a fresh scan
+			fresh, used, err := OpenIndexed(path)
+			if err != nil {
+				t.Fatalf("OpenIndexed failed: %v", err)
+			}
+			defer closeIndex(fresh)
+			if used {
+				t.Error("expected stale sidecar to be ignored")
+			}
+		})
+	}
+}
+
+// TestLoadIndexMalformed verifies truncated or foreign files are rejected.
+func TestLoadIndexMalformed(t *testing.T) {
+	path := createTestFile(t, "line1\nline2\n")
+	idx, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer closeIndex(idx)
+
+	sidecar := SidecarPath(path)
+	if err := idx.SaveIndex(sidecar); err != nil {
+		t.Fatalf("SaveIndex failed: %v", err)
+	}
+	raw, err := os.ReadFile(sidecar)
+	if err != nil {
+		t.Fatalf("failed to read sidecar: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"empty", nil},
+		{"wrong magic", append([]byte("NOTANIDX"), raw[8:]...)},
+		{"truncated", raw[:len(raw)-4]},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bad := filepath.Join(t.TempDir(), "bad"+SidecarSuffix)
+			if err := os.WriteFile(bad, tt.data, 0644); err != nil {
+				t.Fatalf("failed to write file: %v", err)
+			}
+			if err := idx.LoadIndex(bad); !errors.Is(err, ErrBadIndexFile) {
+				t.Errorf("expected ErrBadIndexFile, got %v", err)
+			}
+			if idx.LineCount() != 2 {
+				t.Errorf("expected offsets to be untouched, got %d lines", idx.LineCount())
+			}
+		})
+	}
+}
+
+// TestOpenIndexed verifies a valid sidecar is used on reopen.
+func TestOpenIndexed(t *testing.T) {
+	path := createTestFile(t, "line1\nline2\nline3\n")
+
+	idx, used, err := OpenIndexed(path)
+	if err != nil {
+		t.Fatalf("OpenIndexed failed: %v", err)
+	}
+	if used {
+		t.Error("expected no sidecar on first open")
+	}
+	if err := idx.SaveIndex(SidecarPath(path)); err != nil {
+		t.Fatalf("SaveIndex failed: %v", err)
+	}
+	closeIndex(idx)
+
+	idx, used, err = OpenIndexed(path)
+	if err != nil {
+		t.Fatalf("OpenIndexed failed: %v", err)
+	}
+	defer closeIndex(idx)
+	if !used {
+		t.Error("expected sidecar to be used on reopen")
+	}
+	if got, _ := idx.GetLineString(3); got != "line3" {
+		t.Errorf("expected line3, got %q", got)
+	}
+}
+
+// TestSaveIndexNotAFile verifies indexes that don't mirror a file on disk
+// can't be saved.
+func TestSaveIndexNotAFile(t *testing.T) {
+	stdin, err := OpenReader(strings.NewReader("a\nb\n"), "stdin")
+	if err != nil {
+		t.Fatalf("OpenReader failed: %v", err)
+	}
+	if err := stdin.SaveIndex(filepath.Join(t.TempDir(), "stdin"+SidecarSuffix)); err == nil {
+		t.Error("expected an error saving an index for stdin")
+	}
+
+	compressed, err := Open(filepath.Join("testdata", "sample.ndjson.xz"))
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer closeIndex(compressed)
+	if err := compressed.SaveIndex(filepath.Join(t.TempDir(), "sample"+SidecarSuffix)); err == nil {
+		t.Error("expected an error saving an index for a compressed file")
+	}
+}
@@ -0,0 +1,123 @@
+package index
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// tailChunkSize is how many bytes OpenTail first reads from the end of the
+// file, doubling until the window holds enough lines. It's a variable so
+// tests can make small files take several reads.
+var tailChunkSize = 64 << 10
+
+// OpenTail indexes only the last n lines of the file at path, for a quick
+// look at the end of a file too large to index in full. It reads backward
+// from the end in growing windows until it has found n line starts, so
+// only the tail is read into memory. A file with fewer than n lines is
+// indexed whole. Line 1 of the index is the first line of the tail.
+//
+// Compressed files can't be read backward and are decompressed in full
+// before the tail is kept, as are UTF-16 files, which are transcoded. A
+// maxBytes above zero caps the decompressed data as in OpenCompressed, so
+// the tail is then the last n lines of what was read. The tail is meant
+// for line-per-record logs; a cut into a JSON array or multi-line objects
+// frames from the cut. The index can't be refreshed or saved. The caller
+// must call Close when done.
+func OpenTail(path string, n int, maxBytes int64) (*Index, error) {
+	if n < 1 {
+		return nil, fmt.Errorf("tail of %d lines: must be at least 1", n)
+	}
+	if format, err := DetectCompression(path); (err == nil && format != CompressionNone) || startsUTF16(path) {
+		var whole *Index
+		if err == nil && format != CompressionNone {
+			whole, err = OpenCompressed(path, maxBytes)
+		} else {
+			whole, err = OpenFile(path)
+		}
+		if err != nil {
+			return nil, err
+		}
+		data := whole.data
+		_ = whole.Close()
+		return newTailIndex(data[tailStart(data, n, true):], path)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat file: %w", err)
+	}
+	size := info.Size()
+	if size == 0 {
+		return nil, ErrEmptyFile
+	}
+
+	for window := min(int64(tailChunkSize), size); ; window = min(window*2, size) {
+		buf := make([]byte, window)
+		if _, err := f.ReadAt(buf, size-window); err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("failed to read file: %w", err)
+		}
+		whole := window == size
+		if start := tailStart(buf, n, whole); start >= 0 {
+			return newTailIndex(buf[start:], path)
+		}
+	}
+}
+
+// startsUTF16 reports whether the file at path starts with a UTF-16 byte
+// order mark.
+func startsUTF16(path string) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer func() { _ = f.Close() }()
+	head := make([]byte, len(bomUTF16LE))
+	if _, err := io.ReadFull(f, head); err != nil {
+		return false
+	}
+	enc, _ := detectBOM(head)
+	return isUTF16(enc)
+}
+
+// tailStart returns the offset in data where its last n lines begin, not
+// counting a final newline as the start of another line. When data holds
+// fewer than n line starts it returns 0 if data is the whole file (whole),
+// and -1 otherwise, since the start of its first line isn't known.
+func tailStart(data []byte, n int, whole bool) int {
+	end := len(data)
+	if end > 0 && data[end-1] == '\n' {
+		end--
+	}
+	for range n {
+		nl := bytes.LastIndexByte(data[:end], '\n')
+		if nl < 0 {
+			if whole {
+				return 0
+			}
+			return -1
+		}
+		end = nl
+	}
+	return end + 1
+}
+
+// newTailIndex indexes data, the tail of the file name.
+func newTailIndex(data []byte, name string) (*Index, error) {
+	idx := &Index{
+		data:    data,
+		offsets: make([]uint64, 0, 1024),
+		name:    name,
+	}
+	if err := idx.buildOffsets(); err != nil {
+		return nil, err
+	}
+	return idx, nil
+}
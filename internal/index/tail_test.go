@@ -0,0 +1,110 @@
+package index
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestOpenTail verifies only the last n lines are indexed, whether or not
+// the file ends in a newline, across several backward reads, and that a
+// short file is indexed whole.
+func TestOpenTail(t *testing.T) {
+	old := tailChunkSize
+	tailChunkSize = 8
+	t.Cleanup(func() { tailChunkSize = old })
+
+	var lines []string
+	for i := 1; i <= 20; i++ {
+		lines = append(lines, fmt.Sprintf("line%d", i))
+	}
+
+	tests := []struct {
+		name    string
+		content string
+		n       int
+		want    []string
+	}{
+		{"trailing newline", strings.Join(lines, "\n") + "\n", 3, lines[17:]},
+		{"no trailing newline", strings.Join(lines, "\n"), 3, lines[17:]},
+		{"CRLF", strings.Join(lines, "\r\n") + "\r\n", 2, lines[18:]},
+		{"one line", strings.Join(lines, "\n") + "\n", 1, lines[19:]},
+		{"exactly all", strings.Join(lines, "\n") + "\n", 20, lines},
+		{"more than the file", strings.Join(lines, "\n") + "\n", 50, lines},
+		{"empty lines kept", "a\n\nb\n\nc\n", 3, []string{"b", "", "c"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			idx, err := OpenTail(createTestFile(t, tt.content), tt.n, 0)
+			if err != nil {
+				t.Fatalf("OpenTail failed: %v", err)
+			}
+			defer closeIndex(idx)
+
+			if idx.LineCount() != len(tt.want) {
+				t.Fatalf("expected %d lines, got %d", len(tt.want), idx.LineCount())
+			}
+			for i, want := range tt.want {
+				if got, err := idx.GetLineString(i + 1); err != nil || got != want {
+					t.Errorf("line %d: expected %q, got %q (%v)", i+1, want, got, err)
+				}
+			}
+		})
+	}
+}
+
+// TestOpenTailCompressed verifies the tail of a compressed file is taken
+// from its decompressed lines, and from only the first maxBytes of them
+// when capped.
+func TestOpenTailCompressed(t *testing.T) {
+	path := filepath.Join("testdata", "sample.ndjson.gz")
+	tests := []struct {
+		name     string
+		maxBytes int64
+		want     []string
+	}{
+		{"whole", 0, sampleLines[len(sampleLines)-2:]},
+		{"capped", int64(len(sampleLines[0]) + len(sampleLines[1]) + 3), sampleLines[:2]},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			idx, err := OpenTail(path, 2, tt.maxBytes)
+			if err != nil {
+				t.Fatalf("OpenTail failed: %v", err)
+			}
+			defer closeIndex(idx)
+
+			if idx.LineCount() != len(tt.want) {
+				t.Fatalf("expected %d lines, got %d", len(tt.want), idx.LineCount())
+			}
+			for i, w := range tt.want {
+				if got, _ := idx.GetLineString(i + 1); got != w {
+					t.Errorf("line %d: expected %q, got %q", i+1, w, got)
+				}
+			}
+		})
+	}
+}
+
+// TestOpenTailErrors verifies bad counts, empty files, and refreshing are
+// rejected.
+func TestOpenTailErrors(t *testing.T) {
+	path := createTestFile(t, "a\nb\n")
+	if _, err := OpenTail(path, 0, 0); err == nil {
+		t.Error("expected an error for a tail of 0 lines")
+	}
+	if _, err := OpenTail(createTestFile(t, ""), 5, 0); !errors.Is(err, ErrEmptyFile) {
+		t.Errorf("expected ErrEmptyFile, got %v", err)
+	}
+
+	idx, err := OpenTail(path, 1, 0)
+	if err != nil {
+		t.Fatalf("OpenTail failed: %v", err)
+	}
+	defer closeIndex(idx)
+	if _, err := idx.Refresh(); !errors.Is(err, ErrNotRefreshable) {
+		t.Errorf("expected ErrNotRefreshable, got %v", err)
+	}
+}
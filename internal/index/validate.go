@@ -0,0 +1,89 @@
+package index
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidIndex is returned by Validate when the line index doesn't
+// agree with the data it indexes.
+var ErrInvalidIndex = errors.New("invalid index")
+
+// Validate checks the line index against the data: every line must start
+// at offset 0 or just after a newline, the starts must increase, and the
+// line count must match an independent ScanLines count of the data. Data
+// framed as a JSON array or multi-line objects has its record bounds
+// checked instead, since its records aren't lines. Validate waits for a
+// lazy index to finish scanning, and checks each file of a multi-file
+// index in turn. The error wraps ErrInvalidIndex when the index is wrong.
+func (idx *Index) Validate() error {
+	if idx.parts != nil {
+		for i, part := range idx.parts {
+			if err := part.Validate(); err != nil {
+				return fmt.Errorf("%s: %w", part.Name(), err)
+			}
+			if lines := idx.starts[i+1] - idx.starts[i]; lines != part.LineCount() {
+				return fmt.Errorf("%w: %s has %d lines, but %d are counted for it", ErrInvalidIndex, part.Name(), part.LineCount(), lines)
+			}
+		}
+		return nil
+	}
+	if err := idx.waitIndexed(); err != nil {
+		return err
+	}
+
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	if idx.ends != nil {
+		return idx.validateRecords()
+	}
+	data := idx.data
+	for i, off := range idx.offsets {
+		switch {
+		case off >= uint64(len(data)):
+			return fmt.Errorf("%w: line %d starts at offset %d, past the end of %d bytes", ErrInvalidIndex, i+1, off, len(data))
+		case i > 0 && off <= idx.offsets[i-1]:
+			return fmt.Errorf("%w: line %d starts at offset %d, not after line %d at %d", ErrInvalidIndex, i+1, off, i, idx.offsets[i-1])
+		case i == 0 && off != 0:
+			return fmt.Errorf("%w: line 1 starts at offset %d, not 0", ErrInvalidIndex, off)
+		case off > 0 && data[off-1] != '\n':
+			return fmt.Errorf("%w: line %d starts at offset %d, which doesn't follow a newline", ErrInvalidIndex, i+1, off)
+		}
+	}
+
+	// Count the lines again without the offsets
+	count := 0
+	err := ScanLines(bytes.NewReader(data), func([]byte, int) error {
+		count++
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("counting lines: %w", err)
+	}
+	if count != len(idx.offsets) {
+		return fmt.Errorf("%w: %d lines indexed, but the data holds %d", ErrInvalidIndex, len(idx.offsets), count)
+	}
+	return nil
+}
+
+// validateRecords checks the bounds of framed records: each must start
+// after the previous one ends and end within the data. The caller holds
+// mu.
+func (idx *Index) validateRecords() error {
+	if len(idx.ends) != len(idx.offsets) {
+		return fmt.Errorf("%w: %d records start but %d end", ErrInvalidIndex, len(idx.offsets), len(idx.ends))
+	}
+	var prev uint64
+	for i, start := range idx.offsets {
+		end := idx.ends[i]
+		switch {
+		case start < prev:
+			return fmt.Errorf("%w: record %d starts at offset %d, before the previous one ends at %d", ErrInvalidIndex, i+1, start, prev)
+		case end < start || end > uint64(len(idx.data)):
+			return fmt.Errorf("%w: record %d spans offsets %d-%d, outside %d bytes", ErrInvalidIndex, i+1, start, end, len(idx.data))
+		}
+		prev = end
+	}
+	return nil
+}
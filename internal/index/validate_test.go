@@ -0,0 +1,102 @@
+package index
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestValidate verifies well-formed indexes of unusual files pass, and that
+// offsets off a line start or a wrong line count are caught.
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"lf", "{\"a\":1}\n{\"a\":2}\n"},
+		{"crlf", "{\"a\":1}\r\n{\"a\":2}\r\n"},
+		{"no trailing newline", "{\"a\":1}\n{\"a\":2}"},
+		{"blank lines", "{\"a\":1}\n\n\n{\"a\":2}\n"},
+		{"embedded nulls", "{\"a\":\"x\x00y\"}\n\x00\n{\"a\":2}\n"},
+		{"long line", "{\"a\":\"" + strings.Repeat("x", 200_000) + "\"}\n{\"a\":2}\n"},
+		{"json array", `[{"a":1},{"a":2}]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			idx, err := Open(createTestFile(t, tt.content))
+			if err != nil {
+				t.Fatalf("Open failed: %v", err)
+			}
+			defer closeIndex(idx)
+			if err := idx.Validate(); err != nil {
+				t.Errorf("Validate: %v", err)
+			}
+		})
+	}
+
+	t.Run("lazy", func(t *testing.T) {
+		idx, err := OpenLazy(createTestFile(t, strings.Repeat("{\"a\":1}\n", 1000)), 10)
+		if err != nil {
+			t.Fatalf("OpenLazy failed: %v", err)
+		}
+		defer closeIndex(idx)
+		if err := idx.Validate(); err != nil {
+			t.Errorf("Validate: %v", err)
+		}
+		if got := idx.LineCount(); got != 1000 {
+			t.Errorf("expected Validate to wait for all 1000 lines, got %d", got)
+		}
+	})
+
+	t.Run("offset off a line start", func(t *testing.T) {
+		idx, err := Open(createTestFile(t, "{\"a\":1}\n{\"a\":2}\n"))
+		if err != nil {
+			t.Fatalf("Open failed: %v", err)
+		}
+		defer closeIndex(idx)
+		idx.offsets[1]++
+		if err := idx.Validate(); !errors.Is(err, ErrInvalidIndex) || !strings.Contains(err.Error(), "line 2") {
+			t.Errorf("expected line 2 reported, got %v", err)
+		}
+	})
+
+	t.Run("missing line", func(t *testing.T) {
+		idx, err := Open(createTestFile(t, "{\"a\":1}\n{\"a\":2}\n{\"a\":3}\n"))
+		if err != nil {
+			t.Fatalf("Open failed: %v", err)
+		}
+		defer closeIndex(idx)
+		idx.offsets = idx.offsets[:2]
+		if err := idx.Validate(); !errors.Is(err, ErrInvalidIndex) || !strings.Contains(err.Error(), "2 lines indexed, but the data holds 3") {
+			t.Errorf("expected a line count mismatch, got %v", err)
+		}
+	})
+}
+
+// TestValidateMulti verifies each file of a multi-file index is checked
+// and named in the error.
+func TestValidateMulti(t *testing.T) {
+	dir := t.TempDir()
+	first, second := filepath.Join(dir, "a.log"), filepath.Join(dir, "b.log")
+	if err := os.WriteFile(first, []byte("{\"a\":1}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(second, []byte("{\"b\":1}\r\n{\"b\":2}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	idx, err := OpenMulti([]string{first, second})
+	if err != nil {
+		t.Fatalf("OpenMulti failed: %v", err)
+	}
+	defer closeIndex(idx)
+	if err := idx.Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+
+	idx.parts[1].offsets[1] = 0
+	if err := idx.Validate(); !errors.Is(err, ErrInvalidIndex) || !strings.Contains(err.Error(), "b.log") {
+		t.Errorf("expected b.log reported, got %v", err)
+	}
+}
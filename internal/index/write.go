@@ -0,0 +1,39 @@
+package index
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+)
+
+// WriteLines writes the given 1-indexed lines to w as raw bytes, each
+// followed by a newline, in the order listed. A nil lineNums writes every
+// line. Output is buffered; the first read or write error is returned.
+func (idx *Index) WriteLines(w io.Writer, lineNums []int) error {
+	bw := bufio.NewWriter(w)
+	write := func(n int) error {
+		line, err := idx.GetLine(n)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", n, err)
+		}
+		if _, err := bw.Write(line); err != nil {
+			return err
+		}
+		return bw.WriteByte('\n')
+	}
+
+	if lineNums == nil {
+		for n := 1; n <= idx.LineCount(); n++ {
+			if err := write(n); err != nil {
+				return err
+			}
+		}
+	} else {
+		for _, n := range lineNums {
+			if err := write(n); err != nil {
+				return err
+			}
+		}
+	}
+	return bw.Flush()
+}
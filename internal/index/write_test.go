@@ -0,0 +1,44 @@
+package index
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestWriteLines verifies selected lines are written raw, one per line.
+func TestWriteLines(t *testing.T) {
+	idx, err := OpenReader(strings.NewReader("a\r\nb\nc\nd"), "test")
+	if err != nil {
+		t.Fatalf("OpenReader failed: %v", err)
+	}
+	defer closeIndex(idx)
+
+	tests := []struct {
+		name  string
+		lines []int
+		want  string
+	}{
+		{"selected", []int{2, 4}, "b\nd\n"},
+		{"all", nil, "a\nb\nc\nd\n"},
+		{"none", []int{}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := idx.WriteLines(&buf, tt.lines); err != nil {
+				t.Fatalf("WriteLines failed: %v", err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, buf.String())
+			}
+		})
+	}
+
+	var buf bytes.Buffer
+	if err := idx.WriteLines(&buf, []int{1, 9}); !errors.Is(err, ErrInvalidLine) {
+		t.Errorf("expected ErrInvalidLine, got %v", err)
+	}
+}
@@ -148,6 +148,27 @@ func (v *Viewport) PageUp() {
 	v.clamp()
 }
 
+// PageDownKeepCursor moves the view and the cursor down by one screen, so
+// the cursor stays on the same screen row, as in less. On the last page
+// the view stops and the cursor moves on toward the last line.
+func (v *Viewport) PageDownKeepCursor() {
+	v.Offset += v.Height
+	v.Cursor += v.Height
+	v.clamp()
+}
+
+// PageUpKeepCursor moves the view and the cursor up by one screen, so the
+// cursor stays on the same screen row. On the first page the view stops
+// and the cursor moves on toward the first line.
+func (v *Viewport) PageUpKeepCursor() {
+	v.Offset -= v.Height
+	if v.Offset < 1 {
+		v.Offset = 1
+	}
+	v.Cursor -= v.Height
+	v.clamp()
+}
+
 // HalfPageDown moves down by half a screen, cursor moves with screen.
 func (v *Viewport) HalfPageDown() {
 	half := v.Height / 2
@@ -194,6 +215,61 @@ func (v *Viewport) Goto(line int) {
 	v.clamp()
 }
 
+// Center moves the cursor to the specified 1-indexed line and scrolls so
+// it sits in the middle of the view (zz in vim), showing the lines around
+// it. Near either end of the file the offset is clamped, so the line sits
+// above or below the middle instead.
+func (v *Viewport) Center(line int) {
+	v.Cursor = line
+	v.Offset = line - v.Height/2
+	v.clamp()
+}
+
+// NextMatch moves the cursor to the first line after it for which pred
+// returns true. With wrap set the search continues from the first line,
+// like vim's wrapscan, ending with the cursor's own line. It reports
+// whether a line was found; otherwise the cursor stays where it was.
+func (v *Viewport) NextMatch(pred func(line int) bool, wrap bool) bool {
+	for line := v.Cursor + 1; line <= v.TotalLines; line++ {
+		if pred(line) {
+			v.Goto(line)
+			return true
+		}
+	}
+	if !wrap {
+		return false
+	}
+	for line := 1; line <= min(v.Cursor, v.TotalLines); line++ {
+		if pred(line) {
+			v.Goto(line)
+			return true
+		}
+	}
+	return false
+}
+
+// PrevMatch is NextMatch searching upward: from the line before the
+// cursor to the first, then with wrap set from the last line back to the
+// cursor's own.
+func (v *Viewport) PrevMatch(pred func(line int) bool, wrap bool) bool {
+	for line := min(v.Cursor-1, v.TotalLines); line >= 1; line-- {
+		if pred(line) {
+			v.Goto(line)
+			return true
+		}
+	}
+	if !wrap {
+		return false
+	}
+	for line := v.TotalLines; line >= max(v.Cursor, 1); line-- {
+		if pred(line) {
+			v.Goto(line)
+			return true
+		}
+	}
+	return false
+}
+
 // GotoTop moves cursor to the first line.
 func (v *Viewport) GotoTop() {
 	v.Goto(1)
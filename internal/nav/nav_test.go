@@ -165,6 +165,63 @@ func TestPageDownUp(t *testing.T) {
 	}
 }
 
+// TestPageKeepCursor verifies paging that moves the cursor with the view
+// keeps it on the same screen row, and that the first and last pages stop
+// the view while the cursor continues to the first or last line.
+func TestPageKeepCursor(t *testing.T) {
+	v := New(100, 10)
+	v.Goto(4)
+
+	for i := 0; i < 5; i++ {
+		rel := v.CursorRelative()
+		v.PageDownKeepCursor()
+		if v.CursorRelative() != rel {
+			t.Fatalf("PageDownKeepCursor %d: expected relative row %d, got %d (%s)", i, rel, v.CursorRelative(), v.State())
+		}
+	}
+	if v.Cursor != 54 || v.Offset != 51 {
+		t.Errorf("expected cursor 54 offset 51, got %s", v.State())
+	}
+
+	for i := 0; i < 3; i++ {
+		rel := v.CursorRelative()
+		v.PageUpKeepCursor()
+		if v.CursorRelative() != rel {
+			t.Fatalf("PageUpKeepCursor %d: expected relative row %d, got %d (%s)", i, rel, v.CursorRelative(), v.State())
+		}
+	}
+	if v.Cursor != 24 || v.Offset != 21 {
+		t.Errorf("expected cursor 24 offset 21, got %s", v.State())
+	}
+
+	tests := []struct {
+		name           string
+		cursor, offset int
+		down           bool
+		wantCursor     int
+		wantOffset     int
+	}{
+		{"partial last page", 87, 85, true, 97, 91},
+		{"on last page", 95, 91, true, 100, 91},
+		{"partial first page", 8, 6, false, 1, 1},
+		{"on first page", 5, 1, false, 1, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := New(100, 10)
+			v.Cursor, v.Offset = tt.cursor, tt.offset
+			if tt.down {
+				v.PageDownKeepCursor()
+			} else {
+				v.PageUpKeepCursor()
+			}
+			if v.Cursor != tt.wantCursor || v.Offset != tt.wantOffset {
+				t.Errorf("expected cursor %d offset %d, got %s", tt.wantCursor, tt.wantOffset, v.State())
+			}
+		})
+	}
+}
+
 // TestHalfPageDownUp verifies half-page movement.
 func TestHalfPageDownUp(t *testing.T) {
 	v := New(100, 10)
@@ -232,6 +289,93 @@ func TestGoto(t *testing.T) {
 	}
 }
 
+// TestCenter verifies Center puts the line mid-view and clamps the offset
+// near the top and bottom of the file.
+func TestCenter(t *testing.T) {
+	tests := []struct {
+		name        string
+		total, line int
+		wantOffset  int
+	}{
+		{"middle of file", 100, 50, 45},
+		{"near top", 100, 3, 1},
+		{"first line", 100, 1, 1},
+		{"near bottom", 100, 98, 91},
+		{"last line", 100, 100, 91},
+		{"past the end", 100, 150, 91},
+		{"shorter than view", 5, 4, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := New(tt.total, 10)
+			v.Center(tt.line)
+			if want := min(tt.line, tt.total); v.Cursor != want {
+				t.Errorf("expected cursor %d, got %d", want, v.Cursor)
+			}
+			if v.Offset != tt.wantOffset {
+				t.Errorf("expected offset %d, got %d", tt.wantOffset, v.Offset)
+			}
+		})
+	}
+}
+
+// TestNextPrevMatch verifies NextMatch and PrevMatch find the nearest
+// matching line in their direction, wrap only when asked, and leave the
+// cursor alone when nothing matches.
+func TestNextPrevMatch(t *testing.T) {
+	matches := map[int]bool{10: true, 40: true, 70: true}
+	pred := func(line int) bool { return matches[line] }
+
+	tests := []struct {
+		name   string
+		cursor int
+		next   bool
+		wrap   bool
+		want   int
+		found  bool
+	}{
+		{"next", 10, true, false, 40, true},
+		{"next skips cursor line", 40, true, true, 70, true},
+		{"next at end without wrap", 70, true, false, 70, false},
+		{"next wraps to top", 80, true, true, 10, true},
+		{"prev", 70, false, false, 40, true},
+		{"prev at start without wrap", 10, false, false, 10, false},
+		{"prev wraps to bottom", 5, false, true, 70, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := New(100, 10)
+			v.Goto(tt.cursor)
+			var found bool
+			if tt.next {
+				found = v.NextMatch(pred, tt.wrap)
+			} else {
+				found = v.PrevMatch(pred, tt.wrap)
+			}
+			if found != tt.found || v.Cursor != tt.want {
+				t.Errorf("expected cursor %d (found %v), got %d (found %v)", tt.want, tt.found, v.Cursor, found)
+			}
+		})
+	}
+
+	// Wrapping all the way around finds the cursor's own line last
+	v := New(100, 10)
+	v.Goto(40)
+	if !v.NextMatch(func(line int) bool { return line == 40 }, true) || v.Cursor != 40 {
+		t.Errorf("expected a wrapped search to end on the cursor line, got %d", v.Cursor)
+	}
+
+	v = New(100, 10)
+	none := func(int) bool { return false }
+	if v.NextMatch(none, true) || v.PrevMatch(none, true) || v.Cursor != 1 {
+		t.Errorf("expected no match to leave the cursor on 1, got %d", v.Cursor)
+	}
+	empty := New(0, 10)
+	if empty.NextMatch(pred, true) || empty.PrevMatch(pred, true) {
+		t.Error("expected no match in an empty viewport")
+	}
+}
+
 // TestGotoLineTopMiddleBottom verifies H/M/L vim motions.
 func TestGotoLineTopMiddleBottom(t *testing.T) {
 	v := New(100, 10)
@@ -0,0 +1,73 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// StripANSI removes ANSI escape sequences, such as the color codes some
+// loggers embed in messages, leaving only the text they decorate.
+func StripANSI(s string) string {
+	if !strings.ContainsRune(s, '\x1b') {
+		return s
+	}
+	return ansi.Strip(s)
+}
+
+// escapedCSI matches a CSI escape sequence at the start of JSON text, where
+// the ESC character is written as \u001b.
+var escapedCSI = regexp.MustCompile(`^\\u001[bB]\[[0-?]*[ -/]*[@-~]`)
+
+// StripEscapedANSI removes ANSI escape sequences written inside JSON
+// strings, as in "\u001b[31merror\u001b[0m", from formatted JSON text.
+func StripEscapedANSI(s string) string {
+	return replaceEscapedCSI(s, func(string) string { return "" })
+}
+
+// InterpretEscapedANSI turns color and style sequences written inside JSON
+// strings into real escape sequences, so the terminal renders them instead
+// of showing \u001b[31m. A reset is added at the end of a line that sets
+// any, so colors don't bleed past it. Other sequences, such as cursor
+// movement, would break the layout and are removed.
+func InterpretEscapedANSI(s string) string {
+	styled := false
+	out := replaceEscapedCSI(s, func(seq string) string {
+		if !strings.HasSuffix(seq, "m") {
+			return ""
+		}
+		styled = true
+		return "\x1b" + seq[len(`\u001b`):]
+	})
+	if styled {
+		out += "\x1b[0m"
+	}
+	return out
+}
+
+// replaceEscapedCSI replaces each JSON-escaped CSI sequence in s with the
+// result of fn. JSON escapes are walked in pairs so the text of an escaped
+// backslash followed by u001b isn't mistaken for one.
+func replaceEscapedCSI(s string, fn func(seq string) string) string {
+	if !strings.Contains(s, `\u001b`) && !strings.Contains(s, `\u001B`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		if s[i] != '\\' {
+			b.WriteByte(s[i])
+			i++
+			continue
+		}
+		if loc := escapedCSI.FindStringIndex(s[i:]); loc != nil {
+			b.WriteString(fn(s[i : i+loc[1]]))
+			i += loc[1]
+			continue
+		}
+		end := min(i+2, len(s))
+		b.WriteString(s[i:end])
+		i = end
+	}
+	return b.String()
+}
@@ -0,0 +1,59 @@
+package parser
+
+import "testing"
+
+// TestStripANSI verifies escape sequences are removed and other text,
+// including plain control characters, is kept.
+func TestStripANSI(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"plain", "plain"},
+		{"\x1b[31mred\x1b[0m text", "red text"},
+		{"\x1b[1;38;5;196mbold\x1b[m", "bold"},
+		{"\x1b]8;;http://example.com\x07link\x1b]8;;\x07", "link"},
+		{"tab\tstays", "tab\tstays"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := StripANSI(tt.input); got != tt.want {
+				t.Errorf("StripANSI(%q): expected %q, got %q", tt.input, tt.want, got)
+			}
+		})
+	}
+
+	if got := NormalizeCell("\x1b[33mwarn\x1b[0m\tok"); got != "warn ok" {
+		t.Errorf("NormalizeCell: expected ANSI codes stripped, got %q", got)
+	}
+}
+
+// TestEscapedANSI verifies JSON-escaped sequences are stripped or turned
+// into real ones, that only color sequences are interpreted, and that an
+// escaped backslash before u001b is left alone.
+func TestEscapedANSI(t *testing.T) {
+	tests := []struct {
+		input     string
+		strip     string
+		interpret string
+	}{
+		{`"msg": "hi"`, `"msg": "hi"`, `"msg": "hi"`},
+		{
+			`"msg": "\u001b[31mred\u001B[0m",`,
+			`"msg": "red",`,
+			"\"msg\": \"\x1b[31mred\x1b[0m\",\x1b[0m",
+		},
+		{`"msg": "\u001b[2Jclear"`, `"msg": "clear"`, `"msg": "clear"`},
+		{`"path": "C:\\u001b[31m"`, `"path": "C:\\u001b[31m"`, `"path": "C:\\u001b[31m"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := StripEscapedANSI(tt.input); got != tt.strip {
+				t.Errorf("StripEscapedANSI: expected %q, got %q", tt.strip, got)
+			}
+			if got := InterpretEscapedANSI(tt.input); got != tt.interpret {
+				t.Errorf("InterpretEscapedANSI: expected %q, got %q", tt.interpret, got)
+			}
+		})
+	}
+}
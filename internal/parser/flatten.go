@@ -0,0 +1,67 @@
+package parser
+
+import (
+	"strconv"
+
+	"github.com/tidwall/gjson"
+)
+
+// KV is one flattened field of a log record.
+type KV struct {
+	// Key is the dotted path to the field, such as "source.file" or
+	// "items.0".
+	Key string
+	// Value is the field's value: strings unquoted, other scalars as
+	// written, and empty objects or arrays as {} or [].
+	Value string
+}
+
+// Flatten turns a JSON object into one KV per leaf value, in the order the
+// fields appear. Nested object keys are joined with dots and array elements
+// use their index, so {"items":[{"id":1}]} yields items.0.id = 1.
+// Returns nil if raw is not a JSON object.
+func Flatten(raw []byte) []KV {
+	result := gjson.ParseBytes(raw)
+	if !result.IsObject() {
+		return nil
+	}
+	var kvs []KV
+	flattenInto(&kvs, "", result)
+	return kvs
+}
+
+// flattenInto appends the leaves of value under prefix to kvs.
+func flattenInto(kvs *[]KV, prefix string, value gjson.Result) {
+	if !value.IsObject() && !value.IsArray() {
+		v := value.Raw
+		if value.Type == gjson.String {
+			v = value.Str
+		}
+		*kvs = append(*kvs, KV{Key: prefix, Value: v})
+		return
+	}
+
+	empty := true
+	i := 0
+	value.ForEach(func(key, child gjson.Result) bool {
+		empty = false
+		name := key.String()
+		if value.IsArray() {
+			name = strconv.Itoa(i)
+			i++
+		}
+		if prefix != "" {
+			name = prefix + "." + name
+		}
+		flattenInto(kvs, name, child)
+		return true
+	})
+
+	if empty && prefix != "" {
+		v := "{}"
+		if value.IsArray() {
+			v = "[]"
+		}
+		*kvs = append(*kvs, KV{Key: prefix, Value: v})
+	}
+}
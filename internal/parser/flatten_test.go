@@ -0,0 +1,55 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestFlatten verifies nested objects and arrays flatten to dotted paths in
+// field order.
+func TestFlatten(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []KV
+	}{
+		{
+			name:  "nested object",
+			input: `{"level":"info","source":{"file":"main.go","line":42},"ok":true}`,
+			want: []KV{
+				{"level", "info"},
+				{"source.file", "main.go"},
+				{"source.line", "42"},
+				{"ok", "true"},
+			},
+		},
+		{
+			name:  "arrays use indexes",
+			input: `{"items":["a",{"id":1}],"tags":[],"meta":{},"err":null}`,
+			want: []KV{
+				{"items.0", "a"},
+				{"items.1.id", "1"},
+				{"tags", "[]"},
+				{"meta", "{}"},
+				{"err", "null"},
+			},
+		},
+		{
+			name:  "not an object",
+			input: `[1,2]`,
+		},
+		{
+			name:  "empty input",
+			input: ``,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Flatten([]byte(tt.input))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
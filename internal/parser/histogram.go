@@ -0,0 +1,66 @@
+package parser
+
+import (
+	"fmt"
+
+	"github.com/lbe/jsonlogviewer/internal/index"
+)
+
+// FieldHistogram counts the values of the field at a gjson path, such as
+// "user.id", over every line of idx. Values are counted as ExtractField
+// returns them; lines without the field, or that aren't JSON, count under
+// the empty string.
+func FieldHistogram(idx *index.Index, path string) (map[string]int, error) {
+	return FieldHistogramLines(idx, path, nil)
+}
+
+// FieldHistogramLines is like FieldHistogram but counts only the given
+// lines, such as those an active filter shows. A nil lines counts every
+// line.
+func FieldHistogramLines(idx *index.Index, path string, lines []int) (map[string]int, error) {
+	counts := make(map[string]int)
+	err := eachLine(idx, lines, func(raw []byte) {
+		counts[ExtractField(raw, path)]++
+	})
+	return counts, err
+}
+
+// LevelHistogram counts the lines of idx at each canonical level (see
+// NormalizeLevel), reading levels the way Parse does. Lines without a
+// level, or that don't parse, count under the empty string. A nil lines
+// counts every line.
+func (p *Parser) LevelHistogram(idx *index.Index, lines []int) (map[string]int, error) {
+	counts := make(map[string]int)
+	var entry LogEntry
+	err := eachLine(idx, lines, func(raw []byte) {
+		if p.ParseInto(raw, 0, &entry) != nil {
+			entry.CanonicalLevel = ""
+		}
+		counts[entry.CanonicalLevel]++
+	})
+	return counts, err
+}
+
+// eachLine calls fn with the given lines of idx in order, or with every
+// line when lines is nil. A lazy index is read up to the lines it had
+// indexed when eachLine was called.
+func eachLine(idx *index.Index, lines []int, fn func(raw []byte)) error {
+	if lines == nil {
+		for n, count := 1, idx.LineCount(); n <= count; n++ {
+			raw, err := idx.GetLine(n)
+			if err != nil {
+				return fmt.Errorf("line %d: %w", n, err)
+			}
+			fn(raw)
+		}
+		return nil
+	}
+	for _, n := range lines {
+		raw, err := idx.GetLine(n)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", n, err)
+		}
+		fn(raw)
+	}
+	return nil
+}
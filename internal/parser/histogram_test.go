@@ -0,0 +1,55 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestFieldHistogram verifies values are counted per line, with lines
+// missing the field or not JSON under "", and that a line list narrows
+// the count.
+func TestFieldHistogram(t *testing.T) {
+	idx := openTestIndex(t, `{"user":{"id":"alice"}}
+{"user":{"id":"bob"}}
+{"user":{"id":"alice"}}
+{"msg":"anonymous"}
+not json`)
+
+	got, err := FieldHistogram(idx, "user.id")
+	if err != nil {
+		t.Fatalf("FieldHistogram failed: %v", err)
+	}
+	if want := map[string]int{"alice": 2, "bob": 1, "": 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+
+	got, err = FieldHistogramLines(idx, "user.id", []int{2, 3})
+	if err != nil {
+		t.Fatalf("FieldHistogramLines failed: %v", err)
+	}
+	if want := map[string]int{"alice": 1, "bob": 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+
+	if _, err := FieldHistogramLines(idx, "user.id", []int{9}); err == nil {
+		t.Error("expected an error for a line past the end")
+	}
+}
+
+// TestLevelHistogram verifies levels are counted by canonical name, with
+// lines lacking a level or not parsing under "".
+func TestLevelHistogram(t *testing.T) {
+	idx := openTestIndex(t, `{"level":"warning"}
+{"level":"WARN"}
+{"level":"err"}
+{"msg":"no level"}
+not json`)
+
+	got, err := New().LevelHistogram(idx, nil)
+	if err != nil {
+		t.Fatalf("LevelHistogram failed: %v", err)
+	}
+	if want := map[string]int{"WARN": 2, "ERROR": 1, "": 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
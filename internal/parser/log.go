@@ -0,0 +1,14 @@
+package parser
+
+// maxLoggedLine is how many bytes of a line failure logs include.
+const maxLoggedLine = 200
+
+// LogText returns raw as text for a log record, cut to its first
+// maxLoggedLine bytes with "…" marking the cut, so a huge line can't
+// swamp the log.
+func LogText(raw []byte) string {
+	if len(raw) <= maxLoggedLine {
+		return string(raw)
+	}
+	return string(raw[:maxLoggedLine]) + "…"
+}
@@ -0,0 +1,20 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestLogText verifies short lines are logged whole and long ones are cut
+// to their first bytes with the cut marked.
+func TestLogText(t *testing.T) {
+	if got := LogText([]byte("<not json>")); got != "<not json>" {
+		t.Errorf("expected a short line unchanged, got %q", got)
+	}
+
+	long := "<" + strings.Repeat("x", 500)
+	want := "<" + strings.Repeat("x", maxLoggedLine-1) + "…"
+	if got := LogText([]byte(long)); got != want {
+		t.Errorf("expected the line cut to %d bytes, got %q", maxLoggedLine, got)
+	}
+}
@@ -0,0 +1,259 @@
+package parser
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/tidwall/gjson"
+)
+
+// Format is the syntax a Parser reads log lines in.
+type Format int
+
+const (
+	// FormatAuto picks the format of each line with DetectFormat.
+	FormatAuto Format = iota
+	// FormatJSON reads every line as a JSON object.
+	FormatJSON
+	// FormatLogfmt reads every line as logfmt key=value pairs.
+	FormatLogfmt
+)
+
+// formatNames are the names ParseFormat accepts, indexed by Format.
+var formatNames = [...]string{"auto", "json", "logfmt"}
+
+// String returns the format's name as accepted by ParseFormat.
+func (f Format) String() string {
+	if f < 0 || int(f) >= len(formatNames) {
+		return fmt.Sprintf("Format(%d)", int(f))
+	}
+	return formatNames[f]
+}
+
+// ParseFormat returns the Format named name: "auto", "json", or "logfmt".
+func ParseFormat(name string) (Format, error) {
+	for i, n := range formatNames {
+		if n == name {
+			return Format(i), nil
+		}
+	}
+	return FormatAuto, fmt.Errorf("unknown format %q (want auto, json, or logfmt)", name)
+}
+
+// DetectFormat guesses the format of one line: JSON when its first
+// non-blank byte opens an object, logfmt otherwise. In auto mode a Parser
+// also checks that a line it would read as logfmt looks like it (see
+// looksLogfmt), so plain text isn't mistaken for pairs.
+func DetectFormat(raw []byte) Format {
+	trimmed := bytes.TrimLeft(raw, " \t")
+	if len(trimmed) > 0 && trimmed[0] == '{' {
+		return FormatJSON
+	}
+	return FormatLogfmt
+}
+
+// SetFormat makes the parser read every line in format f. FormatAuto, the
+// default, detects the format of each line, so a file mixing JSON and
+// logfmt lines reads correctly.
+func (p *Parser) SetFormat(f Format) {
+	p.format = f
+}
+
+// Format returns the configured line format.
+func (p *Parser) Format() Format {
+	return p.format
+}
+
+// IsLogfmt reports whether p reads raw as logfmt. In auto mode that's a
+// line that doesn't start with '{' and looks like logfmt (see
+// looksLogfmt); other lines fall back to JSON.
+func (p *Parser) IsLogfmt(raw []byte) bool {
+	_, ok, _ := p.logfmtLine(raw)
+	return ok
+}
+
+// Fields returns the fields of raw as the detail pane lists them: the
+// key=value pairs of a logfmt line in the order written, or the flattened
+// leaves of a JSON object (see Flatten). Returns nil when raw is neither.
+func (p *Parser) Fields(raw []byte) []KV {
+	kvs, ok, err := p.logfmtLine(raw)
+	if !ok {
+		return Flatten(raw)
+	}
+	if err != nil {
+		return nil
+	}
+	return kvs
+}
+
+// logfmtLine returns the pairs of raw when p reads it as logfmt; ok is
+// false for lines read as JSON. In auto mode a line that doesn't parse as
+// logfmt, or doesn't look like it, is read as JSON instead, so plain text
+// keeps behaving as it did before logfmt support: unparsed, and marked as
+// malformed.
+func (p *Parser) logfmtLine(raw []byte) (kvs []KV, ok bool, err error) {
+	switch p.format {
+	case FormatJSON:
+		return nil, false, nil
+	case FormatLogfmt:
+		kvs, err = LogfmtFields(raw)
+		return kvs, true, err
+	}
+	if DetectFormat(raw) != FormatLogfmt {
+		return nil, false, nil
+	}
+	kvs, pairs, err := logfmtPairs(raw)
+	if err != nil || !p.looksLogfmt(kvs, pairs) {
+		return nil, false, nil
+	}
+	return kvs, true, nil
+}
+
+// looksLogfmt reports whether a line split into kvs, pairs of them with an
+// "=", is logfmt rather than text that happens to hold an "=": every word
+// is a pair, or one of the time, level, or message keys is there. A line
+// such as "2024/01/02 15:04:05 connection failed: retries=3" is not.
+func (p *Parser) looksLogfmt(kvs []KV, pairs int) bool {
+	if pairs == len(kvs) {
+		return true
+	}
+	for _, kv := range kvs {
+		if kv.Key == p.timeField || slices.Contains(timeFields, kv.Key) ||
+			slices.Contains(levelFields, kv.Key) || slices.Contains(msgFields, kv.Key) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseLogfmt extracts the table fields from a logfmt line such as
+//
+//	time=2024-01-15T10:30:00Z level=info msg="user logged in" user=alice
+//
+// looking them up under the same key names as JSON lines. The row
+// parameter is the 1-indexed line number for display. Unlike Parser.Parse
+// it never truncates the message.
+func ParseLogfmt(raw []byte, row int) (*LogEntry, error) {
+	kvs, err := LogfmtFields(raw)
+	if err != nil {
+		return nil, err
+	}
+	entry := &LogEntry{}
+	fillLogfmt(entry, raw, row, kvs, "")
+	return entry, nil
+}
+
+// fillLogfmt sets entry from the pairs of a logfmt line, reading the time
+// from timeField when it's set.
+func fillLogfmt(entry *LogEntry, raw []byte, row int, kvs []KV, timeField string) {
+	*entry = LogEntry{Row: row, Raw: raw}
+	level := firstPair(kvs, levelFields)
+	if level != "" && allDigits(level) {
+		level = levelName(gjson.Result{Type: gjson.Number, Raw: level})
+	}
+	entry.Level = level
+	entry.CanonicalLevel = NormalizeLevel(level)
+	entry.Msg = firstPair(kvs, msgFields)
+	if timeField != "" {
+		entry.RawTime = firstPair(kvs, []string{timeField})
+	} else {
+		entry.RawTime = firstPair(kvs, timeFields)
+	}
+	setTime(entry)
+}
+
+// firstPair returns the value of the first of keys with a non-empty value
+// in kvs, or "" when none is set.
+func firstPair(kvs []KV, keys []string) string {
+	for _, key := range keys {
+		for _, kv := range kvs {
+			if kv.Key == key && kv.Value != "" {
+				return kv.Value
+			}
+		}
+	}
+	return ""
+}
+
+// LogfmtFields splits a logfmt line into its pairs in the order written.
+// Values may be bare (up to the next space) or double-quoted with Go
+// escapes; a key without "=" has an empty value. It fails on an
+// unterminated quote, a pair without a key, or a line with no key=value
+// pair at all.
+func LogfmtFields(raw []byte) ([]KV, error) {
+	kvs, _, err := logfmtPairs(raw)
+	return kvs, err
+}
+
+// logfmtPairs splits raw like LogfmtFields, also returning how many of the
+// words were key=value pairs rather than bare keys.
+func logfmtPairs(raw []byte) ([]KV, int, error) {
+	var kvs []KV
+	pairs := 0
+	for i := 0; i < len(raw); {
+		if isLogfmtSpace(raw[i]) {
+			i++
+			continue
+		}
+		start := i
+		for i < len(raw) && !isLogfmtSpace(raw[i]) && raw[i] != '=' && raw[i] != '"' {
+			i++
+		}
+		if i == start {
+			return nil, 0, fmt.Errorf("logfmt: missing key at offset %d", i)
+		}
+		kv := KV{Key: string(raw[start:i])}
+		if i < len(raw) && raw[i] == '"' {
+			return nil, 0, fmt.Errorf("logfmt: quote in key at offset %d", i)
+		}
+		if i < len(raw) && raw[i] == '=' {
+			i++
+			pairs++
+			value, next, err := logfmtValue(raw, i)
+			if err != nil {
+				return nil, 0, err
+			}
+			kv.Value, i = value, next
+		}
+		kvs = append(kvs, kv)
+	}
+	if pairs == 0 {
+		return nil, 0, fmt.Errorf("logfmt: no key=value pairs")
+	}
+	return kvs, pairs, nil
+}
+
+// logfmtValue reads the value starting at raw[i], returning it and the
+// offset just past it.
+func logfmtValue(raw []byte, i int) (string, int, error) {
+	if i >= len(raw) || raw[i] != '"' {
+		start := i
+		for i < len(raw) && !isLogfmtSpace(raw[i]) {
+			i++
+		}
+		return string(raw[start:i]), i, nil
+	}
+
+	start := i
+	for i++; i < len(raw); i++ {
+		switch raw[i] {
+		case '\\':
+			i++
+		case '"':
+			quoted := string(raw[start : i+1])
+			if value, err := strconv.Unquote(quoted); err == nil {
+				return value, i + 1, nil
+			}
+			// An escape Go doesn't know is kept as written
+			return quoted[1 : len(quoted)-1], i + 1, nil
+		}
+	}
+	return "", 0, fmt.Errorf("logfmt: unterminated quote at offset %d", start)
+}
+
+// isLogfmtSpace reports whether b separates logfmt pairs.
+func isLogfmtSpace(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\r' || b == '\n'
+}
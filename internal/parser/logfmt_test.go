@@ -0,0 +1,145 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// TestLogfmtFields verifies bare, quoted, and empty values split into
+// pairs in the order written, and that malformed lines are rejected.
+func TestLogfmtFields(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    []KV
+		wantErr bool
+	}{
+		{
+			name:  "bare and quoted values",
+			input: `level=info msg="user logged in" user=alice`,
+			want:  []KV{{"level", "info"}, {"msg", "user logged in"}, {"user", "alice"}},
+		},
+		{
+			name:  "escapes and empty values",
+			input: `msg="say \"hi\"\tnow" empty= flag err="a\qb"`,
+			want:  []KV{{"msg", "say \"hi\"\tnow"}, {"empty", ""}, {"flag", ""}, {"err", `a\qb`}},
+		},
+		{
+			name:  "extra spacing",
+			input: "  a=1\t b=2  ",
+			want:  []KV{{"a", "1"}, {"b", "2"}},
+		},
+		{name: "plain text", input: "panic: runtime error", wantErr: true},
+		{name: "unterminated quote", input: `msg="oops level=info`, wantErr: true},
+		{name: "missing key", input: `=value a=1`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := LogfmtFields([]byte(tt.input))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestParseLogfmt verifies the table fields come from the usual key names,
+// with epochs converted and numeric levels named.
+func TestParseLogfmt(t *testing.T) {
+	entry, err := ParseLogfmt([]byte(`ts=1705314600 severity=3 message="disk full" host=db1`), 7)
+	if err != nil {
+		t.Fatalf("ParseLogfmt failed: %v", err)
+	}
+	want := LogEntry{
+		Row:            7,
+		Time:           "2024-01-15T10:30:00Z",
+		RawTime:        "1705314600",
+		Timestamp:      time.Unix(1705314600, 0).UTC(),
+		Level:          "err",
+		CanonicalLevel: "ERROR",
+		Msg:            "disk full",
+		Raw:            entry.Raw,
+	}
+	if !reflect.DeepEqual(*entry, want) {
+		t.Errorf("got %+v, want %+v", *entry, want)
+	}
+
+	if _, err := ParseLogfmt([]byte("not logfmt"), 1); err == nil {
+		t.Error("expected an error for a line without key=value pairs")
+	}
+}
+
+// TestParserFormat verifies auto mode reads each line in its own format,
+// falls back to JSON for plain text, and that a forced format applies to
+// every line.
+func TestParserFormat(t *testing.T) {
+	jsonLine := []byte(`{"level":"warn","msg":"from json"}`)
+	logfmtLine := []byte(`level=info msg="from logfmt"`)
+
+	p := New()
+	for _, tt := range []struct {
+		raw     []byte
+		wantMsg string
+		logfmt  bool
+	}{
+		{jsonLine, "from json", false},
+		{logfmtLine, "from logfmt", true},
+	} {
+		entry, err := p.Parse(tt.raw, 1)
+		if err != nil {
+			t.Fatalf("Parse(%s) failed: %v", tt.raw, err)
+		}
+		if entry.Msg != tt.wantMsg {
+			t.Errorf("Parse(%s): msg = %q, want %q", tt.raw, entry.Msg, tt.wantMsg)
+		}
+		if got := p.IsLogfmt(tt.raw); got != tt.logfmt {
+			t.Errorf("IsLogfmt(%s) = %v, want %v", tt.raw, got, tt.logfmt)
+		}
+	}
+	for _, text := range []string{
+		"panic: runtime error",
+		"2024/01/02 15:04:05 connection failed: retries=3",
+	} {
+		if p.IsLogfmt([]byte(text)) {
+			t.Errorf("expected plain text %q to fall back to JSON in auto mode", text)
+		}
+		if _, err := p.Parse([]byte(text), 1); err == nil {
+			t.Errorf("expected plain text %q to fail to parse", text)
+		}
+	}
+	// A known key makes a line logfmt even with bare words in it
+	if entry, err := p.Parse([]byte(`starting msg="ready" verbose`), 1); err != nil || entry.Msg != "ready" {
+		t.Errorf("expected a line with a msg key read as logfmt, got %+v, %v", entry, err)
+	}
+	if got := p.Fields(logfmtLine); !reflect.DeepEqual(got, []KV{{"level", "info"}, {"msg", "from logfmt"}}) {
+		t.Errorf("Fields = %v", got)
+	}
+
+	p.SetFormat(FormatJSON)
+	if entry, err := p.Parse(logfmtLine, 1); err == nil && entry.Msg != "" {
+		t.Errorf("expected -format json to ignore logfmt pairs, got msg %q", entry.Msg)
+	}
+
+	p.SetFormat(FormatLogfmt)
+	if _, err := p.Parse(jsonLine, 1); err == nil {
+		t.Error("expected -format logfmt to reject a JSON line")
+	}
+}
+
+// TestParseFormat verifies the -format names round-trip and unknown names
+// are rejected.
+func TestParseFormat(t *testing.T) {
+	for _, f := range []Format{FormatAuto, FormatJSON, FormatLogfmt} {
+		got, err := ParseFormat(f.String())
+		if err != nil || got != f {
+			t.Errorf("ParseFormat(%q) = %v, %v", f.String(), got, err)
+		}
+	}
+	if _, err := ParseFormat("xml"); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
@@ -0,0 +1,90 @@
+package parser
+
+import (
+	"fmt"
+
+	"github.com/lbe/jsonlogviewer/internal/index"
+)
+
+// maxSkippedLines caps how many skipped lines a SkippedLinesError lists, so
+// a file of mostly non-JSON lines doesn't hold an error per line.
+const maxSkippedLines = 10
+
+// LineError records a line that couldn't be parsed.
+type LineError struct {
+	// Line is the 1-indexed line number.
+	Line int
+	// Err is why the line was skipped.
+	Err error
+}
+
+// SkippedLinesError summarizes the lines ParseEach and ParseAll skipped
+// because they couldn't be parsed. Every other line was still delivered.
+type SkippedLinesError struct {
+	// Count is the number of lines skipped.
+	Count int
+	// Lines lists the first skipped lines, at most maxSkippedLines of them.
+	Lines []LineError
+}
+
+// Error describes how many lines were skipped and the first of them.
+func (e *SkippedLinesError) Error() string {
+	first := e.Lines[0]
+	if e.Count == 1 {
+		return fmt.Sprintf("skipped line %d: %v", first.Line, first.Err)
+	}
+	return fmt.Sprintf("skipped %d lines, first line %d: %v", e.Count, first.Line, first.Err)
+}
+
+// ParseEach parses every line of idx in order and calls fn with each
+// entry, so a whole file can be processed without holding all of its
+// entries at once. The entry passed to fn is reused for the next line;
+// copy it to keep it. Its Raw field points into idx and is valid until idx
+// is closed.
+//
+// Lines that don't parse are skipped and reported together in a
+// *SkippedLinesError once every line has been seen. An error from fn or
+// from reading idx stops the walk and is returned instead. A lazy index is
+// walked up to the lines it had indexed when ParseEach was called.
+func (p *Parser) ParseEach(idx *index.Index, fn func(entry *LogEntry) error) error {
+	var skipped *SkippedLinesError
+	var entry LogEntry
+	for n, count := 1, idx.LineCount(); n <= count; n++ {
+		line, err := idx.GetLine(n)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", n, err)
+		}
+		if err := p.ParseInto(line, n, &entry); err != nil {
+			if skipped == nil {
+				skipped = &SkippedLinesError{}
+			}
+			skipped.Count++
+			if len(skipped.Lines) < maxSkippedLines {
+				skipped.Lines = append(skipped.Lines, LineError{Line: n, Err: err})
+			}
+			continue
+		}
+		if err := fn(&entry); err != nil {
+			return err
+		}
+	}
+	if skipped != nil {
+		return skipped
+	}
+	return nil
+}
+
+// ParseAll parses every line of idx and returns the entries in line order.
+// Lines that don't parse are left out and reported in a
+// *SkippedLinesError, returned alongside the entries that did parse. Each
+// entry's Raw field points into idx and is valid until idx is closed.
+// For large files, ParseEach avoids holding every entry in memory.
+func (p *Parser) ParseAll(idx *index.Index) ([]*LogEntry, error) {
+	entries := make([]*LogEntry, 0, idx.LineCount())
+	err := p.ParseEach(idx, func(entry *LogEntry) error {
+		e := *entry
+		entries = append(entries, &e)
+		return nil
+	})
+	return entries, err
+}
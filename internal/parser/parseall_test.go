@@ -0,0 +1,101 @@
+package parser
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/lbe/jsonlogviewer/internal/index"
+)
+
+// parseAllContent has two lines that can't be parsed among three entries.
+const parseAllContent = `{"level":"info","msg":"one"}
+
+{"level":"warn","msg":"two"}
+garbage text
+{"level":"error","msg":"three"}
+`
+
+// openTestIndex indexes content in memory.
+func openTestIndex(t *testing.T, content string) *index.Index {
+	t.Helper()
+	idx, err := index.OpenReader(strings.NewReader(content), "test")
+	if err != nil {
+		t.Fatalf("OpenReader failed: %v", err)
+	}
+	t.Cleanup(func() { _ = idx.Close() })
+	return idx
+}
+
+// TestParseAll verifies every parseable line is returned in order with its
+// line number, and the skipped lines are summarized in the error.
+func TestParseAll(t *testing.T) {
+	idx := openTestIndex(t, parseAllContent)
+
+	entries, err := New().ParseAll(idx)
+	var skipped *SkippedLinesError
+	if !errors.As(err, &skipped) {
+		t.Fatalf("expected a SkippedLinesError, got %v", err)
+	}
+	if skipped.Count != 2 || skipped.Lines[0].Line != 2 || skipped.Lines[1].Line != 4 {
+		t.Errorf("expected lines 2 and 4 skipped, got %+v", skipped)
+	}
+	if !strings.Contains(err.Error(), "skipped 2 lines") {
+		t.Errorf("expected a summary of skipped lines, got %q", err)
+	}
+
+	want := []struct {
+		row int
+		msg string
+	}{{1, "one"}, {3, "two"}, {5, "three"}}
+	if len(entries) != len(want) {
+		t.Fatalf("expected %d entries, got %d", len(want), len(entries))
+	}
+	for i, w := range want {
+		if entries[i].Row != w.row || entries[i].Msg != w.msg {
+			t.Errorf("entry %d: expected row %d %q, got row %d %q", i, w.row, w.msg, entries[i].Row, entries[i].Msg)
+		}
+	}
+
+	clean := openTestIndex(t, `{"msg":"a"}`+"\n"+`{"msg":"b"}`)
+	if entries, err := New().ParseAll(clean); err != nil || len(entries) != 2 {
+		t.Errorf("expected 2 entries and no error, got %d and %v", len(entries), err)
+	}
+}
+
+// TestParseEach verifies entries are streamed to the callback and that an
+// error from it stops the walk.
+func TestParseEach(t *testing.T) {
+	idx := openTestIndex(t, parseAllContent)
+
+	var msgs []string
+	stop := errors.New("stop")
+	err := New().ParseEach(idx, func(entry *LogEntry) error {
+		msgs = append(msgs, entry.Msg)
+		if len(msgs) == 2 {
+			return stop
+		}
+		return nil
+	})
+	if !errors.Is(err, stop) {
+		t.Errorf("expected the callback's error, got %v", err)
+	}
+	if strings.Join(msgs, ",") != "one,two" {
+		t.Errorf("expected the walk to stop after two entries, got %q", msgs)
+	}
+}
+
+// TestSkippedLinesErrorCap verifies a file of bad lines reports them all
+// in Count but keeps only the first few.
+func TestSkippedLinesErrorCap(t *testing.T) {
+	idx := openTestIndex(t, strings.Repeat("garbage\n", 50))
+
+	err := New().ParseEach(idx, func(*LogEntry) error { return nil })
+	var skipped *SkippedLinesError
+	if !errors.As(err, &skipped) {
+		t.Fatalf("expected a SkippedLinesError, got %v", err)
+	}
+	if skipped.Count != 50 || len(skipped.Lines) != maxSkippedLines {
+		t.Errorf("expected 50 counted and %d listed, got %d and %d", maxSkippedLines, skipped.Count, len(skipped.Lines))
+	}
+}
@@ -1,15 +1,20 @@
 // Package parser provides JSON log parsing using gjson for fast extraction
 // of table columns and standard encoding/json for pretty-print formatting.
 // It handles standard log fields (time, level, msg) and preserves original
-// key order when formatting.
+// key order when formatting. Lines in logfmt are read alongside JSON.
 package parser
 
 import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
 
+	"github.com/charmbracelet/x/ansi"
 	"github.com/lbe/jsonlogviewer/internal/pool"
 	"github.com/tidwall/gjson"
 )
@@ -18,13 +23,23 @@ import (
 type LogEntry struct {
 	// Row is the 1-indexed line number in the source file.
 	Row int
-	// Time is the timestamp field value.
+	// Time is the timestamp field value. Unix epoch numbers are converted
+	// to RFC 3339 in UTC; other values are kept as written.
 	Time string
-	// Level is the log level (DEBUG, INFO, WARN, ERROR, etc.).
+	// RawTime is the timestamp field value exactly as written.
+	RawTime string
+	// Timestamp is RawTime parsed by ParseTime, or the zero time when it
+	// is missing or not recognized.
+	Timestamp time.Time
+	// Level is the log level (DEBUG, INFO, WARN, ERROR, etc.) as written,
+	// with numeric syslog severities named.
 	Level string
+	// CanonicalLevel is Level normalized by NormalizeLevel, so aliases
+	// such as "warning" and "WARN" filter and color alike.
+	CanonicalLevel string
 	// Msg is the log message.
 	Msg string
-	// Raw contains the complete raw JSON.
+	// Raw contains the complete raw line, JSON or logfmt.
 	Raw []byte
 }
 
@@ -32,11 +47,31 @@ type LogEntry struct {
 type Parser struct {
 	// bufferPool is used for pretty-printing JSON.
 	bufferPool *pool.GenSyncPool[*bytes.Buffer]
+	// maxMsgLen is the length Msg is truncated to; 0 disables truncation.
+	maxMsgLen int
+	// timeField is the gjson path of the timestamp, overriding the
+	// built-in field names when set.
+	timeField string
+	// format is the syntax lines are read in.
+	format Format
+	// indent is the indentation of each level of FormatPretty output.
+	indent string
 }
 
+// DefaultMaxMsgLen is the message length a new Parser truncates to.
+const DefaultMaxMsgLen = 100
+
+// DefaultIndent is the indentation a new Parser pretty-prints with.
+const DefaultIndent = "  "
+
+// maxIndentSpaces is the widest indentation ParseIndent accepts.
+const maxIndentSpaces = 8
+
 // New creates a new Parser with initialized buffer pool.
 func New() *Parser {
 	return &Parser{
+		maxMsgLen: DefaultMaxMsgLen,
+		indent:    DefaultIndent,
 		bufferPool: pool.New(
 			func() *bytes.Buffer {
 				return bytes.NewBuffer(make([]byte, 0, 8192))
@@ -48,71 +83,190 @@ func New() *Parser {
 	}
 }
 
-// Parse extracts fields from a raw JSON log line.
-// The row parameter is the 1-indexed line number for display.
-func (p *Parser) Parse(raw []byte, row int) (*LogEntry, error) {
-	if len(raw) == 0 {
-		return nil, fmt.Errorf("empty line")
+// SetMaxMsgLen sets the length messages are truncated to for table
+// display, including the "..." marker. Zero or less disables truncation.
+func (p *Parser) SetMaxMsgLen(n int) {
+	if n < 0 {
+		n = 0
 	}
+	p.maxMsgLen = n
+}
 
-	result := gjson.ParseBytes(raw)
-	if !result.Exists() {
-		return nil, fmt.Errorf("invalid JSON")
-	}
+// MaxMsgLen returns the message truncation length; 0 means unlimited.
+func (p *Parser) MaxMsgLen() int {
+	return p.maxMsgLen
+}
 
-	entry := &LogEntry{
-		Row:   row,
-		Raw:   raw,
-		Time:  result.Get("time").String(),
-		Level: result.Get("level").String(),
-		Msg:   result.Get("msg").String(),
+// SetTimeField makes the parser read timestamps from the given gjson path
+// (e.g. "event_time" or "meta.ts") instead of detecting one of the usual
+// field names. An empty path restores auto-detection.
+func (p *Parser) SetTimeField(path string) {
+	p.timeField = path
+}
+
+// TimeField returns the configured timestamp path, or "" when the
+// timestamp field is auto-detected.
+func (p *Parser) TimeField() string {
+	return p.timeField
+}
+
+// SetIndent sets the indentation of each nesting level in FormatPretty
+// output, such as four spaces or a tab. An empty indent restores
+// DefaultIndent.
+func (p *Parser) SetIndent(indent string) {
+	if indent == "" {
+		indent = DefaultIndent
 	}
+	p.indent = indent
+}
+
+// Indent returns the indentation FormatPretty uses for each level.
+func (p *Parser) Indent() string {
+	return p.indent
+}
 
-	// Handle case-sensitive variations
-	if entry.Time == "" {
-		entry.Time = result.Get("Time").String()
+// ParseIndent returns the indentation named by spec: a number of spaces
+// from 1 to 8, or "tab".
+func ParseIndent(spec string) (string, error) {
+	if spec == "tab" {
+		return "\t", nil
 	}
-	if entry.Time == "" {
-		entry.Time = result.Get("timestamp").String()
+	n, err := strconv.Atoi(spec)
+	if err != nil || n < 1 || n > maxIndentSpaces {
+		return "", fmt.Errorf("invalid indent %q (want 1-%d spaces or tab)", spec, maxIndentSpaces)
 	}
-	if entry.Time == "" {
-		entry.Time = result.Get("Timestamp").String()
+	return strings.Repeat(" ", n), nil
+}
+
+// Parse extracts fields from a raw JSON or logfmt log line.
+// The row parameter is the 1-indexed line number for display.
+func (p *Parser) Parse(raw []byte, row int) (*LogEntry, error) {
+	entry := &LogEntry{}
+	if err := p.ParseInto(raw, row, entry); err != nil {
+		return nil, err
 	}
-	if entry.Time == "" {
-		entry.Time = result.Get("ts").String()
+	return entry, nil
+}
+
+// ParseInto is like Parse but fills a caller-provided entry, so a caller
+// parsing many lines in a row can reuse one LogEntry instead of allocating
+// one per line. Every field of entry is overwritten on success; on error
+// entry is left unchanged.
+func (p *Parser) ParseInto(raw []byte, row int, entry *LogEntry) error {
+	if len(raw) == 0 {
+		return fmt.Errorf("empty line")
 	}
 
-	if entry.Level == "" {
-		entry.Level = result.Get("Level").String()
+	if kvs, ok, err := p.logfmtLine(raw); ok {
+		if err != nil {
+			return err
+		}
+		fillLogfmt(entry, raw, row, kvs, p.timeField)
+		p.truncateMsg(entry)
+		return nil
 	}
-	if entry.Level == "" {
-		entry.Level = result.Get("severity").String()
+
+	// gjson reads the leading number of text such as "2024/01/02 ..." as
+	// a value, so anything but an object must be valid JSON throughout
+	result := gjson.ParseBytes(raw)
+	if !result.Exists() || !result.IsObject() && !json.Valid(raw) {
+		return fmt.Errorf("invalid JSON")
 	}
-	if entry.Level == "" {
-		entry.Level = result.Get("Severity").String()
+
+	*entry = LogEntry{Row: row, Raw: raw}
+	_, level := firstField(result, levelFields)
+	entry.Level = levelName(level)
+	entry.CanonicalLevel = NormalizeLevel(entry.Level)
+	_, msg := firstField(result, msgFields)
+	entry.Msg = msgText(msg)
+
+	// A configured time field replaces detection entirely
+	if p.timeField != "" {
+		entry.RawTime = result.Get(p.timeField).String()
+	} else {
+		_, rawTime := firstField(result, timeFields)
+		entry.RawTime = rawTime.String()
 	}
+	setTime(entry)
+	p.truncateMsg(entry)
+	return nil
+}
 
-	if entry.Msg == "" {
-		entry.Msg = result.Get("Msg").String()
+// setTime sets entry.Time and entry.Timestamp from entry.RawTime,
+// converting Unix epochs.
+func setTime(entry *LogEntry) {
+	entry.Time, entry.Timestamp = entry.RawTime, time.Time{}
+	if t, ok := parseEpoch(entry.RawTime); ok {
+		entry.Time, entry.Timestamp = t.Format(time.RFC3339Nano), t
+	} else if t, ok := ParseTime(entry.RawTime); ok {
+		entry.Timestamp = t
 	}
-	if entry.Msg == "" {
-		entry.Msg = result.Get("message").String()
+}
+
+// truncateMsg cuts entry.Msg to the parser's maximum message length.
+func (p *Parser) truncateMsg(entry *LogEntry) {
+	// Truncate very long messages for table display. The length is in
+	// display cells, so a multi-byte character is never cut in half; no
+	// string is wider than it is long in bytes, which keeps the check cheap
+	if p.maxMsgLen > 0 && len(entry.Msg) > p.maxMsgLen && ansi.StringWidth(entry.Msg) > p.maxMsgLen {
+		tail := "..."
+		if p.maxMsgLen <= 3 {
+			tail = ""
+		}
+		entry.Msg = ansi.Truncate(entry.Msg, p.maxMsgLen, tail)
 	}
-	if entry.Msg == "" {
-		entry.Msg = result.Get("Message").String()
+}
+
+// Field names tried, in order, for each of the fields the table shows.
+var (
+	timeFields  = []string{"time", "Time", "timestamp", "Timestamp", "ts"}
+	levelFields = []string{"level", "Level", "severity", "Severity"}
+	msgFields   = []string{"msg", "Msg", "message", "Message"}
+)
+
+// firstField returns the first of fields with a non-empty value in result,
+// and that value. The key is empty and the value doesn't exist when none
+// is set.
+func firstField(result gjson.Result, fields []string) (key string, value gjson.Result) {
+	for _, field := range fields {
+		if v := result.Get(field); v.String() != "" {
+			return field, v
+		}
 	}
+	return "", gjson.Result{}
+}
+
+// syslogSeverities names the numeric syslog severities, indexed by number.
+var syslogSeverities = [...]string{"emerg", "alert", "crit", "err", "warning", "notice", "info", "debug"}
 
-	// Truncate very long messages for table display
-	const maxMsgLen = 100
-	if len(entry.Msg) > maxMsgLen {
-		entry.Msg = entry.Msg[:maxMsgLen-3] + "..."
+// levelName returns a level value as text, naming the numeric syslog
+// severities 0 to 7 so they color and filter like named levels. Other
+// numbers are kept as written.
+func levelName(v gjson.Result) string {
+	if v.Type == gjson.Number {
+		if n, err := strconv.Atoi(v.Raw); err == nil && n >= 0 && n < len(syslogSeverities) {
+			return syslogSeverities[n]
+		}
 	}
+	return v.String()
+}
 
-	return entry, nil
+// msgText returns a message value as text. An object or array message is
+// compacted onto one line so it fits a table cell.
+func msgText(v gjson.Result) string {
+	if !v.IsObject() && !v.IsArray() {
+		return v.String()
+	}
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, []byte(v.Raw)); err != nil {
+		return v.Raw
+	}
+	return buf.String()
 }
 
-// FormatPretty returns a pretty-printed JSON string with 2-space indentation.
-// It preserves the original key order from the input JSON.
+// FormatPretty returns a pretty-printed JSON string indented with the
+// parser's indent (two spaces unless changed with SetIndent). It preserves
+// the original key order from the input JSON.
 func (p *Parser) FormatPretty(raw []byte) (string, error) {
 	if len(raw) == 0 {
 		return "", fmt.Errorf("empty input")
@@ -129,13 +283,13 @@ func (p *Parser) FormatPretty(raw []byte) (string, error) {
 	defer p.bufferPool.Put(buf)
 
 	// Use json.Indent for pretty-printing with original order
-	if err := json.Indent(buf, raw, "", "  "); err != nil {
+	if err := json.Indent(buf, raw, "", p.indent); err != nil {
 		// If standard indent fails, try with gjson's raw output
 		rawJSON := result.Raw
 		if rawJSON == "" {
 			rawJSON = string(raw)
 		}
-		if err := json.Indent(buf, []byte(rawJSON), "", "  "); err != nil {
+		if err := json.Indent(buf, []byte(rawJSON), "", p.indent); err != nil {
 			return "", fmt.Errorf("failed to format JSON: %w", err)
 		}
 	}
@@ -143,6 +297,104 @@ func (p *Parser) FormatPretty(raw []byte) (string, error) {
 	return buf.String(), nil
 }
 
+// timeLayouts lists the timestamp layouts recognized by ParseTime, most
+// common first. Layouts without a zone are interpreted as UTC.
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999",
+	time.RFC1123Z,
+	time.RFC1123,
+}
+
+// ParseTime parses a timestamp string as found in a log's time field,
+// either in one of the recognized layouts or as a Unix epoch number.
+// It reports false if the value isn't recognized.
+func ParseTime(s string) (time.Time, bool) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, false
+	}
+	if t, ok := parseEpoch(s); ok {
+		return t, true
+	}
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// ParseTimeBound parses the bound of a time range given on the command
+// line: a timestamp ParseTime recognizes, such as RFC 3339, or a duration
+// such as "-1h" or "90m" counted back from now. A duration is taken as
+// that long ago whether or not it has a leading minus.
+func ParseTimeBound(s string, now time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(strings.TrimSpace(s)); err == nil {
+		return now.Add(-d.Abs()), nil
+	}
+	if t, ok := ParseTime(s); ok {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q (want RFC 3339 or a duration such as -1h)", s)
+}
+
+// InTimeRange reports whether an entry with timestamp t falls between
+// since and until, inclusive; a zero bound leaves that end open. A zero t,
+// an entry without a recognized timestamp, is in range unless
+// excludeUntimed is set.
+func InTimeRange(t, since, until time.Time, excludeUntimed bool) bool {
+	if t.IsZero() {
+		return !excludeUntimed
+	}
+	return (since.IsZero() || !t.Before(since)) && (until.IsZero() || !t.After(until))
+}
+
+// parseEpoch interprets a purely numeric value as a Unix timestamp in UTC.
+// The unit follows the number of integer digits: up to 10 is seconds
+// (optionally with a fraction), then milliseconds, microseconds, and
+// nanoseconds at 13, 16, and 19 digits.
+func parseEpoch(s string) (time.Time, bool) {
+	whole, frac, hasFrac := strings.Cut(s, ".")
+	if whole == "" || !allDigits(whole) || (hasFrac && !allDigits(frac)) {
+		return time.Time{}, false
+	}
+	n, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	switch digits := len(whole); {
+	case digits <= 10:
+		var nsec int64
+		if hasFrac && frac != "" {
+			frac = (frac + "000000000")[:9]
+			nsec, _ = strconv.ParseInt(frac, 10, 64)
+		}
+		return time.Unix(n, nsec).UTC(), true
+	case hasFrac:
+		return time.Time{}, false
+	case digits <= 13:
+		return time.UnixMilli(n).UTC(), true
+	case digits <= 16:
+		return time.UnixMicro(n).UTC(), true
+	default:
+		return time.Unix(0, n).UTC(), true
+	}
+}
+
+// allDigits reports whether s consists only of ASCII digits.
+func allDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
 // ExtractField extracts a specific field from raw JSON using gjson path syntax.
 // Supports nested paths like "user.name" or array access like "items.0.id".
 func ExtractField(raw []byte, path string) string {
@@ -150,25 +402,137 @@ func ExtractField(raw []byte, path string) string {
 	return result.String()
 }
 
-// LevelColor returns the lipgloss color for a given log level.
-// Returns an empty string if the level is unrecognized.
+// LevelColor returns the lipgloss color for a given log level, matched by
+// its canonical name (see NormalizeLevel). Returns an empty string if the
+// level is unrecognized.
 func LevelColor(level string) string {
-	switch strings.ToUpper(level) {
+	switch NormalizeLevel(level) {
 	case "DEBUG", "TRACE":
 		return "#808080" // Gray
 	case "INFO":
 		return "#00FF00" // Green
-	case "WARN", "WARNING":
+	case "WARN":
 		return "#FFFF00" // Yellow
 	case "ERROR":
 		return "#FF0000" // Red
-	case "FATAL", "PANIC":
+	case "FATAL":
 		return "#FF00FF" // Magenta
 	default:
 		return "" // Default
 	}
 }
 
+// NormalizeLevel returns the canonical name of a level: TRACE, DEBUG,
+// INFO, WARN, ERROR, or FATAL, whatever case or alias it's written in.
+// Syslog names and the numeric severities 0 to 7 map to their nearest
+// equivalent, e.g. "warning" to WARN, "crit" and "panic" to FATAL, and
+// "notice" to INFO. Other levels are returned upper-cased.
+func NormalizeLevel(level string) string {
+	level = strings.ToUpper(strings.TrimSpace(level))
+	if n, err := strconv.Atoi(level); err == nil && n >= 0 && n < len(syslogSeverities) {
+		level = strings.ToUpper(syslogSeverities[n])
+	}
+	switch level {
+	case "TRACE", "TRC":
+		return "TRACE"
+	case "DEBUG", "DBG":
+		return "DEBUG"
+	case "INFO", "INF", "INFORMATION", "NOTICE":
+		return "INFO"
+	case "WARN", "WARNING", "WRN":
+		return "WARN"
+	case "ERROR", "ERR", "ERRO":
+		return "ERROR"
+	case "FATAL", "FTL", "PANIC", "CRIT", "CRITICAL", "ALERT", "EMERG":
+		return "FATAL"
+	}
+	return level
+}
+
+// LevelRank returns the severity rank of a level name, from 1 for TRACE up
+// to 6 for FATAL, so levels can be compared and filtered by a minimum.
+// Aliases rank with their canonical level (see NormalizeLevel). Unknown
+// or empty levels rank 0.
+func LevelRank(level string) int {
+	switch NormalizeLevel(level) {
+	case "TRACE":
+		return 1
+	case "DEBUG":
+		return 2
+	case "INFO":
+		return 3
+	case "WARN":
+		return 4
+	case "ERROR":
+		return 5
+	case "FATAL":
+		return 6
+	default:
+		return 0
+	}
+}
+
+// LevelTint returns a dark background color for tinting whole table rows of
+// the given level, chosen to keep the LevelColor foreground readable.
+// Only warning and more severe levels are tinted; others return an empty
+// string.
+func LevelTint(level string) string {
+	switch NormalizeLevel(level) {
+	case "WARN":
+		return "#332B00" // Dark amber
+	case "ERROR":
+		return "#3D0F0F" // Dark red
+	case "FATAL":
+		return "#3A0F3A" // Dark magenta
+	default:
+		return ""
+	}
+}
+
+// NormalizeCell prepares a value for display in a fixed-width table cell.
+// Tabs and line breaks occupy zero or variable columns in a terminal, which
+// breaks padding and width math, so each one is replaced with a single
+// space; other control characters show as in SanitizeForDisplay. Embedded
+// ANSI escape sequences are removed first, so a colored message shows as
+// plain text rather than as stray codes.
+func NormalizeCell(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == '\t', r == '\n', r == '\r':
+			return ' '
+		case unicode.IsControl(r):
+			return controlPicture(r)
+		}
+		return r
+	}, StripANSI(s))
+}
+
+// SanitizeForDisplay makes s safe to write to a terminal. Control
+// characters other than tabs and newlines, such as NUL bytes in binary
+// garbage or a stray ESC that could rewrite the screen, are replaced with
+// visible one-column placeholders like ␀, and invalid UTF-8 with U+FFFD.
+func SanitizeForDisplay(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r != '\t' && r != '\n' && unicode.IsControl(r) {
+			return controlPicture(r)
+		}
+		return r
+	}, s)
+}
+
+// controlPicture returns the placeholder shown for control character r:
+// its symbol from the Control Pictures block, such as ␀ for NUL, or U+FFFD
+// for the C1 controls, which have none.
+func controlPicture(r rune) rune {
+	switch {
+	case r < 0x20:
+		return 0x2400 + r
+	case r == 0x7f:
+		return '\u2421'
+	}
+	return utf8.RuneError
+}
+
 // ShortenLevel returns a shortened version of the level string.
 func ShortenLevel(level string) string {
 	switch strings.ToUpper(level) {
@@ -178,10 +542,18 @@ func ShortenLevel(level string) string {
 		return "INF"
 	case "WARN", "WARNING":
 		return "WRN"
-	case "ERROR":
+	case "ERROR", "ERR":
 		return "ERR"
 	case "FATAL":
 		return "FTL"
+	case "NOTICE":
+		return "NTC"
+	case "CRIT":
+		return "CRT"
+	case "ALERT":
+		return "ALR"
+	case "EMERG":
+		return "EMG"
 	case "PANIC":
 		return "PNC"
 	case "TRACE":
@@ -3,6 +3,10 @@ package parser
 import (
 	"strings"
 	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/x/ansi"
 )
 
 // TestParse verifies basic log entry parsing.
@@ -67,7 +71,7 @@ func TestParse(t *testing.T) {
 			name:     "ts field",
 			input:    `{"ts":1705315800,"level":"info","msg":"unix timestamp"}`,
 			row:      6,
-			wantTime: "1705315800",
+			wantTime: "2024-01-15T10:50:00Z",
 			wantLvl:  "info",
 			wantMsg:  "unix timestamp",
 			wantErr:  false,
@@ -79,13 +83,41 @@ func TestParse(t *testing.T) {
 			wantErr: true,
 		},
 		{
-			name:     "invalid JSON",
-			input:    "not json at all",
-			row:      8,
-			wantTime: "", // gjson may not error on invalid input, just return empty
-			wantLvl:  "",
-			wantMsg:  "",
-			wantErr:  false, // gjson is lenient with invalid input
+			name:    "invalid JSON",
+			input:   "not json at all",
+			row:     8,
+			wantErr: true, // gjson alone would read the leading "n" as null
+		},
+		{
+			name:     "syslog severity number",
+			input:    `{"time":"2024-01-15T10:30:00Z","level":3,"msg":"disk failing"}`,
+			row:      10,
+			wantTime: "2024-01-15T10:30:00Z",
+			wantLvl:  "err",
+			wantMsg:  "disk failing",
+		},
+		{
+			name:     "other level number",
+			input:    `{"time":"2024-01-15T10:30:00Z","level":30,"msg":"bunyan info"}`,
+			row:      11,
+			wantTime: "2024-01-15T10:30:00Z",
+			wantLvl:  "30",
+			wantMsg:  "bunyan info",
+		},
+		{
+			name:     "object message",
+			input:    `{"time":"2024-01-15T10:30:00Z","level":"info","msg":{ "event": "login",  "ids": [1, 2] }}`,
+			row:      12,
+			wantTime: "2024-01-15T10:30:00Z",
+			wantLvl:  "info",
+			wantMsg:  `{"event":"login","ids":[1,2]}`,
+		},
+		{
+			name:    "array message",
+			input:   `{"level":"info","msg":[ "a", "b" ]}`,
+			row:     13,
+			wantLvl: "info",
+			wantMsg: `["a","b"]`,
 		},
 		{
 			name:     "missing fields",
@@ -276,6 +308,9 @@ func TestLevelColor(t *testing.T) {
 		{"error", "#FF0000"},
 		{"FATAL", "#FF00FF"},
 		{"PANIC", "#FF00FF"},
+		{"notice", "#00FF00"},
+		{"err", "#FF0000"},
+		{"alert", "#FF00FF"},
 		{"unknown", ""},
 		{"", ""},
 	}
@@ -290,6 +325,259 @@ func TestLevelColor(t *testing.T) {
 	}
 }
 
+// TestParseTime verifies timestamp layouts are recognized.
+func TestParseTime(t *testing.T) {
+	want := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		input  string
+		wantOK bool
+	}{
+		{"2024-01-15T10:30:00Z", true},
+		{"2024-01-15T10:30:00.000Z", true},
+		{"2024-01-15T12:30:00+02:00", true},
+		{"2024-01-15T10:30:00", true},
+		{"2024-01-15 10:30:00", true},
+		{"not a time", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, ok := ParseTime(tt.input)
+			if ok != tt.wantOK {
+				t.Fatalf("ParseTime(%q): expected ok=%v, got %v", tt.input, tt.wantOK, ok)
+			}
+			if ok && !got.Equal(want) {
+				t.Errorf("ParseTime(%q): expected %v, got %v", tt.input, want, got)
+			}
+		})
+	}
+}
+
+// TestParseTimeBound verifies range bounds accept timestamps and durations
+// counted back from now, and that Parse fills in Timestamp.
+func TestParseTimeBound(t *testing.T) {
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		input   string
+		want    time.Time
+		wantErr bool
+	}{
+		{"2024-01-15T10:30:00Z", time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC), false},
+		{"-1h", now.Add(-time.Hour), false},
+		{"90m", now.Add(-90 * time.Minute), false},
+		{"1705314600", time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC), false},
+		{"yesterday", time.Time{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseTimeBound(tt.input, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseTimeBound(%q): err = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("ParseTimeBound(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+
+	p := New()
+	entry, err := p.Parse([]byte(`{"time":"2024-01-15T12:30:00+02:00","msg":"x"}`), 1)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if want := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC); !entry.Timestamp.Equal(want) {
+		t.Errorf("Timestamp = %v, want %v", entry.Timestamp, want)
+	}
+	entry, err = p.Parse([]byte(`{"time":"soon","msg":"x"}`), 1)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if !entry.Timestamp.IsZero() {
+		t.Errorf("unrecognized time: Timestamp = %v, want zero", entry.Timestamp)
+	}
+}
+
+// TestInTimeRange verifies bounds are inclusive, a zero bound is open, and
+// untimed entries are kept unless excluded.
+func TestInTimeRange(t *testing.T) {
+	since := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	until := since.Add(time.Hour)
+
+	tests := []struct {
+		name           string
+		t              time.Time
+		since, until   time.Time
+		excludeUntimed bool
+		want           bool
+	}{
+		{"at since", since, since, until, false, true},
+		{"at until", until, since, until, false, true},
+		{"before", since.Add(-time.Second), since, until, false, false},
+		{"after", until.Add(time.Second), since, until, false, false},
+		{"open end", until.Add(time.Hour), since, time.Time{}, false, true},
+		{"untimed", time.Time{}, since, until, false, true},
+		{"untimed excluded", time.Time{}, since, until, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := InTimeRange(tt.t, tt.since, tt.until, tt.excludeUntimed); got != tt.want {
+				t.Errorf("InTimeRange = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestParseEpoch verifies numeric timestamps are read in the unit their
+// digit count implies, keeping the raw value, while ISO values pass through.
+func TestParseEpoch(t *testing.T) {
+	p := New()
+
+	tests := []struct {
+		name     string
+		input    string
+		wantTime string
+		wantRaw  string
+	}{
+		{"seconds", `{"ts":1705315800}`, "2024-01-15T10:50:00Z", "1705315800"},
+		{"fractional seconds", `{"ts":1705315800.25}`, "2024-01-15T10:50:00.25Z", "1705315800.25"},
+		{"milliseconds", `{"ts":1705315800123}`, "2024-01-15T10:50:00.123Z", "1705315800123"},
+		{"microseconds", `{"ts":1705315800123456}`, "2024-01-15T10:50:00.123456Z", "1705315800123456"},
+		{"nanoseconds", `{"ts":1705315800123456789}`, "2024-01-15T10:50:00.123456789Z", "1705315800123456789"},
+		{"numeric string", `{"time":"1705315800"}`, "2024-01-15T10:50:00Z", "1705315800"},
+		{"iso unchanged", `{"time":"2024-01-15T12:30:00+02:00"}`, "2024-01-15T12:30:00+02:00", "2024-01-15T12:30:00+02:00"},
+		{"negative unchanged", `{"ts":-5}`, "-5", "-5"},
+		{"not a number", `{"ts":"12ab"}`, "12ab", "12ab"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry, err := p.Parse([]byte(tt.input), 1)
+			if err != nil {
+				t.Fatalf("Parse failed: %v", err)
+			}
+			if entry.Time != tt.wantTime {
+				t.Errorf("Time: expected %q, got %q", tt.wantTime, entry.Time)
+			}
+			if entry.RawTime != tt.wantRaw {
+				t.Errorf("RawTime: expected %q, got %q", tt.wantRaw, entry.RawTime)
+			}
+		})
+	}
+
+	if got, ok := ParseTime("1705315800"); !ok || !got.Equal(time.Date(2024, 1, 15, 10, 50, 0, 0, time.UTC)) {
+		t.Errorf("ParseTime: expected epoch seconds to parse, got %v, %v", got, ok)
+	}
+}
+
+// TestNormalizeCell verifies whitespace controls are flattened for table
+// cells and other control characters shown as placeholders.
+func TestNormalizeCell(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"plain text", "plain text"},
+		{"tab\there", "tab here"},
+		{"two\t\ttabs", "two  tabs"},
+		{"line\nbreak", "line break"},
+		{"crlf\r\n", "crlf  "},
+		{"bell\x07", "bell␇"},
+		{"nul\x00byte", "nul␀byte"},
+		{"bad\xffutf8", "bad�utf8"},
+		{"日本語\tok", "日本語 ok"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got := NormalizeCell(tt.input)
+			if got != tt.want {
+				t.Errorf("NormalizeCell(%q): expected %q, got %q", tt.input, tt.want, got)
+			}
+		})
+	}
+}
+
+// TestSanitizeForDisplay verifies control characters other than tabs and
+// newlines become one-column placeholders.
+func TestSanitizeForDisplay(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"plain text", "plain text"},
+		{"keep\ttab\nand newline", "keep\ttab\nand newline"},
+		{"nul\x00\x00s", "nul␀␀s"},
+		{"esc\x1b[2Jclear", "esc␛[2Jclear"},
+		{"cr\r", "cr␍"},
+		{"del\x7f", "del␡"},
+		{"c1\u0085", "c1�"},
+		{"bad\xfe", "bad�"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got := SanitizeForDisplay(tt.input)
+			if got != tt.want {
+				t.Errorf("SanitizeForDisplay(%q): expected %q, got %q", tt.input, tt.want, got)
+			}
+			if width := ansi.StringWidth(got); !strings.ContainsAny(got, "\t\n") && width != utf8.RuneCountInString(got) {
+				t.Errorf("SanitizeForDisplay(%q): width %d, want one column per character", tt.input, width)
+			}
+		})
+	}
+}
+
+// TestNormalizeLevel verifies aliases, case, and syslog severities map to
+// the canonical levels.
+func TestNormalizeLevel(t *testing.T) {
+	tests := []struct {
+		level string
+		want  string
+	}{
+		{"info", "INFO"},
+		{" Info ", "INFO"},
+		{"notice", "INFO"},
+		{"WARNING", "WARN"},
+		{"wrn", "WARN"},
+		{"err", "ERROR"},
+		{"Error", "ERROR"},
+		{"fatal", "FATAL"},
+		{"crit", "FATAL"},
+		{"panic", "FATAL"},
+		{"emerg", "FATAL"},
+		{"dbg", "DEBUG"},
+		{"trace", "TRACE"},
+		{"3", "ERROR"},
+		{"7", "DEBUG"},
+		{"30", "30"},
+		{"verbose", "VERBOSE"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.level, func(t *testing.T) {
+			if got := NormalizeLevel(tt.level); got != tt.want {
+				t.Errorf("NormalizeLevel(%q): expected %q, got %q", tt.level, tt.want, got)
+			}
+		})
+	}
+
+	// Parse keeps the level as written next to its canonical form
+	entry, err := New().Parse([]byte(`{"level":"Warning","msg":"hi"}`), 1)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if entry.Level != "Warning" || entry.CanonicalLevel != "WARN" {
+		t.Errorf("expected Level %q and CanonicalLevel %q, got %q and %q", "Warning", "WARN", entry.Level, entry.CanonicalLevel)
+	}
+}
+
 // TestShortenLevel verifies level abbreviation.
 func TestShortenLevel(t *testing.T) {
 	tests := []struct {
@@ -304,6 +592,8 @@ func TestShortenLevel(t *testing.T) {
 		{"FATAL", "FTL"},
 		{"PANIC", "PNC"},
 		{"TRACE", "TRC"},
+		{"notice", "NTC"},
+		{"emerg", "EMG"},
 		{"CUSTOM", "CUS"},
 		{"AB", "AB"},
 		{"", ""},
@@ -319,11 +609,232 @@ func TestShortenLevel(t *testing.T) {
 	}
 }
 
+// TestSetMaxMsgLen verifies the message truncation length is configurable.
+func TestSetMaxMsgLen(t *testing.T) {
+	long := strings.Repeat("x", 150)
+	input := []byte(`{"msg":"` + long + `"}`)
+
+	tests := []struct {
+		name    string
+		max     int
+		wantLen int
+	}{
+		{"default", DefaultMaxMsgLen, 100},
+		{"shorter", 20, 20},
+		{"longer than message", 200, 150},
+		{"unlimited", 0, 150},
+		{"negative means unlimited", -5, 150},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := New()
+			p.SetMaxMsgLen(tt.max)
+			entry, err := p.Parse(input, 1)
+			if err != nil {
+				t.Fatalf("Parse failed: %v", err)
+			}
+			if len(entry.Msg) != tt.wantLen {
+				t.Errorf("expected message length %d, got %d", tt.wantLen, len(entry.Msg))
+			}
+			if tt.wantLen < 150 && !strings.HasSuffix(entry.Msg, "...") {
+				t.Errorf("expected truncated message to end with ..., got %q", entry.Msg)
+			}
+		})
+	}
+}
+
+// TestSetIndent verifies FormatPretty indents with the configured string
+// and ParseIndent accepts space counts and "tab".
+func TestSetIndent(t *testing.T) {
+	input := []byte(`{"a":{"b":1}}`)
+	p := New()
+	for _, tt := range []struct {
+		spec, want string
+	}{
+		{"4", "{\n    \"a\": {\n        \"b\": 1\n    }\n}"},
+		{"tab", "{\n\t\"a\": {\n\t\t\"b\": 1\n\t}\n}"},
+	} {
+		indent, err := ParseIndent(tt.spec)
+		if err != nil {
+			t.Fatalf("ParseIndent(%q) failed: %v", tt.spec, err)
+		}
+		p.SetIndent(indent)
+		got, err := p.FormatPretty(input)
+		if err != nil {
+			t.Fatalf("FormatPretty failed: %v", err)
+		}
+		if got != tt.want {
+			t.Errorf("indent %q: expected %q, got %q", tt.spec, tt.want, got)
+		}
+	}
+
+	p.SetIndent("")
+	if p.Indent() != DefaultIndent {
+		t.Errorf("expected an empty indent to restore the default, got %q", p.Indent())
+	}
+
+	for _, spec := range []string{"", "0", "9", "tabs", "-2"} {
+		if _, err := ParseIndent(spec); err == nil {
+			t.Errorf("ParseIndent(%q): expected an error", spec)
+		}
+	}
+}
+
+// TestMaxMsgLenWide verifies truncation counts display cells, so wide
+// characters are never split and the result fits the length.
+func TestMaxMsgLenWide(t *testing.T) {
+	tests := []struct {
+		name string
+		msg  string
+		max  int
+		want string
+	}{
+		{"cjk", "日本語のログメッセージ", 10, "日本語..."},
+		{"cjk odd width", "日本語のログメッセージ", 9, "日本語..."},
+		{"emoji", "🚀🚀🚀🚀🚀🚀", 8, "🚀🚀..."},
+		{"fits", "日本語", 6, "日本語"},
+		{"tiny", "日本語", 3, "日"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := New()
+			p.SetMaxMsgLen(tt.max)
+			entry, err := p.Parse([]byte(`{"msg":"`+tt.msg+`"}`), 1)
+			if err != nil {
+				t.Fatalf("Parse failed: %v", err)
+			}
+			if entry.Msg != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, entry.Msg)
+			}
+		})
+	}
+}
+
+// TestParseTimeField verifies a configured time field overrides detection.
+func TestParseTimeField(t *testing.T) {
+	tests := []struct {
+		name  string
+		field string
+		input string
+		want  string
+	}{
+		{"custom field", "event_time", `{"time":"ignored","event_time":"2024-01-15T10:30:00Z","msg":"x"}`, "2024-01-15T10:30:00Z"},
+		{"nested path", "meta.at", `{"meta":{"at":"2024-01-15T10:30:00Z"},"msg":"x"}`, "2024-01-15T10:30:00Z"},
+		{"missing field has no fallback", "event_time", `{"time":"2024-01-15T10:30:00Z","msg":"x"}`, ""},
+		{"auto-detect", "", `{"ts":"2024-01-15T10:30:00Z","msg":"x"}`, "2024-01-15T10:30:00Z"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := New()
+			p.SetTimeField(tt.field)
+			entry, err := p.Parse([]byte(tt.input), 1)
+			if err != nil {
+				t.Fatalf("Parse failed: %v", err)
+			}
+			if entry.Time != tt.want {
+				t.Errorf("expected time %q, got %q", tt.want, entry.Time)
+			}
+		})
+	}
+}
+
+// TestParseInto verifies a reused entry is fully overwritten and left
+// alone when parsing fails.
+func TestParseInto(t *testing.T) {
+	p := New()
+	var entry LogEntry
+
+	first := []byte(`{"time":"2024-01-15T10:30:00Z","level":"error","msg":"first"}`)
+	if err := p.ParseInto(first, 1, &entry); err != nil {
+		t.Fatalf("ParseInto failed: %v", err)
+	}
+	if entry.Row != 1 || entry.Level != "error" || entry.Msg != "first" {
+		t.Errorf("unexpected entry: %+v", entry)
+	}
+
+	// Fields missing from the second line must not leak from the first
+	second := []byte(`{"msg":"second"}`)
+	if err := p.ParseInto(second, 2, &entry); err != nil {
+		t.Fatalf("ParseInto failed: %v", err)
+	}
+	if entry.Row != 2 || entry.Time != "" || entry.Level != "" || entry.Msg != "second" {
+		t.Errorf("expected stale fields to be cleared, got %+v", entry)
+	}
+
+	if err := p.ParseInto(nil, 3, &entry); err == nil {
+		t.Error("expected an error for an empty line")
+	}
+	if entry.Row != 2 || entry.Msg != "second" {
+		t.Errorf("expected entry to be unchanged after an error, got %+v", entry)
+	}
+}
+
+// TestLevelTint verifies only warning and worse levels get a row tint.
+func TestLevelTint(t *testing.T) {
+	tests := []struct {
+		level string
+		want  string
+	}{
+		{"debug", ""},
+		{"INFO", ""},
+		{"warn", "#332B00"},
+		{"WARNING", "#332B00"},
+		{"error", "#3D0F0F"},
+		{"FATAL", "#3A0F3A"},
+		{"panic", "#3A0F3A"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.level, func(t *testing.T) {
+			if got := LevelTint(tt.level); got != tt.want {
+				t.Errorf("LevelTint(%q): expected %q, got %q", tt.level, tt.want, got)
+			}
+		})
+	}
+}
+
+// TestLevelRank verifies levels are ordered by severity and unknown
+// levels rank lowest.
+func TestLevelRank(t *testing.T) {
+	tests := []struct {
+		level string
+		want  int
+	}{
+		{"", 0},
+		{"verbose", 0},
+		{"trace", 1},
+		{"DEBUG", 2},
+		{"info", 3},
+		{"warn", 4},
+		{"WARNING", 4},
+		{"error", 5},
+		{"FATAL", 6},
+		{"panic", 6},
+		{"notice", 3},
+		{"err", 5},
+		{"crit", 6},
+		{"emerg", 6},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.level, func(t *testing.T) {
+			if got := LevelRank(tt.level); got != tt.want {
+				t.Errorf("LevelRank(%q): expected %d, got %d", tt.level, tt.want, got)
+			}
+		})
+	}
+}
+
 // BenchmarkParse benchmarks log entry parsing.
 func BenchmarkParse(b *testing.B) {
 	p := New()
 	input := []byte(`{"time":"2024-01-15T10:30:00Z","level":"info","msg":"benchmark test","source":{"file":"main.go","line":42},"request_id":"abc-123"}`)
 
+	b.ReportAllocs()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
 		_, err := p.Parse(input, i+1)
@@ -333,6 +844,22 @@ func BenchmarkParse(b *testing.B) {
 	}
 }
 
+// BenchmarkParseInto benchmarks parsing into a reused entry, for comparison
+// with BenchmarkParse.
+func BenchmarkParseInto(b *testing.B) {
+	p := New()
+	input := []byte(`{"time":"2024-01-15T10:30:00Z","level":"info","msg":"benchmark test","source":{"file":"main.go","line":42},"request_id":"abc-123"}`)
+	var entry LogEntry
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := p.ParseInto(input, i+1, &entry); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
 // BenchmarkFormatPretty benchmarks JSON formatting.
 func BenchmarkFormatPretty(b *testing.B) {
 	p := New()
@@ -0,0 +1,86 @@
+package parser
+
+import (
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/tidwall/gjson"
+)
+
+// Schema records which keys of a log line the parser reads the time,
+// level, and message from. A field that wasn't found is empty.
+type Schema struct {
+	Time  string
+	Level string
+	Msg   string
+}
+
+// DetectSchema returns the keys ParseInto would read from raw, so users
+// can check the viewer matched their logger's field names. A configured
+// time field (see SetTimeField) is reported when it's present.
+func (p *Parser) DetectSchema(raw []byte) Schema {
+	result := gjson.ParseBytes(raw)
+	var s Schema
+	if p.timeField != "" {
+		if result.Get(p.timeField).String() != "" {
+			s.Time = p.timeField
+		}
+	} else {
+		s.Time, _ = firstField(result, timeFields)
+	}
+	s.Level, _ = firstField(result, levelFields)
+	s.Msg, _ = firstField(result, msgFields)
+	return s
+}
+
+// String renders the schema as time/level/message keys, with "-" for a
+// field that wasn't found, e.g. "ts/severity/message".
+func (s Schema) String() string {
+	keys := []string{s.Time, s.Level, s.Msg}
+	for i, k := range keys {
+		if k == "" {
+			keys[i] = "-"
+		}
+	}
+	return strings.Join(keys, "/")
+}
+
+// DetectColumnsSample is how many lines from the start of a file are
+// worth passing to DetectColumns.
+const DetectColumnsSample = 100
+
+// DetectColumns suggests extra table columns for logs whose schema isn't
+// known in advance: the top-level keys of lines, most common first, with
+// ties in order of first appearance. The time, level, and message keys the
+// table already shows are left out, as are keys holding objects or arrays,
+// which don't fit in a cell. Lines that aren't JSON objects are skipped.
+func DetectColumns(lines [][]byte) []string {
+	counts := make(map[string]int)
+	var keys []string
+	for _, line := range lines {
+		result := gjson.ParseBytes(line)
+		if !result.IsObject() {
+			continue
+		}
+		result.ForEach(func(key, value gjson.Result) bool {
+			k := key.String()
+			if value.IsObject() || value.IsArray() || isBuiltinField(k) {
+				return true
+			}
+			if counts[k] == 0 {
+				keys = append(keys, k)
+			}
+			counts[k]++
+			return true
+		})
+	}
+	sort.SliceStable(keys, func(i, j int) bool { return counts[keys[i]] > counts[keys[j]] })
+	return keys
+}
+
+// isBuiltinField reports whether key is one the parser reads the time,
+// level, or message from.
+func isBuiltinField(key string) bool {
+	return slices.Contains(timeFields, key) || slices.Contains(levelFields, key) || slices.Contains(msgFields, key)
+}
@@ -0,0 +1,56 @@
+package parser
+
+import (
+	"fmt"
+	"testing"
+)
+
+// TestDetectSchema verifies the reported keys match the fields Parse
+// reads, including a configured time field and missing fields.
+func TestDetectSchema(t *testing.T) {
+	tests := []struct {
+		name      string
+		raw       string
+		timeField string
+		want      string
+	}{
+		{"slog", `{"time":"2024-01-01T00:00:00Z","level":"INFO","msg":"hi"}`, "", "time/level/msg"},
+		{"variants", `{"ts":1700000000,"severity":"warn","message":"hi"}`, "", "ts/severity/message"},
+		{"capitalized", `{"Timestamp":"x","Level":"info","Message":"hi"}`, "", "Timestamp/Level/Message"},
+		{"empty value skipped", `{"level":"","severity":"error","msg":"hi"}`, "", "-/severity/msg"},
+		{"nothing matched", `{"foo":1}`, "", "-/-/-"},
+		{"configured time field", `{"meta":{"at":"x"},"time":"y","level":"info","msg":"hi"}`, "meta.at", "meta.at/level/msg"},
+		{"configured field missing", `{"time":"y","level":"info","msg":"hi"}`, "meta.at", "-/level/msg"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := New()
+			p.SetTimeField(tt.timeField)
+			if got := p.DetectSchema([]byte(tt.raw)).String(); got != tt.want {
+				t.Errorf("expected schema %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+// TestDetectColumns verifies keys are ranked by how many lines have them,
+// ties keep their first appearance, and the built-in fields, nested
+// values, and non-object lines are left out.
+func TestDetectColumns(t *testing.T) {
+	lines := [][]byte{
+		[]byte(`{"time":"x","level":"info","msg":"a","user":"bob","req":{"id":1}}`),
+		[]byte(`{"ts":1,"severity":"warn","message":"b","status":200,"user":"amy","tags":["x"]}`),
+		[]byte(`not json`),
+		[]byte(`[1,2]`),
+		[]byte(`{"level":"info","msg":"c","status":404,"user":"bob","duration_ms":12}`),
+		[]byte(`{"msg":"d","a.b":true,"duration_ms":3}`),
+	}
+	want := "[user status duration_ms a.b]"
+	if got := fmt.Sprint(DetectColumns(lines)); got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+	if got := DetectColumns(nil); len(got) != 0 {
+		t.Errorf("expected no columns for no lines, got %v", got)
+	}
+}
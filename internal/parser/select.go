@@ -0,0 +1,55 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/tidwall/gjson"
+)
+
+// SelectFields returns a JSON object holding only the given fields of a
+// parsed entry, in the order given and keyed by path. "time", "level",
+// and "msg" are the entry's own fields, wherever the line keeps them, as
+// in the table's built-in columns; any other path is read from the raw
+// line with gjson. Fields the line doesn't have are left out.
+func SelectFields(entry *LogEntry, paths []string) []byte {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for _, path := range paths {
+		var value []byte
+		switch path {
+		case "time":
+			value = quoteNonEmpty(entry.RawTime)
+		case "level":
+			value = quoteNonEmpty(entry.Level)
+		case "msg":
+			value = quoteNonEmpty(entry.Msg)
+		default:
+			if result := gjson.GetBytes(entry.Raw, path); result.Exists() {
+				value = []byte(result.Raw)
+			}
+		}
+		if value == nil {
+			continue
+		}
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		key, _ := json.Marshal(path)
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes()
+}
+
+// quoteNonEmpty returns s as a JSON string, or nil when it's empty.
+func quoteNonEmpty(s string) []byte {
+	if s == "" {
+		return nil
+	}
+	// Marshaling a string can't fail
+	b, _ := json.Marshal(s)
+	return b
+}
@@ -0,0 +1,48 @@
+package parser
+
+import "testing"
+
+// TestSelectFields verifies the built-in names read the parsed entry,
+// other paths read the raw line, and missing fields are left out.
+func TestSelectFields(t *testing.T) {
+	p := New()
+	p.SetMaxMsgLen(0)
+
+	tests := []struct {
+		name  string
+		line  string
+		paths []string
+		want  string
+	}{
+		{
+			name:  "built-in and nested",
+			line:  `{"ts":"2024-01-15T10:30:00Z","severity":"warn","message":"disk low","user":{"id":7},"tags":["a"]}`,
+			paths: []string{"level", "time", "msg", "user.id", "tags"},
+			want:  `{"level":"warn","time":"2024-01-15T10:30:00Z","msg":"disk low","user.id":7,"tags":["a"]}`,
+		},
+		{
+			name:  "missing fields",
+			line:  `{"msg":"hi"}`,
+			paths: []string{"time", "msg", "request_id"},
+			want:  `{"msg":"hi"}`,
+		},
+		{
+			name:  "logfmt",
+			line:  `time=2024-01-15T10:30:00Z level=info msg="say \"hi\""`,
+			paths: []string{"time", "msg"},
+			want:  `{"time":"2024-01-15T10:30:00Z","msg":"say \"hi\""}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry, err := p.Parse([]byte(tt.line), 1)
+			if err != nil {
+				t.Fatalf("Parse failed: %v", err)
+			}
+			if got := string(SelectFields(entry, tt.paths)); got != tt.want {
+				t.Errorf("SelectFields = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package parser
+
+import (
+	"strings"
+
+	"github.com/tidwall/gjson"
+)
+
+// errorFields are the record fields checked, in order, for a structured
+// error object.
+var errorFields = []string{"error", "exception", "err"}
+
+// stackFields are the keys a structured error may keep its stack trace under.
+var stackFields = []string{"stack", "stack_trace", "stacktrace", "stackTrace"}
+
+// FormatError looks for a structured error object in a log record (an
+// "error", "exception", or "err" field holding an object) and formats it
+// for reading: the first line is the error message, prefixed by its type
+// when present, followed by one indented line per stack frame.
+//
+// Stacks may be an array of strings, an array of frame objects with
+// function/file/line keys, or a single newline-separated string.
+// Returns false if the record has no error object with a message or stack.
+func FormatError(result gjson.Result) (string, bool) {
+	var errObj gjson.Result
+	for _, field := range errorFields {
+		if v := result.Get(field); v.IsObject() {
+			errObj = v
+			break
+		}
+	}
+	if !errObj.Exists() {
+		return "", false
+	}
+
+	msg := firstString(errObj, "message", "msg")
+	typ := firstString(errObj, "type", "kind", "class")
+	var frames []string
+	for _, field := range stackFields {
+		if v := errObj.Get(field); v.Exists() {
+			frames = stackFrames(v)
+			break
+		}
+	}
+	if msg == "" && len(frames) == 0 {
+		return "", false
+	}
+
+	var b strings.Builder
+	if typ != "" {
+		b.WriteString(typ)
+		if msg != "" {
+			b.WriteString(": ")
+		}
+	}
+	b.WriteString(msg)
+	for _, frame := range frames {
+		b.WriteString("\n    ")
+		b.WriteString(frame)
+	}
+	return b.String(), true
+}
+
+// firstString returns the first non-empty string value among keys.
+func firstString(obj gjson.Result, keys ...string) string {
+	for _, key := range keys {
+		if s := obj.Get(key).String(); s != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+// stackFrames splits a stack trace value into one string per frame.
+func stackFrames(stack gjson.Result) []string {
+	var frames []string
+	if stack.IsArray() {
+		for _, frame := range stack.Array() {
+			if frame.IsObject() {
+				frames = append(frames, formatFrame(frame))
+			} else if s := strings.TrimSpace(frame.String()); s != "" {
+				frames = append(frames, s)
+			}
+		}
+		return frames
+	}
+
+	for _, line := range strings.Split(stack.String(), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			frames = append(frames, line)
+		}
+	}
+	return frames
+}
+
+// formatFrame renders a frame object as "function (file:line)".
+func formatFrame(frame gjson.Result) string {
+	fn := firstString(frame, "function", "func", "method")
+	file := firstString(frame, "file", "filename")
+	line := firstString(frame, "line", "lineno")
+
+	loc := file
+	if file != "" && line != "" {
+		loc += ":" + line
+	}
+	switch {
+	case fn != "" && loc != "":
+		return fn + " (" + loc + ")"
+	case fn != "":
+		return fn
+	case loc != "":
+		return loc
+	default:
+		return frame.Raw
+	}
+}
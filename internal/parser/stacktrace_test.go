@@ -0,0 +1,67 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/tidwall/gjson"
+)
+
+// TestFormatError verifies structured errors are detected and their stack
+// traces laid out one frame per line.
+func TestFormatError(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		want   string
+		wantOK bool
+	}{
+		{
+			name:   "array of frames",
+			input:  `{"msg":"failed","error":{"type":"IOException","message":"disk full","stack":["at a.Write(A.java:10)","at b.Save(B.java:20)"]}}`,
+			want:   "IOException: disk full\n    at a.Write(A.java:10)\n    at b.Save(B.java:20)",
+			wantOK: true,
+		},
+		{
+			name:   "newline string stack",
+			input:  `{"exception":{"message":"boom","stack_trace":"Traceback:\n  File \"x.py\", line 3\n\n  ValueError"}}`,
+			want:   "boom\n    Traceback:\n    File \"x.py\", line 3\n    ValueError",
+			wantOK: true,
+		},
+		{
+			name:   "frame objects",
+			input:  `{"err":{"msg":"nil map","stacktrace":[{"function":"main.run","file":"main.go","line":42},{"func":"main.main"}]}}`,
+			want:   "nil map\n    main.run (main.go:42)\n    main.main",
+			wantOK: true,
+		},
+		{
+			name:   "message only",
+			input:  `{"error":{"message":"timeout"}}`,
+			want:   "timeout",
+			wantOK: true,
+		},
+		{
+			name:  "string error",
+			input: `{"error":"plain string"}`,
+		},
+		{
+			name:  "object without message or stack",
+			input: `{"error":{"code":42}}`,
+		},
+		{
+			name:  "no error field",
+			input: `{"msg":"ok"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := FormatError(gjson.Parse(tt.input))
+			if ok != tt.wantOK {
+				t.Fatalf("expected ok=%v, got %v", tt.wantOK, ok)
+			}
+			if got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package parser
+
+import (
+	"strconv"
+
+	"github.com/tidwall/gjson"
+)
+
+// TreeNode is one line of a tree view of a JSON object: a leaf value, or
+// an object or array whose children follow it one level deeper unless it
+// is collapsed.
+type TreeNode struct {
+	// Path is the dotted path to the node, as in Flatten.
+	Path string
+	// Key is the node's own key, or its index within an array.
+	Key string
+	// Depth is how many objects or arrays enclose the node, 0 for the
+	// fields of the top-level object.
+	Depth int
+	// Value is a leaf's value as Flatten gives it, or the raw JSON of an
+	// object or array.
+	Value string
+	// Branch marks a non-empty object or array.
+	Branch bool
+	// Array marks a branch that is an array rather than an object.
+	Array bool
+	// Collapsed marks a branch whose children are left out.
+	Collapsed bool
+	// Size is the number of children of a branch.
+	Size int
+}
+
+// FormatTree lays out a JSON object as a tree, one node per field in the
+// order written, with each object or array followed by its children.
+// collapsed is asked about every branch by path, and the children of those
+// it reports are left out; a nil collapsed expands everything. Returns nil
+// if raw is not a JSON object.
+func FormatTree(raw []byte, collapsed func(path string) bool) []TreeNode {
+	result := gjson.ParseBytes(raw)
+	if !result.IsObject() {
+		return nil
+	}
+	var nodes []TreeNode
+	treeInto(&nodes, "", 0, result, collapsed)
+	return nodes
+}
+
+// treeInto appends the children of value, whose path is prefix, to nodes
+// at the given depth.
+func treeInto(nodes *[]TreeNode, prefix string, depth int, value gjson.Result, collapsed func(string) bool) {
+	i := 0
+	value.ForEach(func(key, child gjson.Result) bool {
+		name := key.String()
+		if value.IsArray() {
+			name = strconv.Itoa(i)
+			i++
+		}
+		path := name
+		if prefix != "" {
+			path = prefix + "." + name
+		}
+
+		node := TreeNode{Path: path, Key: name, Depth: depth}
+		switch {
+		case child.IsObject() || child.IsArray():
+			node.Value = child.Raw
+			node.Array = child.IsArray()
+			child.ForEach(func(_, _ gjson.Result) bool {
+				node.Size++
+				return true
+			})
+			if node.Size == 0 {
+				// Empty containers read as leaves, as in Flatten
+				node.Value = "{}"
+				if node.Array {
+					node.Value = "[]"
+				}
+				break
+			}
+			node.Branch = true
+			node.Collapsed = collapsed != nil && collapsed(path)
+		case child.Type == gjson.String:
+			node.Value = child.Str
+		default:
+			node.Value = child.Raw
+		}
+
+		*nodes = append(*nodes, node)
+		if node.Branch && !node.Collapsed {
+			treeInto(nodes, path, depth+1, child, collapsed)
+		}
+		return true
+	})
+}
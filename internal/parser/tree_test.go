@@ -0,0 +1,42 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestFormatTree verifies nodes follow the fields in order with their
+// children one level deeper, empty containers read as leaves, and a
+// collapsed branch keeps its size but leaves out its children.
+func TestFormatTree(t *testing.T) {
+	raw := []byte(`{"msg":"hi","req":{"id":7,"tags":["a",{"k":true}]},"meta":{}}`)
+
+	got := FormatTree(raw, nil)
+	want := []TreeNode{
+		{Path: "msg", Key: "msg", Value: "hi"},
+		{Path: "req", Key: "req", Value: `{"id":7,"tags":["a",{"k":true}]}`, Branch: true, Size: 2},
+		{Path: "req.id", Key: "id", Depth: 1, Value: "7"},
+		{Path: "req.tags", Key: "tags", Depth: 1, Value: `["a",{"k":true}]`, Branch: true, Array: true, Size: 2},
+		{Path: "req.tags.0", Key: "0", Depth: 2, Value: "a"},
+		{Path: "req.tags.1", Key: "1", Depth: 2, Value: `{"k":true}`, Branch: true, Size: 1},
+		{Path: "req.tags.1.k", Key: "k", Depth: 3, Value: "true"},
+		{Path: "meta", Key: "meta", Value: "{}"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected tree:\n got %+v\nwant %+v", got, want)
+	}
+
+	folded := FormatTree(raw, func(path string) bool { return path == "req.tags" })
+	if len(folded) != len(want)-3 {
+		t.Fatalf("expected the folded array's children left out, got %+v", folded)
+	}
+	if node := folded[3]; !node.Collapsed || node.Size != 2 || folded[4].Path != "meta" {
+		t.Errorf("expected req.tags collapsed with its size, got %+v", folded[3:])
+	}
+
+	for _, raw := range []string{`["a"]`, `"text"`, `not json`} {
+		if nodes := FormatTree([]byte(raw), nil); nodes != nil {
+			t.Errorf("FormatTree(%s): expected nil, got %+v", raw, nodes)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package tui
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+
+	"github.com/lbe/jsonlogviewer/internal/index"
+)
+
+// toggleHelpPage switches the help overlay between the key bindings and
+// the about page.
+func (m *Model) toggleHelpPage() {
+	m.helpAbout = !m.helpAbout
+}
+
+// renderAbout renders the about page of the help overlay: the version and
+// build, then the source and its index, ready to paste into a bug report.
+func (m *Model) renderAbout() string {
+	build := m.buildInfo
+	if build == "" {
+		build = runtime.Version()
+	}
+	indexed := formatDuration(m.idx.IndexDuration())
+	if m.indexing {
+		indexed = "still indexing"
+	}
+
+	rows := [][2]string{
+		{"Version", "jsonlogviewer " + m.version},
+		{"Build", build},
+		{"Source", m.idx.Name()},
+		{"Lines", fmt.Sprintf("%d", m.idx.LineCount())},
+		{"Size", fmt.Sprintf("%s (%d bytes)", index.FormatBytes(m.idx.Size()), m.idx.Size())},
+		{"Indexed in", indexed},
+	}
+	lines := make([]string, 0, len(rows)+1)
+	for _, row := range rows {
+		lines = append(lines, m.styles.Help.Render(fmt.Sprintf(" %-10s ", row[0]))+row[1])
+	}
+	lines = append(lines, m.styles.Help.Render(" Tab: key bindings | F1/?: close"))
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,77 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// maxStatePattern caps how many cells of a regex filter or search query
+// the status line shows, so a long pattern doesn't push the rest out.
+const maxStatePattern = 24
+
+// activeState sums up the filters and search narrowing or marking the
+// rows for the status line, e.g.
+// `filter:WARN,ERROR,FATAL  re:/timeout/ 12 matches  search:"user 42" (3/17)`,
+// or "" when none is active.
+func (m *Model) activeState() string {
+	var parts []string
+	if levels := m.shownLevels(); levels != "" {
+		parts = append(parts, "filter:"+levels)
+	}
+	if state := m.sortState(); state != "" {
+		parts = append(parts, "sort:"+state)
+	}
+	if state := m.timeState(); state != "" {
+		parts = append(parts, "time:"+state)
+	}
+	if state := m.regexState(); state != "" {
+		parts = append(parts, "re:"+strings.TrimPrefix(state, "&"))
+	}
+	if m.searchQuery != "" {
+		i, total := m.searchCount()
+		query := ansi.Truncate(m.searchQuery, maxStatePattern, "…")
+		parts = append(parts, fmt.Sprintf("search:%q (%d/%d)", query, i, total))
+	}
+	return strings.Join(parts, "  ")
+}
+
+// shownLevels lists the levels the level filters let through, e.g.
+// "WARN,ERROR,FATAL", or "none" when every level is hidden. It's "" when
+// no level filter is set.
+func (m *Model) shownLevels() string {
+	if m.levelState() == "" {
+		return ""
+	}
+	var shown []string
+	for t, level := range levelToggles {
+		// Toggles start at DEBUG, rank 2; TRACE goes with DEBUG
+		if !m.hiddenLevels[t] && t+2 >= m.minLevel {
+			shown = append(shown, level)
+		}
+	}
+	if len(shown) == 0 {
+		return "none"
+	}
+	return strings.Join(shown, ",")
+}
+
+// searchCount returns how many of the search matches shown in the table
+// are at or above the cursor, and how many are shown in all, like vim's
+// search count.
+func (m *Model) searchCount() (i, total int) {
+	if m.visible == nil && m.sorted == nil {
+		return sort.SearchInts(m.searchMatches, m.cursorLine()+1), len(m.searchMatches)
+	}
+	for _, n := range m.searchMatches {
+		if m.isShown(n) {
+			total++
+			if m.posOf(n) <= m.viewport.Cursor {
+				i++
+			}
+		}
+	}
+	return i, total
+}
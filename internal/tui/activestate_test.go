@@ -0,0 +1,48 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// TestActiveState verifies the status line sums up the level filter, the
+// regex filter, and the search with the cursor's match number, and stays
+// within the terminal width.
+func TestActiveState(t *testing.T) {
+	idx := createTestIndex(t, levelContent)
+	defer closeIndex(idx)
+
+	m := New(idx)
+	m.Update(tea.WindowSizeMsg{Width: 200, Height: 30})
+	if got := m.activeState(); got != "" {
+		t.Errorf("expected no active state, got %q", got)
+	}
+
+	sendKeys(&m, "++++f5")
+	submitRegexFilter(&m, `msg":"[ce]`)
+	sendKeys(&m, "/level\n")
+
+	want := `filter:WARN,ERROR  re:/msg":"[ce]/ 2 matches  search:"level" (2/2)`
+	if got := m.activeState(); got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+	m.statusMsg = ""
+	if !strings.Contains(m.View(), want) {
+		t.Error("expected the active state in the status line")
+	}
+
+	sendKeys(&m, "/"+strings.Repeat("x", 40)+"\n")
+	if got := m.activeState(); !strings.Contains(got, `search:"`+strings.Repeat("x", maxStatePattern-1)+`…" (0/0)`) {
+		t.Errorf("expected a long query cut short, got %q", got)
+	}
+
+	m.Update(tea.WindowSizeMsg{Width: 60, Height: 30})
+	m.statusMsg = ""
+	lines := strings.Split(m.View(), "\n")
+	if status := lines[len(lines)-1]; ansi.StringWidth(status) > 60 {
+		t.Errorf("expected the status line within 60 cells, got %d: %q", ansi.StringWidth(status), ansi.Strip(status))
+	}
+}
@@ -0,0 +1,128 @@
+package tui
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lbe/jsonlogviewer/internal/parser"
+)
+
+// Background is the terminal background the default colors are chosen
+// for. A theme file's colors apply on top of either.
+type Background int
+
+const (
+	// BackgroundDark suits light text on a dark terminal.
+	BackgroundDark Background = iota
+	// BackgroundLight uses darker text and paler selections for a light
+	// terminal.
+	BackgroundLight
+)
+
+// ParseBackground returns the Background named name: "dark", "light", or
+// "auto", which asks the terminal with lipgloss.HasDarkBackground and
+// falls back to dark when it can't tell.
+func ParseBackground(name string) (Background, error) {
+	switch name {
+	case "dark":
+		return BackgroundDark, nil
+	case "light":
+		return BackgroundLight, nil
+	case "auto":
+		if lipgloss.HasDarkBackground() {
+			return BackgroundDark, nil
+		}
+		return BackgroundLight, nil
+	}
+	return BackgroundDark, fmt.Errorf("unknown theme %q (want light, dark, or auto)", name)
+}
+
+// WithBackground picks the default colors for a dark or light terminal.
+// A theme set with WithTheme is kept on top of them.
+func WithBackground(bg Background) Option {
+	return func(m *Model) {
+		m.background = bg
+		m.SetTheme(m.theme)
+	}
+}
+
+// lightStyles returns the default UI styles for a light background.
+func lightStyles() *Styles {
+	return &Styles{
+		Header: lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("#000000")).
+			Background(lipgloss.Color("#D0D0D0")),
+		Selected: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#000000")).
+			Background(lipgloss.Color("#B8B8B8")),
+		Highlight: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#000000")).
+			Background(lipgloss.Color("#FFE08A")),
+		Normal: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#202020")),
+		SearchMatch: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#000000")).
+			Background(lipgloss.Color("#FFFF00")),
+		Match: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#000000")).
+			Background(lipgloss.Color("#B7D7F2")),
+		Visual: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#000000")).
+			Background(lipgloss.Color("#D7C7F0")),
+		ErrorMsg: lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("#C00000")),
+		Detail: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#202020")),
+		Title: lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("#007A00")),
+		Help: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#6C6C6C")),
+		Separator: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#A0A0A0")),
+		ParseError: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#B03030")).
+			Italic(true),
+		TableContainer:  lipgloss.NewStyle(),
+		DetailContainer: lipgloss.NewStyle(),
+	}
+}
+
+// lightLevelColors are the row colors of each canonical level on a light
+// background, darker counterparts of parser.LevelColor.
+var lightLevelColors = map[string]string{
+	"TRACE": "#6C6C6C", // Gray
+	"DEBUG": "#6C6C6C", // Gray
+	"INFO":  "#007A00", // Green
+	"WARN":  "#8A6D00", // Dark yellow
+	"ERROR": "#C00000", // Red
+	"FATAL": "#A000A0", // Magenta
+}
+
+// lightLevelTints are the row backgrounds zb gives warnings and worse on a
+// light background, pale counterparts of parser.LevelTint.
+var lightLevelTints = map[string]string{
+	"WARN":  "#FFF3C4", // Pale amber
+	"ERROR": "#FFD7D7", // Pale red
+	"FATAL": "#F5D7F5", // Pale magenta
+}
+
+// defaultLevelColor returns the built-in row color of a level for the
+// model's background, or "" for levels without one.
+func (m *Model) defaultLevelColor(level string) string {
+	if m.background == BackgroundLight {
+		return lightLevelColors[parser.NormalizeLevel(level)]
+	}
+	return parser.LevelColor(level)
+}
+
+// levelTintColor returns the zb row background of a level for the model's
+// background, or "" for levels that aren't tinted.
+func (m *Model) levelTintColor(level string) string {
+	if m.background == BackgroundLight {
+		return lightLevelTints[parser.NormalizeLevel(level)]
+	}
+	return parser.LevelTint(level)
+}
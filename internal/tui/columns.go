@@ -0,0 +1,368 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lbe/jsonlogviewer/internal/parser"
+	"github.com/tidwall/gjson"
+)
+
+// rowNumWidth is the width of the fixed line-number column.
+const rowNumWidth = 6
+
+// column describes one reorderable table column.
+type column struct {
+	// key identifies the column independently of its title.
+	key string
+	// title is the header label.
+	title string
+	// width is the display width of the cell.
+	width int
+	// value extracts the cell text from a parsed entry.
+	value func(e *parser.LogEntry) string
+}
+
+// defaultColumns returns the standard Time/Level/Message layout.
+func defaultColumns() []column {
+	return []column{
+		{
+			key:   "time",
+			title: "Time",
+			width: len(displayTimeLayout),
+			value: func(e *parser.LogEntry) string { return e.Time },
+		},
+		{
+			key:   "level",
+			title: "Lvl",
+			width: 6,
+			value: func(e *parser.LogEntry) string { return parser.ShortenLevel(e.Level) },
+		},
+		{
+			key:   "msg",
+			title: "Message",
+			width: 40,
+			value: func(e *parser.LogEntry) string { return e.Msg },
+		},
+	}
+}
+
+// Field column widths: at least minFieldWidth, or wider for a long title,
+// up to maxFieldWidth.
+const (
+	minFieldWidth = 10
+	maxFieldWidth = 30
+)
+
+// parseColumns builds the column layout from a comma-separated list of
+// gjson paths such as "time,level,request_id,user.name". A path may be
+// followed by ":Title" to name its header; otherwise the path is the title.
+// The time, level, and msg paths get the built-in columns, which read the
+// detected fields.
+func parseColumns(spec string) ([]column, error) {
+	builtin := make(map[string]column)
+	for _, col := range defaultColumns() {
+		builtin[col.key] = col
+	}
+
+	var cols []column
+	for _, item := range strings.Split(spec, ",") {
+		path, title, named := strings.Cut(strings.TrimSpace(item), ":")
+		path = strings.TrimSpace(path)
+		if path == "" {
+			return nil, fmt.Errorf("empty column in %q", spec)
+		}
+
+		if col, ok := builtin[path]; ok {
+			if named {
+				col.title = title
+			}
+			cols = append(cols, col)
+			continue
+		}
+
+		if !named {
+			title = path
+		}
+		cols = append(cols, fieldColumn(path, title))
+	}
+	return cols, nil
+}
+
+// fieldColumn returns a column showing the value at a gjson path.
+func fieldColumn(path, title string) column {
+	return column{
+		key:   path,
+		title: title,
+		width: min(max(len(title), minFieldWidth), maxFieldWidth),
+		value: func(e *parser.LogEntry) string { return parser.ExtractField(e.Raw, path) },
+	}
+}
+
+// Limits on the columns added by applyAutoColumns: at most maxAutoColumns
+// of them, and only while the detail pane keeps minAutoDetailWidth.
+const (
+	maxAutoColumns     = 5
+	minAutoDetailWidth = 40
+)
+
+// applyAutoColumns adds the detected fields from WithAutoColumns as
+// columns before the message, most common first, for as many as fit the
+// terminal. They become part of the layout Ctrl+r resets to. It runs once,
+// on the first window size, so later resizes don't undo the user's
+// column changes.
+func (m *Model) applyAutoColumns() {
+	fields := m.autoFields
+	m.autoFields = nil
+
+	at := len(m.columns)
+	for i, col := range m.columns {
+		if col.key == "msg" {
+			at = i
+			break
+		}
+	}
+	width := m.tableWidth()
+	var added []column
+	for _, field := range fields {
+		if len(added) == maxAutoColumns {
+			break
+		}
+		col := fieldColumn(gjson.Escape(field), field)
+		if m.width-(width+1+col.width)-1 < minAutoDetailWidth {
+			break
+		}
+		width += 1 + col.width
+		added = append(added, col)
+	}
+	if len(added) == 0 {
+		return
+	}
+	m.columns = slices.Insert(m.columns, at, added...)
+	m.initialColumns = slices.Clone(m.columns)
+}
+
+// Columns is a table layout for WithColumns, parsed by ParseColumns.
+type Columns struct {
+	cols []column
+}
+
+// ParseColumns parses a -columns spec (see parseColumns). An empty spec
+// is the default layout.
+func ParseColumns(spec string) (Columns, error) {
+	if spec == "" {
+		return Columns{}, nil
+	}
+	cols, err := parseColumns(spec)
+	if err != nil {
+		return Columns{}, err
+	}
+	return Columns{cols: cols}, nil
+}
+
+// Paths returns the gjson path of each column in order, with "time",
+// "level", and "msg" naming the built-in columns. It returns nil for the
+// default layout.
+func (c Columns) Paths() []string {
+	var paths []string
+	for _, col := range c.cols {
+		paths = append(paths, col.key)
+	}
+	return paths
+}
+
+// SetColumns replaces the table columns with the layout described by spec
+// (see parseColumns). It also becomes the layout Ctrl+r resets to.
+// An empty spec keeps the default columns.
+func (m *Model) SetColumns(spec string) error {
+	cols, err := ParseColumns(spec)
+	if err != nil {
+		return err
+	}
+	m.setColumns(cols)
+	return nil
+}
+
+// setColumns installs a parsed layout as the columns and the layout Ctrl+r
+// resets to. The default layout leaves the columns alone.
+func (m *Model) setColumns(cols Columns) {
+	if cols.cols == nil {
+		return
+	}
+	m.initialColumns = cols.cols
+	m.columns = slices.Clone(cols.cols)
+}
+
+// tableWidth returns the total width of the table: the number columns
+// plus every configured column, separated by single spaces.
+func (m *Model) tableWidth() int {
+	width := m.numbersWidth()
+	for _, col := range m.columns {
+		width += 1 + m.colWidth(col)
+	}
+	return width
+}
+
+// colWidth returns the display width of col. While the detail pane is
+// hidden the message column also takes whatever the other columns leave
+// of the terminal.
+func (m *Model) colWidth(col column) int {
+	if col.key != "msg" || !m.hideDetail {
+		return col.width
+	}
+	rest := m.width - m.numbersWidth()
+	for _, other := range m.columns {
+		if other.key != "msg" {
+			rest -= 1 + other.width
+		}
+	}
+	return max(col.width, rest-1)
+}
+
+// msgLenStep is how much { and } change the message length.
+const msgLenStep = 10
+
+// minMsgLen and maxMsgLen bound the message length set with { and }.
+const (
+	minMsgLen = 10
+	maxMsgLen = 500
+)
+
+// adjustMsgLen changes how much of each message the table shows by
+// resizing the message column, which formatRow cuts messages to.
+func (m *Model) adjustMsgLen(delta int) {
+	for i := range m.columns {
+		if m.columns[i].key != "msg" {
+			continue
+		}
+		width := m.columns[i].width + delta
+		if width < minMsgLen {
+			width = minMsgLen
+		}
+		if width > maxMsgLen {
+			width = maxMsgLen
+		}
+		m.columns[i].width = width
+		m.statusMsg = fmt.Sprintf("message length: %d", width)
+		return
+	}
+	m.statusMsg = "no message column"
+}
+
+// SetMsgLen sets the starting width of the message column, as { and } do,
+// so a wide terminal can show more of each message than the default. It
+// also becomes the length Ctrl+r resets to. Call it after SetColumns; zero
+// keeps the default.
+func (m *Model) SetMsgLen(n int) {
+	if n == 0 {
+		return
+	}
+	n = min(max(n, minMsgLen), maxMsgLen)
+	for _, cols := range [][]column{m.columns, m.initialColumns} {
+		for i := range cols {
+			if cols[i].key == "msg" {
+				cols[i].width = n
+			}
+		}
+	}
+}
+
+// formatRow renders the cells of a single entry in the current column order.
+func (m *Model) formatRow(entry *parser.LogEntry) string {
+	return m.formatRowAt(0, entry)
+}
+
+// formatRowAt is formatRow for the entry at table row pos, which the Match
+// column shows; pos 0 leaves it blank.
+func (m *Model) formatRowAt(pos int, entry *parser.LogEntry) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%*d", rowNumWidth, entry.Row)
+	if m.matchNumbers {
+		if pos > 0 {
+			fmt.Fprintf(&b, " %*d", rowNumWidth, pos)
+		} else {
+			fmt.Fprintf(&b, " %*s", rowNumWidth, "")
+		}
+	}
+	for _, col := range m.columns {
+		b.WriteByte(' ')
+		text := col.value(entry)
+		text = parser.NormalizeCell(text)
+		switch col.key {
+		case "time":
+			text = m.displayTime(text)
+		case "msg":
+			text = m.scrollMsg(text)
+		}
+		width := m.colWidth(col)
+		b.WriteString(fitWidth(truncate(text, width), width))
+	}
+	return b.String()
+}
+
+// formatHeader renders the column titles in the current column order.
+// In column mode the selected column is bracketed so it's clear which
+// one the move keys will act on, a scrolled message column's label
+// carries the offset, e.g. "Message +16", and the sorted column an arrow.
+func (m *Model) formatHeader() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%*s", rowNumWidth, "Row")
+	if m.matchNumbers {
+		fmt.Fprintf(&b, " %*s", rowNumWidth, "Match")
+	}
+	for i, col := range m.columns {
+		title := col.title
+		if col.key == "msg" && m.hScroll > 0 {
+			// The label stays put and says how far its text is scrolled
+			title += fmt.Sprintf(" +%d", m.hScroll)
+		}
+		if m.sorted != nil && m.sorted.column == col.key {
+			title += m.sorted.arrow()
+		}
+		if m.columnMode && i == m.selectedColumn {
+			title = "[" + title + "]"
+		}
+		b.WriteByte(' ')
+		width := m.colWidth(col)
+		b.WriteString(fitWidth(truncate(title, width), width))
+	}
+	return b.String()
+}
+
+// moveColumn moves the selected column by delta positions in the display
+// order, keeping it selected so repeated presses keep moving it.
+func (m *Model) moveColumn(delta int) {
+	target := m.selectedColumn + delta
+	if target < 0 || target >= len(m.columns) {
+		return
+	}
+	m.columns[m.selectedColumn], m.columns[target] = m.columns[target], m.columns[m.selectedColumn]
+	m.selectedColumn = target
+}
+
+// handleColumnKey handles input while column mode is active.
+func (m *Model) handleColumnKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "h", "left":
+		if m.selectedColumn > 0 {
+			m.selectedColumn--
+		}
+	case "l", "right":
+		if m.selectedColumn < len(m.columns)-1 {
+			m.selectedColumn++
+		}
+	case "<":
+		m.moveColumn(-1)
+	case ">":
+		m.moveColumn(1)
+	case "s":
+		m.sortByColumn()
+	case "S":
+		m.clearSort()
+	case "C", "enter", "esc":
+		m.columnMode = false
+	}
+	return m, nil
+}
@@ -0,0 +1,269 @@
+package tui
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lbe/jsonlogviewer/internal/parser"
+)
+
+// TestColumnReorder verifies moving a column changes the rendered order.
+func TestColumnReorder(t *testing.T) {
+	content := `{"time":"2024-01-01T00:00:00Z","level":"info","msg":"test message"}`
+	idx := createTestIndex(t, content)
+	defer closeIndex(idx)
+
+	m := New(idx)
+	m.width = 120
+	m.height = 30
+
+	header := m.renderTableHeader()
+	if strings.Index(header, "Time") > strings.Index(header, "Lvl") {
+		t.Fatalf("expected Time before Lvl by default, got %q", header)
+	}
+
+	// Enter column mode and move the first column (Time) one to the right
+	for _, msg := range []tea.KeyMsg{
+		{Type: tea.KeyRunes, Runes: []rune{'C'}},
+		{Type: tea.KeyRunes, Runes: []rune{'>'}},
+	} {
+		newM, _ := m.Update(msg)
+		m = *newM.(*Model)
+	}
+
+	if !m.columnMode {
+		t.Error("expected column mode to be active")
+	}
+	if m.columns[0].key != "level" || m.columns[1].key != "time" {
+		t.Errorf("expected level,time order, got %s,%s", m.columns[0].key, m.columns[1].key)
+	}
+	if m.selectedColumn != 1 {
+		t.Errorf("expected moved column to stay selected, got %d", m.selectedColumn)
+	}
+
+	header = m.renderTableHeader()
+	if strings.Index(header, "Lvl") > strings.Index(header, "Time") {
+		t.Errorf("expected Lvl before Time after reorder, got %q", header)
+	}
+
+	// The rows follow the header order
+	row := m.formatRow(mustParse(t, &m, 1))
+	if strings.Index(row, "INF") > strings.Index(row, "2024") {
+		t.Errorf("expected level cell before time cell, got %q", row)
+	}
+
+	// Esc leaves column mode without triggering the quit prompt
+	newM, _ := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = *newM.(*Model)
+	if m.columnMode {
+		t.Error("expected column mode to end after Esc")
+	}
+	if m.confirmExit {
+		t.Error("Esc in column mode should not prompt to quit")
+	}
+}
+
+// TestColumnModeBounds verifies selection and moves stop at the edges.
+func TestColumnModeBounds(t *testing.T) {
+	content := `{"time":"2024-01-01T00:00:00Z","level":"info","msg":"test"}`
+	idx := createTestIndex(t, content)
+	defer closeIndex(idx)
+
+	m := New(idx)
+	m.columnMode = true
+
+	m.moveColumn(-1)
+	if m.columns[0].key != "time" {
+		t.Error("moving the first column left should be a no-op")
+	}
+
+	for i := 0; i < 5; i++ {
+		newM, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'l'}})
+		m = *newM.(*Model)
+	}
+	if m.selectedColumn != len(m.columns)-1 {
+		t.Errorf("expected selection clamped to last column, got %d", m.selectedColumn)
+	}
+	m.moveColumn(1)
+	if m.columns[len(m.columns)-1].key != "msg" {
+		t.Error("moving the last column right should be a no-op")
+	}
+}
+
+// mustParse parses the given line of the model's index or fails the test.
+func mustParse(t *testing.T, m *Model, n int) *parser.LogEntry {
+	t.Helper()
+	line, err := m.idx.GetLine(n)
+	if err != nil {
+		t.Fatalf("GetLine(%d) failed: %v", n, err)
+	}
+	entry, err := m.parser.Parse(line, n)
+	if err != nil {
+		t.Fatalf("Parse(%d) failed: %v", n, err)
+	}
+	return entry
+}
+
+// TestAdjustMsgLen verifies } reveals more of a long message than the
+// default column and parser limit allow, and { shrinks it back.
+func TestAdjustMsgLen(t *testing.T) {
+	long := "start " + strings.Repeat("word ", 40) + "finish"
+	content := `{"time":"2024-01-01T00:00:00Z","level":"info","msg":"` + long + `"}`
+	idx := createTestIndex(t, content)
+	defer closeIndex(idx)
+
+	m := New(idx)
+	initial := m.msgColumnWidth()
+	if strings.Contains(m.formatRow(mustParse(t, &m, 1)), "finish") {
+		t.Fatal("expected the default row to cut the message")
+	}
+
+	// Past the parser's default 100 characters to the full message
+	for i := 0; i < 20; i++ {
+		sendKeys(&m, "}")
+	}
+	if got := m.msgColumnWidth(); got <= parser.DefaultMaxMsgLen {
+		t.Fatalf("expected the column to grow past %d, got %d", parser.DefaultMaxMsgLen, got)
+	}
+	if !strings.Contains(m.formatRow(mustParse(t, &m, 1)), "finish") {
+		t.Error("expected the whole message after increasing the length")
+	}
+
+	for i := 0; i < 100; i++ {
+		sendKeys(&m, "{")
+	}
+	row := m.formatRow(mustParse(t, &m, 1))
+	if m.msgColumnWidth() != minMsgLen {
+		t.Errorf("expected length to stop at %d, got %d", minMsgLen, m.msgColumnWidth())
+	}
+	if strings.Contains(row, "word") {
+		t.Errorf("expected a %d-character message, got %q", minMsgLen, row)
+	}
+
+	m.resetView()
+	if m.msgColumnWidth() != initial {
+		t.Errorf("expected reset to restore width %d, got %d", initial, m.msgColumnWidth())
+	}
+}
+
+// TestSetColumns verifies a -columns spec picks the columns and their
+// headers, extracting custom fields by gjson path.
+func TestSetColumns(t *testing.T) {
+	content := `{"time":"2024-01-01T00:00:00Z","level":"info","msg":"hi","request_id":"req-42","user":{"name":"ada"}}`
+	idx := createTestIndex(t, content)
+	defer closeIndex(idx)
+
+	m := New(idx)
+	if err := m.SetColumns("level, request_id, user.name:User"); err != nil {
+		t.Fatalf("SetColumns failed: %v", err)
+	}
+
+	header := m.formatHeader()
+	for _, want := range []string{"Lvl", "request_id", "User"} {
+		if !strings.Contains(header, want) {
+			t.Errorf("expected %q in header %q", want, header)
+		}
+	}
+	if strings.Contains(header, "Time") || strings.Contains(header, "Message") {
+		t.Errorf("expected only the configured columns, got %q", header)
+	}
+
+	row := m.formatRow(mustParse(t, &m, 1))
+	if !strings.Contains(row, "INF") || !strings.Contains(row, "req-42") || !strings.Contains(row, "ada") {
+		t.Errorf("expected level and extracted fields in row, got %q", row)
+	}
+	if got, want := m.columns[1].width, minFieldWidth; got != want {
+		t.Errorf("expected request_id column width %d, got %d", want, got)
+	}
+
+	// Reset returns to the configured layout, not the built-in one
+	sendKeys(&m, "C>\n")
+	m.resetView()
+	if m.columns[0].key != "level" || len(m.columns) != 3 {
+		t.Errorf("expected reset to the configured columns, got %v", m.formatHeader())
+	}
+
+	if err := m.SetColumns("time,,msg"); err == nil {
+		t.Error("expected an error for an empty column")
+	}
+
+	// -pretty picks fields by the same spec
+	cols, err := ParseColumns("level, request_id, user.name:User")
+	if err != nil {
+		t.Fatalf("ParseColumns failed: %v", err)
+	}
+	if got, want := cols.Paths(), []string{"level", "request_id", "user.name"}; !slices.Equal(got, want) {
+		t.Errorf("expected paths %v, got %v", want, got)
+	}
+}
+
+// TestSetMsgLen verifies -msg-len widens the message column past the
+// default, and that Ctrl+r resets to it.
+func TestSetMsgLen(t *testing.T) {
+	long := "start " + strings.Repeat("word ", 30) + "finish"
+	content := `{"time":"2024-01-01T00:00:00Z","level":"info","msg":"` + long + `"}`
+	idx := createTestIndex(t, content)
+	defer closeIndex(idx)
+
+	m := New(idx)
+	m.SetMsgLen(200)
+	if m.msgColumnWidth() != 200 {
+		t.Fatalf("expected a 200-wide column, got %d", m.msgColumnWidth())
+	}
+	if !strings.Contains(m.formatRow(mustParse(t, &m, 1)), "finish") {
+		t.Error("expected the whole message in a 200-wide column")
+	}
+
+	sendKeys(&m, "{{")
+	m.resetView()
+	if m.msgColumnWidth() != 200 {
+		t.Errorf("expected reset to return to 200, got %d", m.msgColumnWidth())
+	}
+
+	m.SetMsgLen(maxMsgLen + 1)
+	if m.msgColumnWidth() != maxMsgLen {
+		t.Errorf("expected the length capped at %d, got %d", maxMsgLen, m.msgColumnWidth())
+	}
+}
+
+// TestAutoColumns verifies detected fields are added before the message
+// for as many as fit the first window size, read keys with dots, and stay
+// through resizes and Ctrl+r.
+func TestAutoColumns(t *testing.T) {
+	content := `{"level":"info","msg":"hi","user":"bob","a.b":"dotted"}`
+	idx := createTestIndex(t, content)
+	defer closeIndex(idx)
+
+	m := New(idx, WithAutoColumns([]string{"user", "status", "duration_ms", "a.b", "extra"}))
+	// Room for the four field columns (duration_ms is one wider), not five
+	m.Update(tea.WindowSizeMsg{Width: m.tableWidth() + 1 + 4*(minFieldWidth+1) + 1 + minAutoDetailWidth, Height: 20})
+
+	var keys []string
+	for _, col := range m.columns {
+		keys = append(keys, col.title)
+	}
+	if got, want := strings.Join(keys, ","), "Time,Lvl,user,status,duration_ms,a.b,Message"; got != want {
+		t.Fatalf("expected columns %s, got %s", want, got)
+	}
+	if m.detailWidth() < minAutoDetailWidth {
+		t.Errorf("expected the detail pane to keep %d columns, got %d", minAutoDetailWidth, m.detailWidth())
+	}
+	if !strings.Contains(m.renderTable(), "dotted") {
+		t.Error("expected the value of the dotted key in the table")
+	}
+
+	m.Update(tea.WindowSizeMsg{Width: 300, Height: 20})
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlR})
+	if len(m.columns) != 7 {
+		t.Errorf("expected the auto columns kept after a resize and reset, got %d columns", len(m.columns))
+	}
+
+	// A narrow terminal gets no extra columns
+	m = New(idx, WithAutoColumns([]string{"user"}))
+	m.Update(tea.WindowSizeMsg{Width: 100, Height: 20})
+	if len(m.columns) != 3 {
+		t.Errorf("expected the default columns, got %d", len(m.columns))
+	}
+}
@@ -0,0 +1,50 @@
+package tui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// runCommand executes a line entered at the : prompt.
+//
+// Supported commands:
+//
+//	N        go to line N
+//	w path   write the lines shown to path, asking before overwriting
+//	w! path  write the lines shown to path, overwriting without asking
+func (m *Model) runCommand(input string) {
+	name, arg, _ := strings.Cut(strings.TrimSpace(input), " ")
+	arg = strings.TrimSpace(arg)
+
+	if line, err := strconv.Atoi(name); err == nil {
+		m.gotoLineCommand(line)
+		return
+	}
+
+	switch name {
+	case "":
+		return
+	case "w", "w!":
+		if arg == "" {
+			m.statusMsg = "usage: :w[!] path"
+			return
+		}
+		m.confirmOverwrite(arg, name == "w!", func() { m.exportLines(arg, m.shownLines()) })
+	default:
+		m.statusMsg = fmt.Sprintf("unknown command: %s", name)
+	}
+}
+
+// gotoLineCommand moves the cursor to file line n for :n, reporting an
+// out-of-range line or a line hidden by the filters in the status line.
+func (m *Model) gotoLineCommand(n int) {
+	if n < 1 || n > m.idx.LineCount() {
+		m.statusMsg = fmt.Sprintf("line %d out of range (1-%d)", n, m.idx.LineCount())
+		return
+	}
+	m.gotoLine(n)
+	if got := m.cursorLine(); got != n {
+		m.statusMsg = fmt.Sprintf("line %d is hidden by filters; showing line %d", n, got)
+	}
+}
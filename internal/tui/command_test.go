@@ -0,0 +1,45 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// TestGotoLineCommand verifies :N jumps to a line, rejects out-of-range
+// numbers, and Esc cancels the prompt without quitting.
+func TestGotoLineCommand(t *testing.T) {
+	idx := createTestIndex(t, levelContent)
+	defer closeIndex(idx)
+
+	m := New(idx)
+
+	sendKeys(&m, ":5\n")
+	if m.cursorLine() != 5 {
+		t.Errorf("expected :5 to reach line 5, got %d", m.cursorLine())
+	}
+
+	for _, input := range []string{"0", "8", "-3"} {
+		sendKeys(&m, ":"+input+"\n")
+		if m.cursorLine() != 5 {
+			t.Errorf(":%s: expected cursor to stay on line 5, got %d", input, m.cursorLine())
+		}
+		if !strings.Contains(m.statusMsg, "out of range (1-7)") {
+			t.Errorf(":%s: expected an out-of-range message, got %q", input, m.statusMsg)
+		}
+	}
+
+	// A hidden line lands on the next shown one
+	sendKeys(&m, "+++++") // ≥ERROR: lines 5 and 6
+	sendKeys(&m, ":2\n")
+	if m.cursorLine() != 5 || !strings.Contains(m.statusMsg, "hidden") {
+		t.Errorf("expected line 5 with a hidden-line note, got %d, %q", m.cursorLine(), m.statusMsg)
+	}
+
+	sendKeys(&m, ":12")
+	m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.prompt != promptNone || m.confirmExit || m.quitting {
+		t.Error("expected Esc to close the prompt without quitting")
+	}
+}
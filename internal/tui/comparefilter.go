@@ -0,0 +1,77 @@
+package tui
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/lbe/jsonlogviewer/internal/parser"
+	"github.com/tidwall/gjson"
+)
+
+// comparePattern matches a numeric comparison filter such as
+// "duration_ms >= 500": a gjson path, an operator, and the operand.
+var comparePattern = regexp.MustCompile(`^\s*([A-Za-z0-9_.@-]+)\s*(>=|<=|==|!=|>|<)\s*(.*?)\s*$`)
+
+// compareOps evaluates each comparison operator.
+var compareOps = map[string]func(a, b float64) bool{
+	">":  func(a, b float64) bool { return a > b },
+	"<":  func(a, b float64) bool { return a < b },
+	">=": func(a, b float64) bool { return a >= b },
+	"<=": func(a, b float64) bool { return a <= b },
+	"==": func(a, b float64) bool { return a == b },
+	"!=": func(a, b float64) bool { return a != b },
+}
+
+// parseCompareFilter compiles a filter of the form "path op number", which
+// keeps lines whose field at the gjson path is a number satisfying the
+// comparison. Lines where the field is missing or not a number don't
+// match, whatever the operator; those where it's there but not a number
+// are counted for the status line. ok is false when input isn't a
+// comparison, including when the operand isn't a number, so regular
+// expressions such as "a<b" or "level!=debug" are left to
+// parseRegexFilter.
+func parseCompareFilter(input string) (f *regexFilter, ok bool) {
+	sub := comparePattern.FindStringSubmatch(input)
+	if sub == nil {
+		return nil, false
+	}
+	field, op, operand := sub[1], sub[2], sub[3]
+	want, err := strconv.ParseFloat(operand, 64)
+	if err != nil {
+		return nil, false
+	}
+
+	cmp := compareOps[op]
+	f = &regexFilter{
+		pattern: fmt.Sprintf("%s %s %s", field, op, operand),
+		compare: true,
+		match: func(raw []byte) bool {
+			v, err := strconv.ParseFloat(parser.ExtractField(raw, field), 64)
+			return err == nil && cmp(v, want)
+		},
+		nonNumeric: func(raw []byte) bool {
+			v := gjson.GetBytes(raw, field)
+			if !v.Exists() {
+				return false
+			}
+			_, err := strconv.ParseFloat(v.String(), 64)
+			return err != nil
+		},
+	}
+	return f, true
+}
+
+// nonNumericOperand returns the operand of input when it's shaped like a
+// comparison but the operand isn't a number, so the fallback to a regular
+// expression can be pointed out; otherwise it returns "".
+func nonNumericOperand(input string) string {
+	sub := comparePattern.FindStringSubmatch(input)
+	if sub == nil || sub[3] == "" {
+		return ""
+	}
+	if _, err := strconv.ParseFloat(sub[3], 64); err == nil {
+		return ""
+	}
+	return sub[3]
+}
@@ -0,0 +1,102 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// TestParseCompareFilter verifies each operator, that fields which are
+// missing or not numbers never match, that only fields which are there but
+// not numbers count as non-numeric, and that input with an operand that
+// isn't a number is not a comparison.
+func TestParseCompareFilter(t *testing.T) {
+	raw := []byte(`{"duration_ms":512,"status":"404","req":{"size":1.5},"msg":"slow"}`)
+	tests := []struct {
+		input      string
+		want       bool
+		nonNumeric bool
+	}{
+		{`duration_ms > 500`, true, false},
+		{`duration_ms>512`, false, false},
+		{`duration_ms >= 512`, true, false},
+		{`duration_ms < 1e3`, true, false},
+		{`duration_ms <= 511.9`, false, false},
+		{`duration_ms == 512`, true, false},
+		{`duration_ms != 512`, false, false},
+		{`status == 404`, true, false},
+		{`req.size < 2`, true, false},
+		{`msg != 0`, false, true},
+		{`req != 0`, false, true},
+		{`missing != 0`, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			f, ok := parseCompareFilter(tt.input)
+			if !ok {
+				t.Fatal("expected a comparison")
+			}
+			if got := f.match(raw); got != tt.want {
+				t.Errorf("expected match %v, got %v", tt.want, got)
+			}
+			if got := f.nonNumeric(raw); got != tt.nonNumeric {
+				t.Errorf("expected non-numeric %v, got %v", tt.nonNumeric, got)
+			}
+		})
+	}
+
+	for _, input := range []string{`msg=~^a`, `error`, `a = b`, `duration_ms > fast`, `duration_ms >`, `a<b`, `foo->bar`, `level!=debug`} {
+		if _, ok := parseCompareFilter(input); ok {
+			t.Errorf("%q: expected not a comparison", input)
+		}
+	}
+}
+
+// TestCompareFilter verifies a comparison at the & prompt filters the rows
+// and shows in the status line with the count of lines whose field isn't a
+// number, and that input shaped like a comparison with an operand that
+// isn't a number is a regular expression, which the status line points
+// out.
+func TestCompareFilter(t *testing.T) {
+	idx := createTestIndex(t, `{"msg":"a","duration_ms":120}
+{"msg":"b","duration_ms":800}
+{"msg":"c","duration_ms":"fast"}
+{"msg":"d"}
+{"msg":"e","duration_ms":501}`)
+	defer closeIndex(idx)
+
+	m := New(idx)
+	m.width = 120
+	m.height = 30
+
+	submitRegexFilter(&m, "duration_ms > 500")
+	var got []int
+	for pos := 1; pos <= m.rowCount(); pos++ {
+		got = append(got, m.lineAt(pos))
+	}
+	if fmt.Sprint(got) != "[2 5]" {
+		t.Errorf("expected rows [2 5], got %v", got)
+	}
+	if want := "&duration_ms > 500 2 matches, 1 not a number"; m.regexState() != want {
+		t.Errorf("expected state %q, got %q", want, m.regexState())
+	}
+
+	for _, pattern := range []string{`duration_ms > fast`, `msg!=b`, `a<b`} {
+		submitRegexFilter(&m, pattern)
+		if m.prompt != promptNone || m.regex == nil || m.regex.compare {
+			t.Fatalf("%q: expected a regex filter, got prompt %v", pattern, m.prompt)
+		}
+	}
+	submitRegexFilter(&m, `duration_ms > fast`)
+	if want := `"fast" isn't a number, so this is a regular expression`; !strings.Contains(m.statusMsg, want) {
+		t.Errorf("expected the fallback pointed out, got status %q", m.statusMsg)
+	}
+	if strings.Contains(m.regexState(), "not a number") {
+		t.Errorf("expected no non-numeric count for a regex, got %q", m.regexState())
+	}
+	submitRegexFilter(&m, `"msg":"[bc]"`)
+	if want := `&/"msg":"[bc]"/ 2 matches`; m.regexState() != want {
+		t.Errorf("expected state %q, got %q", want, m.regexState())
+	}
+}
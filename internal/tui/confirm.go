@@ -0,0 +1,49 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// confirmation is a pending y/n question shown in the status line; action
+// runs only if the user answers y.
+type confirmation struct {
+	question string
+	action   func()
+}
+
+// SetForce makes file-writing commands overwrite existing files without
+// asking, as if every write used the ! form.
+func (m *Model) SetForce(force bool) {
+	m.force = force
+}
+
+// confirmOverwrite runs action immediately if path doesn't exist yet (or
+// force is set), and otherwise asks before overwriting it. Every feature
+// that writes a file goes through here so overwrites behave the same way.
+func (m *Model) confirmOverwrite(path string, force bool, action func()) {
+	if _, err := os.Stat(path); err != nil || force || m.force {
+		action()
+		return
+	}
+	m.confirm = &confirmation{
+		question: fmt.Sprintf("Overwrite %s? (y/n)", path),
+		action:   action,
+	}
+}
+
+// handleConfirmKey answers the pending confirmation: y runs its action and
+// any other key cancels it.
+func (m *Model) handleConfirmKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	c := m.confirm
+	m.confirm = nil
+	switch msg.String() {
+	case "y", "Y":
+		c.action()
+	default:
+		m.statusMsg = "cancelled"
+	}
+	return m, nil
+}
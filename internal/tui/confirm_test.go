@@ -0,0 +1,163 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const exportContent = `{"time":"2024-01-01T00:00:00Z","level":"info","msg":"one"}
+{"time":"2024-01-01T00:00:01Z","level":"warn","msg":"two"}`
+
+// readFile returns the contents of path, failing the test on error.
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", path, err)
+	}
+	return string(data)
+}
+
+// TestWriteCommand verifies :w writes every line to a new file without
+// asking.
+func TestWriteCommand(t *testing.T) {
+	idx := createTestIndex(t, exportContent)
+	defer closeIndex(idx)
+
+	m := New(idx)
+	path := filepath.Join(t.TempDir(), "out.log")
+	sendKeys(&m, ":w "+path+"\n")
+
+	if m.confirm != nil {
+		t.Fatal("expected no confirmation for a new file")
+	}
+	if got := readFile(t, path); got != exportContent+"\n" {
+		t.Errorf("unexpected export contents: %q", got)
+	}
+	if !strings.Contains(m.statusMsg, "wrote 2 lines") {
+		t.Errorf("expected a success message, got %q", m.statusMsg)
+	}
+}
+
+// TestConfirmOverwrite verifies writing over an existing file asks first,
+// n keeps the file, y overwrites it, and :w! skips the question.
+func TestConfirmOverwrite(t *testing.T) {
+	idx := createTestIndex(t, exportContent)
+	defer closeIndex(idx)
+
+	m := New(idx)
+	m.width = 120
+	m.height = 30
+	path := filepath.Join(t.TempDir(), "out.log")
+	if err := os.WriteFile(path, []byte("keep me\n"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	sendKeys(&m, ":w "+path+"\n")
+	if m.confirm == nil {
+		t.Fatal("expected a confirmation for an existing file")
+	}
+	if !strings.Contains(m.View(), "Overwrite") {
+		t.Error("expected the question in the status line")
+	}
+	sendKeys(&m, "n")
+	if m.confirm != nil || readFile(t, path) != "keep me\n" {
+		t.Error("expected n to cancel and leave the file alone")
+	}
+
+	sendKeys(&m, ":w "+path+"\ny")
+	if got := readFile(t, path); got != exportContent+"\n" {
+		t.Errorf("expected y to overwrite, got %q", got)
+	}
+
+	if err := os.WriteFile(path, []byte("keep me\n"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	sendKeys(&m, ":w! "+path+"\n")
+	if m.confirm != nil {
+		t.Error("expected :w! to skip the confirmation")
+	}
+	if got := readFile(t, path); got != exportContent+"\n" {
+		t.Errorf("expected :w! to overwrite, got %q", got)
+	}
+}
+
+// TestForceSkipsConfirm verifies SetForce (the -force flag) skips the
+// overwrite question for plain :w.
+func TestForceSkipsConfirm(t *testing.T) {
+	idx := createTestIndex(t, exportContent)
+	defer closeIndex(idx)
+
+	m := New(idx)
+	m.SetForce(true)
+	path := filepath.Join(t.TempDir(), "out.log")
+	if err := os.WriteFile(path, []byte("old\n"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	sendKeys(&m, ":w "+path+"\n")
+	if m.confirm != nil {
+		t.Error("expected no confirmation with force set")
+	}
+	if got := readFile(t, path); got != exportContent+"\n" {
+		t.Errorf("expected the file to be overwritten, got %q", got)
+	}
+}
+
+// TestUnknownCommand verifies unrecognized commands report an error.
+func TestUnknownCommand(t *testing.T) {
+	idx := createTestIndex(t, exportContent)
+	defer closeIndex(idx)
+
+	m := New(idx)
+	sendKeys(&m, ":frobnicate\n")
+	if !strings.Contains(m.statusMsg, "unknown command") {
+		t.Errorf("expected an unknown command message, got %q", m.statusMsg)
+	}
+}
+
+// TestExportMatches verifies w writes the search matches, or the filtered
+// lines without a search, and refuses when neither is active.
+func TestExportMatches(t *testing.T) {
+	content := `{"level":"info","msg":"disk ok"}
+{"level":"error","msg":"disk full"}
+{"level":"error","msg":"net down"}`
+	idx := createTestIndex(t, content)
+	defer closeIndex(idx)
+
+	m := New(idx)
+	dir := t.TempDir()
+
+	sendKeys(&m, "w")
+	if m.prompt != promptNone || !strings.Contains(m.statusMsg, "no search or filter") {
+		t.Fatalf("expected w to refuse without a search or filter, got %q", m.statusMsg)
+	}
+
+	// Filter only: every shown line
+	sendKeys(&m, "+++++")
+	filtered := filepath.Join(dir, "errors.log")
+	sendKeys(&m, "w"+filtered+"\n")
+	lines := strings.Split(content, "\n")
+	if got := readFile(t, filtered); got != lines[1]+"\n"+lines[2]+"\n" {
+		t.Errorf("unexpected filtered export: %q", got)
+	}
+
+	// Search within the filter: only shown matches
+	sendKeys(&m, "/disk\n")
+	matched := filepath.Join(dir, "disk.log")
+	sendKeys(&m, "w"+matched+"\n")
+	if got := readFile(t, matched); got != lines[1]+"\n" {
+		t.Errorf("unexpected search export: %q", got)
+	}
+	if !strings.Contains(m.statusMsg, "wrote 1 lines to "+matched) {
+		t.Errorf("expected a count and path in the status, got %q", m.statusMsg)
+	}
+
+	// Write errors are reported, not fatal
+	sendKeys(&m, "w"+filepath.Join(dir, "missing", "x.log")+"\n")
+	if !strings.Contains(m.statusMsg, "export failed") {
+		t.Errorf("expected the write error in the status line, got %q", m.statusMsg)
+	}
+}
@@ -0,0 +1,74 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/lbe/jsonlogviewer/internal/parser"
+)
+
+// maxContextLines caps how many entries are previewed on each side of the
+// cursor.
+const maxContextLines = 10
+
+// adjustContext changes how many neighboring entries are previewed on each
+// side of the cursor, keeping the count within 0..maxContextLines.
+func (m *Model) adjustContext(delta int) {
+	m.contextLines += delta
+	if m.contextLines < 0 {
+		m.contextLines = 0
+	}
+	if m.contextLines > maxContextLines {
+		m.contextLines = maxContextLines
+	}
+	if m.contextLines == 0 {
+		m.statusMsg = "context preview off"
+	} else {
+		m.statusMsg = fmt.Sprintf("context preview: %d above/below", m.contextLines)
+	}
+}
+
+// contextRows returns the line numbers previewed around the cursor in file
+// order, skipping the cursor itself and anything past either end of the file.
+func (m *Model) contextRows() []int {
+	cursor := m.cursorLine()
+	if cursor == 0 {
+		return nil
+	}
+	var rows []int
+	for n := cursor - m.contextLines; n <= cursor+m.contextLines; n++ {
+		if n < 1 || n > m.idx.LineCount() || n == cursor {
+			continue
+		}
+		rows = append(rows, n)
+	}
+	return rows
+}
+
+// renderContext renders the context preview shown at the bottom of the
+// detail pane: a divider, then one compact line per neighboring entry with
+// its distance from the cursor, level, and message. Lines are always cut to
+// the pane width, even in no-truncate mode, since they only summarize.
+// Returns nil when the preview is off or there are no neighbors.
+func (m *Model) renderContext() []string {
+	rows := m.contextRows()
+	if len(rows) == 0 {
+		return nil
+	}
+
+	lines := []string{m.styles.Separator.Render("── context " + strings.Repeat("─", 20))}
+	cursor := m.cursorLine()
+	var entry parser.LogEntry
+	for _, n := range rows {
+		text := fmt.Sprintf("%+3d", n-cursor)
+		if line, err := m.idx.GetLine(n); err == nil && m.parseLine(line, n, &entry) == nil {
+			text += " " + parser.ShortenLevel(entry.Level) + " " + parser.NormalizeCell(entry.Msg)
+		}
+		if width := m.detailWidth(); width > 0 {
+			text = ansi.Truncate(text, width, "...")
+		}
+		lines = append(lines, m.styles.Help.Render(text))
+	}
+	return lines
+}
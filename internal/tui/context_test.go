@@ -0,0 +1,76 @@
+package tui
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+const contextContent = `{"time":"2024-01-01T00:00:00Z","level":"info","msg":"one"}
+{"time":"2024-01-01T00:00:01Z","level":"info","msg":"two"}
+{"time":"2024-01-01T00:00:02Z","level":"warn","msg":"three"}
+{"time":"2024-01-01T00:00:03Z","level":"info","msg":"four"}
+{"time":"2024-01-01T00:00:04Z","level":"error","msg":"five"}`
+
+// TestContextPreviewCount verifies ( and ) change the preview count within
+// its limits.
+func TestContextPreviewCount(t *testing.T) {
+	idx := createTestIndex(t, contextContent)
+	defer closeIndex(idx)
+
+	m := New(idx)
+	sendKeys(&m, "))")
+	if m.contextLines != 2 {
+		t.Errorf("expected 2 context lines, got %d", m.contextLines)
+	}
+	sendKeys(&m, "(((")
+	if m.contextLines != 0 {
+		t.Errorf("expected context to stop at 0, got %d", m.contextLines)
+	}
+	sendKeys(&m, strings.Repeat(")", maxContextLines+5))
+	if m.contextLines != maxContextLines {
+		t.Errorf("expected context to stop at %d, got %d", maxContextLines, m.contextLines)
+	}
+}
+
+// TestContextPreviewBoundaries verifies the preview skips rows past either
+// end of the file.
+func TestContextPreviewBoundaries(t *testing.T) {
+	idx := createTestIndex(t, contextContent)
+	defer closeIndex(idx)
+
+	m := New(idx)
+	m.Update(tea.WindowSizeMsg{Width: 140, Height: 30})
+	sendKeys(&m, "))")
+
+	tests := []struct {
+		cursor int
+		want   []int
+	}{
+		{1, []int{2, 3}},
+		{3, []int{1, 2, 4, 5}},
+		{5, []int{3, 4}},
+	}
+	for _, tt := range tests {
+		m.viewport.Goto(tt.cursor)
+		if got := m.contextRows(); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("cursor %d: expected rows %v, got %v", tt.cursor, tt.want, got)
+		}
+	}
+
+	// The preview sits at the bottom of the detail pane
+	m.viewport.Goto(1)
+	lines := strings.Split(ansi.Strip(m.renderDetail(m.viewport.Height)), "\n")
+	if len(lines) != m.viewport.Height {
+		t.Fatalf("expected %d detail lines, got %d", m.viewport.Height, len(lines))
+	}
+	tail := strings.Join(lines[len(lines)-3:], "\n")
+	for _, want := range []string{"context", " +1 INF two", " +2 WRN three"} {
+		if !strings.Contains(tail, want) {
+			t.Errorf("expected %q in preview, got %q", want, tail)
+		}
+	}
+}
@@ -0,0 +1,298 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/lbe/jsonlogviewer/internal/parser"
+	"github.com/tidwall/gjson"
+)
+
+// detailHScrollStep is how many columns zh/zl move the detail pane.
+const detailHScrollStep = 8
+
+// detailWrapHang is how much further than the line itself wrapped
+// continuation lines are indented.
+const detailWrapHang = 2
+
+// detailMode selects how the detail pane lays out the selected entry.
+type detailMode int
+
+const (
+	// detailPretty shows the entry as indented JSON.
+	detailPretty detailMode = iota
+	// detailFields shows the entry flattened to a key/value table.
+	detailFields
+	// detailTree shows the entry as a tree whose objects and arrays fold.
+	detailTree
+	// detailModeCount is the number of detail modes zv cycles through.
+	detailModeCount
+)
+
+// detailModeNames are the status-line names of each detail mode.
+var detailModeNames = [detailModeCount]string{"pretty JSON", "fields", "tree"}
+
+// cycleDetailMode switches the detail pane to its next layout.
+func (m *Model) cycleDetailMode() {
+	m.detailMode = (m.detailMode + 1) % detailModeCount
+	m.detailOffset = 0
+	m.detailHOffset = 0
+	m.statusMsg = "detail: " + detailModeNames[m.detailMode]
+}
+
+// detailBody returns the lines of raw, file line n, in the current detail
+// mode, falling back to the raw line when it can't be laid out.
+func (m *Model) detailBody(raw []byte, n int) []string {
+	if m.fieldsLayout(raw) {
+		if lines := m.fieldLines(raw); lines != nil {
+			return lines
+		}
+	}
+	if m.treeLayout(raw) {
+		if lines := m.treeLines(raw); lines != nil {
+			return lines
+		}
+	}
+	formatted, err := m.formatLine(raw, n)
+	if err != nil {
+		// Show raw if formatting fails
+		formatted = string(raw)
+	}
+	// The raw fallback may be binary garbage, so control bytes are made
+	// visible before any styling adds escape sequences of its own
+	lines := strings.Split(formatted, "\n")
+	for i, line := range lines {
+		lines[i] = m.detailANSI(m.markSearch(parser.SanitizeForDisplay(expandIndent(line))))
+	}
+	return lines
+}
+
+// detailTabWidth is how many columns a tab of indentation takes in the
+// detail pane.
+const detailTabWidth = 4
+
+// expandIndent replaces the tabs indenting a line of formatted JSON with
+// spaces, since display widths count a tab as zero columns and the pane's
+// cutting and wrapping would misjudge the line. JSON escapes tabs inside
+// strings, so only the indentation holds them.
+func expandIndent(line string) string {
+	body := strings.TrimLeft(line, "\t")
+	if tabs := len(line) - len(body); tabs > 0 {
+		return strings.Repeat(" ", tabs*detailTabWidth) + body
+	}
+	return line
+}
+
+// SetFormat sets the line format: JSON, logfmt, or parser.FormatAuto to
+// detect each line. Call it before the program starts so the parse error
+// scan uses it too.
+func (m *Model) SetFormat(f parser.Format) {
+	m.parser.SetFormat(f)
+	m.invalidateEntries()
+}
+
+// SetIndent sets the indentation of pretty-printed JSON in the detail pane,
+// the pager, and copies, such as four spaces or a tab; see
+// parser.ParseIndent. An empty indent restores two spaces.
+func (m *Model) SetIndent(indent string) {
+	m.parser.SetIndent(indent)
+	m.invalidateEntries()
+}
+
+// fieldsLayout reports whether raw is shown as a key/value table: always
+// for a logfmt line, which has no nesting to pretty-print, and for JSON in
+// fields mode.
+func (m *Model) fieldsLayout(raw []byte) bool {
+	return m.detailMode == detailFields || m.parser.IsLogfmt(raw)
+}
+
+// detailANSI handles ANSI codes embedded in a line of formatted JSON,
+// rendering their colors or stripping them as toggled with za.
+func (m *Model) detailANSI(line string) string {
+	if m.stripDetailANSI {
+		return parser.StripEscapedANSI(line)
+	}
+	return parser.InterpretEscapedANSI(line)
+}
+
+// fieldLines renders raw as a two-column table of dotted paths (or logfmt
+// keys) and values, with the keys padded to a common width. Returns nil
+// when raw is neither a JSON object nor logfmt.
+func (m *Model) fieldLines(raw []byte) []string {
+	kvs := m.parser.Fields(raw)
+	if kvs == nil {
+		return nil
+	}
+	keys := make([]string, len(kvs))
+	keyWidth := 0
+	for i, kv := range kvs {
+		keys[i] = parser.NormalizeCell(kv.Key)
+		keyWidth = max(keyWidth, ansi.StringWidth(keys[i]))
+	}
+	lines := make([]string, 0, len(kvs))
+	for i, kv := range kvs {
+		pad := strings.Repeat(" ", keyWidth-ansi.StringWidth(keys[i]))
+		lines = append(lines, m.styles.Help.Render(keys[i]+pad+" =")+" "+m.markSearch(parser.NormalizeCell(kv.Value)))
+	}
+	return lines
+}
+
+// SetNoTruncate turns no-truncate detail mode on or off. In this mode the
+// detail pane never cuts a line; long lines are reached by scrolling
+// horizontally with zh/zl. It replaces wrapping.
+func (m *Model) SetNoTruncate(on bool) {
+	m.noTruncate = on
+	m.detailHOffset = 0
+	if on {
+		m.wrapDetail = false
+	}
+}
+
+// SetWrapDetail turns detail wrapping on or off. Wrapped lines fold at the
+// pane edge instead of being cut; it replaces no-truncate mode.
+func (m *Model) SetWrapDetail(on bool) {
+	m.wrapDetail = on
+	m.detailOffset = 0
+	if on {
+		m.noTruncate = false
+		m.detailHOffset = 0
+	}
+}
+
+// wrapLines folds lines wider than width onto continuation lines with a
+// hanging indent, so a wrapped value stays visibly under its key. Deep
+// indentation is capped at half the width to leave room for the text.
+func wrapLines(lines []string, width int) []string {
+	if width <= 0 {
+		return lines
+	}
+	wrapped := make([]string, 0, len(lines))
+	for _, line := range lines {
+		if ansi.StringWidth(line) <= width {
+			wrapped = append(wrapped, line)
+			continue
+		}
+		body := strings.TrimLeft(line, " ")
+		indent := min(len(line)-len(body), width/2)
+		hang := min(indent+detailWrapHang, width/2)
+		for i, row := range strings.Split(ansi.Wrap(body, width-hang, ""), "\n") {
+			if i == 0 {
+				wrapped = append(wrapped, strings.Repeat(" ", indent)+row)
+			} else {
+				wrapped = append(wrapped, strings.Repeat(" ", hang)+row)
+			}
+		}
+	}
+	return wrapped
+}
+
+// detailLines lays out the detail pane for raw, file line n: the raw line
+// when shown, the entry with the field under the field cursor highlighted,
+// and any parse errors, wrapped to the pane when wrapping is on. fields
+// holds the index among lines where each selectable field starts.
+func (m *Model) detailLines(raw []byte, n int) (lines []string, fields []int) {
+	body := m.detailBody(raw, n)
+	fields = m.fieldStarts(raw, body)
+	if m.detailFocus && m.fieldCursor < len(fields) {
+		i := fields[m.fieldCursor]
+		body[i] = m.styles.Selected.Render(ansi.Strip(body[i]))
+	}
+	if m.showRaw {
+		// Raw line on top so extraction problems can be compared against
+		// exactly what was read; one scroll offset covers both parts
+		top := m.rawLines(raw)
+		for i := range fields {
+			fields[i] += len(top)
+		}
+		body = append(top, body...)
+	}
+	lines = append(body, m.errorLines(raw)...)
+	if !m.wrapDetail {
+		return lines, fields
+	}
+
+	// Wrap before scrolling so the offset counts wrapped lines
+	width := m.detailWidth()
+	starts := make([]int, len(lines))
+	wrapped := make([]string, 0, len(lines))
+	for i, line := range lines {
+		starts[i] = len(wrapped)
+		wrapped = append(wrapped, wrapLines([]string{line}, width)...)
+	}
+	for i, f := range fields {
+		fields[i] = starts[f]
+	}
+	return wrapped, fields
+}
+
+// scrollDetailH moves the detail pane's horizontal offset by delta columns.
+// It only applies in no-truncate mode.
+func (m *Model) scrollDetailH(delta int) {
+	if !m.noTruncate {
+		m.statusMsg = "horizontal scroll needs no-truncate mode (zn)"
+		return
+	}
+	m.detailHOffset += delta
+	m.clampDetailHOffset()
+}
+
+// clampDetailHOffset keeps the horizontal offset within the widest line,
+// stopping once its last column is visible.
+func (m *Model) clampDetailHOffset() {
+	maxOffset := m.detailMaxWidth - m.detailWidth()
+	if m.detailHOffset > maxOffset {
+		m.detailHOffset = maxOffset
+	}
+	if m.detailHOffset < 0 {
+		m.detailHOffset = 0
+	}
+}
+
+// detailHState describes the visible detail columns, e.g. "cols 9-80/120".
+func (m *Model) detailHState() string {
+	last := m.detailHOffset + m.detailWidth()
+	if last > m.detailMaxWidth {
+		last = m.detailMaxWidth
+	}
+	return fmt.Sprintf("cols %d-%d/%d", m.detailHOffset+1, last, m.detailMaxWidth)
+}
+
+// maxLineWidth returns the display width of the widest line.
+func maxLineWidth(lines []string) int {
+	widest := 0
+	for _, line := range lines {
+		if w := ansi.StringWidth(line); w > widest {
+			widest = w
+		}
+	}
+	return widest
+}
+
+// errorLines renders the structured error in raw, if any, as an extra
+// section below the pretty JSON: the message in bold red, then one
+// indented line per stack frame. Returns nil when there's no error object.
+// gjson decodes escapes such as \u001b, so the text is sanitized before
+// styling like the rest of the pane.
+func (m *Model) errorLines(raw []byte) []string {
+	text, ok := parser.FormatError(gjson.ParseBytes(raw))
+	if !ok {
+		return nil
+	}
+	parts := strings.Split(parser.SanitizeForDisplay(text), "\n")
+	lines := []string{"", m.styles.ErrorMsg.Render(parts[0])}
+	for _, frame := range parts[1:] {
+		lines = append(lines, m.styles.Detail.Render(frame))
+	}
+	return lines
+}
+
+// rawLines renders the raw line for the top of the split detail view,
+// followed by a divider introducing the formatted JSON.
+func (m *Model) rawLines(raw []byte) []string {
+	return []string{
+		m.styles.Help.Render("── raw " + strings.Repeat("─", 20)),
+		parser.NormalizeCell(string(raw)),
+		m.styles.Help.Render("── formatted " + strings.Repeat("─", 14)),
+	}
+}
@@ -0,0 +1,551 @@
+package tui
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// TestNoTruncateReachesAllContent verifies every character of a line wider
+// than the detail pane can be brought into view with zl.
+func TestNoTruncateReachesAllContent(t *testing.T) {
+	long := "BEGIN-" + strings.Repeat("0123456789", 20) + "-END"
+	content := `{"time":"2024-01-01T00:00:00Z","level":"info","msg":"x","payload":"` + long + `"}`
+	idx := createTestIndex(t, content)
+	defer closeIndex(idx)
+
+	m := New(idx)
+	m.Update(tea.WindowSizeMsg{Width: 120, Height: 20})
+
+	// Default mode cuts the line
+	if strings.Contains(m.renderDetail(m.viewport.Height), "-END") {
+		t.Fatal("expected the default detail to truncate the long line")
+	}
+
+	sendKeys(&m, "zn")
+	if !m.noTruncate {
+		t.Fatal("expected zn to enable no-truncate mode")
+	}
+
+	payload := -1
+	for i, line := range strings.Split(ansi.Strip(m.renderDetail(m.viewport.Height)), "\n") {
+		if strings.Contains(line, `"payload"`) {
+			payload = i
+		}
+	}
+	if payload < 0 {
+		t.Fatal("expected the payload field in the detail pane")
+	}
+
+	// Scroll right until the offset stops moving, collecting what was shown
+	var seen strings.Builder
+	width := m.detailWidth()
+	for i := 0; i < 100; i++ {
+		lines := strings.Split(ansi.Strip(m.renderDetail(m.viewport.Height)), "\n")
+		for _, line := range lines {
+			if ansi.StringWidth(line) > width {
+				t.Fatalf("line wider than the pane: %q", line)
+			}
+		}
+		seen.WriteString(lines[payload])
+		before := m.detailHOffset
+		sendKeys(&m, "zl")
+		if m.detailHOffset == before {
+			break
+		}
+	}
+
+	for _, want := range []string{"BEGIN-", "-END"} {
+		if !strings.Contains(seen.String(), want) {
+			t.Errorf("expected %q to become visible while scrolling", want)
+		}
+	}
+	if m.detailHOffset != m.detailMaxWidth-width {
+		t.Errorf("expected scrolling to stop at %d, got %d", m.detailMaxWidth-width, m.detailHOffset)
+	}
+	if !strings.Contains(m.View(), m.detailHState()) {
+		t.Error("expected the status line to show the horizontal position")
+	}
+
+	// zh scrolls back and stops at the first column
+	for i := 0; i < 100; i++ {
+		sendKeys(&m, "zh")
+	}
+	if m.detailHOffset != 0 {
+		t.Errorf("expected offset 0 after scrolling left, got %d", m.detailHOffset)
+	}
+}
+
+// TestDetailHScrollNeedsNoTruncate verifies zl does nothing outside
+// no-truncate mode.
+func TestDetailHScrollNeedsNoTruncate(t *testing.T) {
+	content := `{"time":"2024-01-01T00:00:00Z","level":"info","msg":"test"}`
+	idx := createTestIndex(t, content)
+	defer closeIndex(idx)
+
+	m := New(idx)
+	sendKeys(&m, "zl")
+	if m.detailHOffset != 0 {
+		t.Errorf("expected offset 0, got %d", m.detailHOffset)
+	}
+	if m.statusMsg == "" {
+		t.Error("expected a hint to enable no-truncate mode")
+	}
+}
+
+// TestLockDetailScroll verifies zs keeps the detail offset across j/k and
+// clamps it to shorter entries.
+func TestLockDetailScroll(t *testing.T) {
+	content := `{"time":"2024-01-01T00:00:00Z","level":"info","msg":"a","k1":1,"k2":2,"k3":3,"k4":4}
+{"time":"2024-01-01T00:00:01Z","level":"info","msg":"b","k1":1,"k2":2,"k3":3,"k4":4}
+{"msg":"c"}`
+	idx := createTestIndex(t, content)
+	defer closeIndex(idx)
+
+	m := New(idx)
+	m.Update(tea.WindowSizeMsg{Width: 120, Height: 20})
+	m.View()
+
+	// Unlocked: moving the cursor resets the offset
+	sendKeys(&m, "lll")
+	sendKeys(&m, "j")
+	m.View()
+	if m.detailOffset != 0 {
+		t.Fatalf("expected offset reset when unlocked, got %d", m.detailOffset)
+	}
+
+	sendKeys(&m, "zs")
+	if !m.lockDetailScroll {
+		t.Fatal("expected zs to lock the detail scroll")
+	}
+	sendKeys(&m, "lll")
+	m.View()
+
+	sendKeys(&m, "k")
+	m.View()
+	if m.detailOffset != 3 {
+		t.Errorf("expected offset 3 to persist after k, got %d", m.detailOffset)
+	}
+	sendKeys(&m, "j")
+	m.View()
+	if m.detailOffset != 3 {
+		t.Errorf("expected offset 3 to persist after j, got %d", m.detailOffset)
+	}
+
+	// Line 3 pretty-prints to 3 lines, so the offset is clamped to its last line
+	sendKeys(&m, "j")
+	m.View()
+	if m.detailOffset != 2 {
+		t.Errorf("expected offset clamped to 2, got %d", m.detailOffset)
+	}
+}
+
+// TestDetailErrorSection verifies a structured error's stack is shown one
+// frame per line below the JSON, with the message styled as an error.
+func TestDetailErrorSection(t *testing.T) {
+	content := `{"time":"2024-01-01T00:00:00Z","level":"error","msg":"save failed","error":{"type":"IOError","message":"disk full","stack":["at a.Write(A.java:10)","at b.Save(B.java:20)"]}}`
+	idx := createTestIndex(t, content)
+	defer closeIndex(idx)
+
+	m := New(idx)
+	m.Update(tea.WindowSizeMsg{Width: 160, Height: 40})
+
+	detail := m.renderDetail(m.viewport.Height)
+	if !strings.Contains(detail, m.styles.ErrorMsg.Render("IOError: disk full")) {
+		t.Error("expected the error message in the error style")
+	}
+	lines := strings.Split(ansi.Strip(detail), "\n")
+	var frames []string
+	for _, line := range lines {
+		if strings.HasPrefix(line, "    at ") {
+			frames = append(frames, line)
+		}
+	}
+	if len(frames) != 2 {
+		t.Errorf("expected 2 frame lines, got %q", frames)
+	}
+}
+
+// TestDetailErrorSanitized verifies control characters decoded from the
+// escapes of a structured error reach the screen only as placeholders.
+func TestDetailErrorSanitized(t *testing.T) {
+	content := `{"level":"error","msg":"failed","error":{"message":"boom\u001b[2J\u0000end","stack":["at \u0007a.Write(A.java:10)"]}}`
+	idx := createTestIndex(t, content)
+	defer closeIndex(idx)
+
+	m := New(idx)
+	m.Update(tea.WindowSizeMsg{Width: 160, Height: 40})
+
+	errBlock := strings.Join(m.errorLines([]byte(content)), "\n")
+	if strings.ContainsAny(errBlock, "\x00\x07") || strings.Contains(errBlock, "\x1b[2J") {
+		t.Errorf("expected no raw control characters in the error block, got %q", errBlock)
+	}
+	if plain := ansi.Strip(errBlock); !strings.Contains(plain, "boom␛[2J␀end") || !strings.Contains(plain, "at ␇a.Write") {
+		t.Errorf("expected placeholders in the error block, got %q", plain)
+	}
+	if view := m.View(); strings.ContainsAny(view, "\x00\x07") || strings.Contains(view, "\x1b[2J") {
+		t.Errorf("expected no raw control characters on screen, got %q", view)
+	}
+}
+
+// TestRawDetail verifies zr stacks the raw line above the formatted JSON,
+// and both parts share one scroll offset.
+func TestRawDetail(t *testing.T) {
+	content := `{"time":"2024-01-01T00:00:00Z","level":"info","msg":"hi","n":1}`
+	idx := createTestIndex(t, content)
+	defer closeIndex(idx)
+
+	m := New(idx)
+	m.Update(tea.WindowSizeMsg{Width: 200, Height: 30})
+
+	if strings.Contains(ansi.Strip(m.renderDetail(m.viewport.Height)), content) {
+		t.Fatal("expected no raw line before zr")
+	}
+
+	sendKeys(&m, "zr")
+	lines := strings.Split(ansi.Strip(m.renderDetail(m.viewport.Height)), "\n")
+	if !strings.Contains(lines[0], "raw") || lines[1] != content || !strings.Contains(lines[2], "formatted") {
+		t.Fatalf("expected raw section on top, got %q", lines[:3])
+	}
+	if lines[3] != "{" || !strings.Contains(lines[4], `"time": "2024-01-01T00:00:00Z"`) {
+		t.Errorf("expected formatted JSON below the divider, got %q", lines[3:5])
+	}
+
+	// Scrolling moves through the combined content
+	sendKeys(&m, "ll")
+	lines = strings.Split(ansi.Strip(m.renderDetail(m.viewport.Height)), "\n")
+	if !strings.Contains(lines[0], "formatted") {
+		t.Errorf("expected the divider at the top after scrolling 2 lines, got %q", lines[0])
+	}
+}
+
+// TestDetailANSI verifies color codes embedded in a message are stripped
+// from the table, rendered in the detail pane, and stripped there after za.
+func TestDetailANSI(t *testing.T) {
+	content := `{"level":"info","msg":"\u001b[31mred\u001b[0m alert"}`
+	idx := createTestIndex(t, content)
+	defer closeIndex(idx)
+
+	m := New(idx)
+	m.Update(tea.WindowSizeMsg{Width: 200, Height: 30})
+
+	if row := m.formatRow(mustParse(t, &m, 1)); !strings.Contains(row, "red alert") {
+		t.Errorf("expected the table message without codes, got %q", row)
+	}
+
+	detail := m.renderDetail(m.viewport.Height)
+	if strings.Contains(detail, `\u001b`) || !strings.Contains(detail, "\x1b[31mred") {
+		t.Errorf("expected the detail to render the color, got %q", detail)
+	}
+	if !strings.Contains(ansi.Strip(detail), `"msg": "red alert"`) {
+		t.Errorf("expected the message text in the detail, got %q", ansi.Strip(detail))
+	}
+
+	sendKeys(&m, "za")
+	detail = m.renderDetail(m.viewport.Height)
+	if strings.Contains(detail, "\x1b[31m") || !strings.Contains(detail, `"msg": "red alert"`) {
+		t.Errorf("expected za to strip the codes, got %q", detail)
+	}
+	m.resetView()
+	if m.stripDetailANSI {
+		t.Error("expected reset to render ANSI colors again")
+	}
+}
+
+// TestControlCharsShown verifies NUL bytes and other control characters in
+// garbage lines reach neither the table nor the detail pane raw, and that
+// their placeholders keep the table's columns aligned.
+func TestControlCharsShown(t *testing.T) {
+	content := "{\"level\":\"info\",\"msg\":\"a\\u0000b\"}\n\x00\x00bin\x1b[2Jary\x07\n{\"level\":\"warn\",\"msg\":\"ok\"}"
+	idx := createTestIndex(t, content)
+	defer closeIndex(idx)
+
+	m := New(idx)
+	m.Update(tea.WindowSizeMsg{Width: 160, Height: 20})
+
+	if row := m.formatRow(mustParse(t, &m, 1)); !strings.Contains(row, "a␀b") {
+		t.Errorf("expected the NUL in the message shown as ␀, got %q", row)
+	}
+
+	sendKeys(&m, "j")
+	view := m.View()
+	if strings.ContainsAny(view, "\x00\x07") || strings.Contains(view, "\x1b[2J") {
+		t.Errorf("expected no raw control characters on screen, got %q", view)
+	}
+	if detail := ansi.Strip(m.renderDetail(m.viewport.Height)); !strings.Contains(detail, "␀␀bin␛[2Jary␇") {
+		t.Errorf("expected the garbage line with placeholders in the detail, got %q", detail)
+	}
+
+	width := -1
+	for _, line := range strings.Split(m.renderTable(), "\n") {
+		if line = ansi.Strip(line); line == "" {
+			continue
+		}
+		if w := ansi.StringWidth(line); width < 0 {
+			width = w
+		} else if w != width {
+			t.Errorf("expected every table row %d cells wide, got %d for %q", width, w, line)
+		}
+	}
+}
+
+// TestDetailFieldsMode verifies zv switches the detail pane to a flattened
+// key/value table, scrolls it with the detail offset, and cycles on.
+func TestDetailFieldsMode(t *testing.T) {
+	content := `{"level":"info","source":{"file":"main.go","line":42},"items":["a","b"]}`
+	idx := createTestIndex(t, content)
+	defer closeIndex(idx)
+
+	m := New(idx)
+	m.Update(tea.WindowSizeMsg{Width: 200, Height: 30})
+
+	sendKeys(&m, "zv")
+	lines := strings.Split(ansi.Strip(m.renderDetail(m.viewport.Height)), "\n")
+	want := []string{
+		"level       = info",
+		"source.file = main.go",
+		"source.line = 42",
+		"items.0     = a",
+		"items.1     = b",
+	}
+	for i, w := range want {
+		if strings.TrimRight(lines[i], " ") != w {
+			t.Errorf("line %d: expected %q, got %q", i, w, lines[i])
+		}
+	}
+
+	sendKeys(&m, "l")
+	lines = strings.Split(ansi.Strip(m.renderDetail(m.viewport.Height)), "\n")
+	if !strings.HasPrefix(lines[0], "source.file") {
+		t.Errorf("expected scrolling to start at source.file, got %q", lines[0])
+	}
+
+	// Past the tree view, back to pretty JSON
+	sendKeys(&m, "zvzv")
+	lines = strings.Split(ansi.Strip(m.renderDetail(m.viewport.Height)), "\n")
+	if lines[0] != "{" {
+		t.Errorf("expected pretty JSON after cycling back, got %q", lines[0])
+	}
+}
+
+// TestDetailTreeMode verifies the tree view nests fields under their
+// objects and arrays, and Enter on one in the field cursor folds it for
+// every row while y still copies it.
+func TestDetailTreeMode(t *testing.T) {
+	content := `{"level":"info","source":{"file":"main.go","line":42},"items":["a","b"],"tags":{}}
+{"level":"warn","source":{"file":"db.go","line":7}}`
+	idx := createTestIndex(t, content)
+	defer closeIndex(idx)
+
+	m := New(idx)
+	m.Update(tea.WindowSizeMsg{Width: 200, Height: 30})
+	var copied string
+	m.copyText = func(s string) error {
+		copied = s
+		return nil
+	}
+
+	sendKeys(&m, "zvzv")
+	if m.detailMode != detailTree {
+		t.Fatalf("expected the third view to be the tree, got %s", detailModeNames[m.detailMode])
+	}
+	render := func() []string {
+		lines := strings.Split(ansi.Strip(m.renderDetail(m.viewport.Height)), "\n")
+		for i, line := range lines {
+			lines[i] = strings.TrimRight(line, " ")
+		}
+		return lines
+	}
+	want := []string{
+		"  level: info",
+		"▾ source {2}",
+		"    file: main.go",
+		"    line: 42",
+		"▾ items [2]",
+		"    0: a",
+		"    1: b",
+		"  tags: {}",
+	}
+	lines := render()
+	for i, w := range want {
+		if lines[i] != w {
+			t.Errorf("line %d: expected %q, got %q", i, w, lines[i])
+		}
+	}
+
+	// Field cursor on source: Enter folds, y copies its JSON
+	sendKeys(&m, "\tj\n")
+	if lines := render(); lines[1] != "▸ source {2}" || lines[2] != "▾ items [2]" {
+		t.Errorf("expected source folded, got %q", lines[:3])
+	}
+	sendKeys(&m, "y")
+	if copied != `{"file":"main.go","line":42}` {
+		t.Errorf("expected the folded object's JSON copied, got %q", copied)
+	}
+
+	// The fold applies on the next row too, and Enter opens it again
+	sendKeys(&m, "\tj")
+	if lines := render(); lines[1] != "▸ source {2}" {
+		t.Errorf("expected source folded on line 2, got %q", lines[1])
+	}
+	sendKeys(&m, "\tj\n")
+	if lines := render(); lines[2] != "    file: db.go" {
+		t.Errorf("expected source unfolded, got %q", lines[:3])
+	}
+}
+
+// TestDetailLogfmt verifies a logfmt line shows as an aligned key/value
+// block without switching modes, next to a JSON line still pretty-printed,
+// and that its fields can be selected.
+func TestDetailLogfmt(t *testing.T) {
+	content := `time=2024-01-15T10:30:00Z level=warn msg="disk almost full" used_pct=93
+{"level":"info","msg":"json line"}`
+	idx := createTestIndex(t, content)
+	defer closeIndex(idx)
+
+	m := New(idx)
+	m.Update(tea.WindowSizeMsg{Width: 200, Height: 30})
+
+	if view := ansi.Strip(m.View()); !strings.Contains(view, "WRN") || strings.Count(view, "disk almost full") < 2 {
+		t.Error("expected the logfmt level and message in the table")
+	}
+	lines := strings.Split(ansi.Strip(m.renderDetail(m.viewport.Height)), "\n")
+	want := []string{
+		"time     = 2024-01-15T10:30:00Z",
+		"level    = warn",
+		"msg      = disk almost full",
+		"used_pct = 93",
+	}
+	for i, w := range want {
+		if strings.TrimRight(lines[i], " ") != w {
+			t.Errorf("line %d: expected %q, got %q", i, w, lines[i])
+		}
+	}
+
+	sendKeys(&m, "\tjj")
+	if !strings.Contains(m.View(), "FIELD msg") {
+		t.Error("expected the field cursor to select msg")
+	}
+	sendKeys(&m, "\tj")
+	lines = strings.Split(ansi.Strip(m.renderDetail(m.viewport.Height)), "\n")
+	if lines[0] != "{" {
+		t.Errorf("expected the JSON line pretty-printed, got %q", lines[0])
+	}
+}
+
+// TestWrapLines verifies long lines fold at word boundaries with a hanging
+// indent, short lines are untouched, and deep indents are capped.
+func TestWrapLines(t *testing.T) {
+	tests := []struct {
+		name  string
+		line  string
+		width int
+		want  []string
+	}{
+		{"fits", `  "a": 1`, 20, []string{`  "a": 1`}},
+		{"word wrap", `  "msg": "one two three four"`, 20, []string{`  "msg": "one two`, `    three four"`}},
+		{"long word", `"k": "abcdefghijklmnop"`, 12, []string{`"k":`, `  "abcdefghi`, `  jklmnop"`}},
+		{"deep indent", `            "k": "v w"`, 16, []string{`        "k": "v`, `        w"`}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := wrapLines([]string{tt.line}, tt.width)
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+			for _, row := range got {
+				if ansi.StringWidth(row) > tt.width {
+					t.Errorf("row %q is wider than %d", row, tt.width)
+				}
+			}
+		})
+	}
+}
+
+// TestWrapDetail verifies zw wraps long values so their end is on screen,
+// the scroll offset is clamped to the wrapped line count, and wrapping and
+// no-truncate mode replace each other.
+func TestWrapDetail(t *testing.T) {
+	long := "BEGIN " + strings.Repeat("word ", 60) + "END"
+	content := `{"level":"info","msg":"x","payload":"` + long + `"}`
+	idx := createTestIndex(t, content)
+	defer closeIndex(idx)
+
+	m := New(idx)
+	m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+
+	if strings.Contains(m.View(), "END") {
+		t.Fatal("expected the value to be cut before wrapping is on")
+	}
+
+	sendKeys(&m, "zw")
+	if !m.wrapDetail {
+		t.Fatal("expected zw to turn wrapping on")
+	}
+	if !strings.Contains(m.View(), "END") {
+		t.Error("expected the end of the value on screen once wrapped")
+	}
+
+	raw, _ := idx.GetLine(1)
+	wrapped := len(wrapLines(m.detailBody(raw, 1), m.detailWidth()))
+	m.detailOffset = 1000
+	m.View()
+	if m.detailOffset != wrapped-1 {
+		t.Errorf("expected the offset clamped to %d wrapped lines, got %d", wrapped, m.detailOffset)
+	}
+
+	sendKeys(&m, "zn")
+	if m.wrapDetail || !m.noTruncate {
+		t.Error("expected zn to replace wrapping with no-truncate mode")
+	}
+	sendKeys(&m, "zw")
+	if !m.wrapDetail || m.noTruncate {
+		t.Error("expected zw to replace no-truncate mode with wrapping")
+	}
+	sendKeys(&m, "zw")
+	if m.wrapDetail {
+		t.Error("expected a second zw to turn wrapping off")
+	}
+}
+
+// TestDetailTabIndent verifies a tab indent is expanded to spaces in the
+// detail pane, so wrapped lines still fit, while copies keep the tab.
+func TestDetailTabIndent(t *testing.T) {
+	long := strings.Repeat("word ", 40)
+	content := `{"level":"info","msg":"x","req":{"note":"` + long + `"}}`
+	idx := createTestIndex(t, content)
+	defer closeIndex(idx)
+
+	m := New(idx)
+	m.Update(tea.WindowSizeMsg{Width: 140, Height: 30})
+	m.SetIndent("\t")
+	var copied string
+	m.copyText = func(s string) error {
+		copied = s
+		return nil
+	}
+
+	body := m.detailBody([]byte(content), 1)
+	if !slices.Contains(body, `        "note": "`+long+`"`) {
+		t.Errorf("expected the nested field indented by two tab widths, got %q", body)
+	}
+
+	sendKeys(&m, "zw")
+	for _, line := range strings.Split(ansi.Strip(m.renderDetail(m.viewport.Height)), "\n") {
+		if strings.Contains(line, "\t") {
+			t.Errorf("expected no tabs in the detail pane, got %q", line)
+		}
+		if w := ansi.StringWidth(line); w > m.detailWidth() {
+			t.Errorf("expected wrapped lines to fit %d columns, got %d: %q", m.detailWidth(), w, line)
+		}
+	}
+
+	sendKeys(&m, "Y")
+	if !strings.Contains(copied, "\n\t\t\"note\"") {
+		t.Errorf("expected the copy indented with tabs, got %q", copied)
+	}
+}
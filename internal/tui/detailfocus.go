@@ -0,0 +1,197 @@
+package tui
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/lbe/jsonlogviewer/internal/parser"
+)
+
+// cursorFields returns the fields of the cursor line, or nil when it is
+// neither a JSON object nor logfmt. In tree mode they are the lines of the
+// tree, objects and arrays included.
+func (m *Model) cursorFields() []parser.KV {
+	raw, err := m.idx.GetLine(m.cursorLine())
+	if err != nil {
+		return nil
+	}
+	return m.lineFields(raw)
+}
+
+// lineFields returns the fields of raw the field cursor selects among, as
+// cursorFields does for the cursor line.
+func (m *Model) lineFields(raw []byte) []parser.KV {
+	if m.treeLayout(raw) {
+		return treeFields(m.treeNodes(raw))
+	}
+	return m.parser.Fields(raw)
+}
+
+// toggleDetailFocus moves the keys between the table and the detail pane,
+// where they drive a field cursor so a value in a deep entry can be picked
+// out. A line without fields, such as one that isn't JSON, can still be
+// scrolled.
+func (m *Model) toggleDetailFocus() {
+	if m.detailFocus {
+		m.detailFocus = false
+		return
+	}
+	if m.hideDetail {
+		m.statusMsg = "detail pane hidden: zd shows it"
+		return
+	}
+	m.detailFocus = true
+	m.fieldCursor = 0
+}
+
+// handleDetailFocusKey handles input while the detail pane has the keys.
+// Without fields to select, j/k scroll the pane a line at a time.
+func (m *Model) handleDetailFocusKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	fields := m.cursorFields()
+	switch msg.String() {
+	case "j", "down":
+		if len(fields) == 0 {
+			m.pageDetail(1)
+			break
+		}
+		m.fieldCursor = min(m.fieldCursor+1, len(fields)-1)
+	case "k", "up":
+		if len(fields) == 0 {
+			m.pageDetail(-1)
+			break
+		}
+		m.fieldCursor = max(m.fieldCursor-1, 0)
+	case "g", "home":
+		m.fieldCursor = 0
+		m.detailOffset = 0
+	case "G", "end":
+		m.fieldCursor = max(len(fields)-1, 0)
+		m.pageDetail(math.MaxInt / 2)
+	case "ctrl+d":
+		m.pageDetail(max(m.detailHeight()/2, 1))
+	case "ctrl+u":
+		m.pageDetail(-max(m.detailHeight()/2, 1))
+	case "ctrl+f", "pgdown":
+		m.pageDetail(m.detailHeight())
+	case "ctrl+b", "pgup":
+		m.pageDetail(-m.detailHeight())
+	case "enter", "y":
+		if len(fields) == 0 {
+			m.statusMsg = "no fields to copy on this line"
+			break
+		}
+		kv := fields[min(m.fieldCursor, len(fields)-1)]
+		if msg.String() == "enter" && m.toggleFold(kv.Key) {
+			break
+		}
+		m.copyOut(kv.Value, fmt.Sprintf("%s of line %d", kv.Key, m.cursorLine()))
+	case "tab", "esc":
+		m.detailFocus = false
+	}
+	return m, nil
+}
+
+// fieldStarts returns the index among the detail body's lines of each field
+// the field cursor can select. In the fields and tree layouts each line is
+// a field; in pretty JSON the fields are the lines that neither open nor
+// close an object or array, in the same order. A line without fields, shown
+// as it is, has none.
+func (m *Model) fieldStarts(raw []byte, lines []string) []int {
+	if len(m.lineFields(raw)) == 0 {
+		return nil
+	}
+	each := m.fieldsLayout(raw) || m.treeLayout(raw)
+	fields := make([]int, 0, len(lines))
+	for i, line := range lines {
+		if each || isLeafLine(ansi.Strip(line)) {
+			fields = append(fields, i)
+		}
+	}
+	return fields
+}
+
+// detailHeight returns how many lines of the detail pane show the entry,
+// leaving the rest to the context preview as renderDetail does.
+func (m *Model) detailHeight() int {
+	height := m.viewport.Height
+	if m.stacked {
+		_, height = m.stackedHeights()
+	}
+	context := min(len(m.renderContext()), max(height-1, 0))
+	return max(height-context, 1)
+}
+
+// pageDetail scrolls the focused detail pane by delta lines, stopping with
+// the last line at the bottom of the pane. The detail keeps the selected
+// field in view, so a field cursor the scroll leaves behind moves to the
+// nearest field still shown.
+func (m *Model) pageDetail(delta int) {
+	raw, err := m.idx.GetLine(m.cursorLine())
+	if err != nil {
+		return
+	}
+	lines, fields := m.detailLines(raw, m.cursorLine())
+	height := m.detailHeight()
+	m.detailOffset = min(max(m.detailOffset+delta, 0), max(len(lines)-height, 0))
+
+	end := m.detailOffset + height
+	if m.fieldCursor < len(fields) && fields[m.fieldCursor] >= m.detailOffset && fields[m.fieldCursor] < end {
+		return
+	}
+	for i, f := range fields {
+		if f >= m.detailOffset && f < end {
+			m.fieldCursor = i
+			if delta > 0 {
+				break
+			}
+		}
+	}
+}
+
+// isLeafLine reports whether a line of indented JSON holds a value rather
+// than opening or closing an object or array. Empty objects and arrays
+// sit on one line and count as values.
+func isLeafLine(line string) bool {
+	line = strings.TrimSuffix(strings.TrimSpace(line), ",")
+	switch {
+	case line == "":
+		return false
+	case strings.HasSuffix(line, "{}"), strings.HasSuffix(line, "[]"):
+		return true
+	case strings.HasSuffix(line, "{"), strings.HasSuffix(line, "["):
+		return false
+	case line == "}", line == "]":
+		return false
+	}
+	return true
+}
+
+// separatorStyle returns the style of the lines dividing the panes,
+// drawn in the title color while the detail pane has the keys so it's
+// clear which pane they go to.
+func (m *Model) separatorStyle() lipgloss.Style {
+	if m.detailFocus {
+		return m.styles.Title
+	}
+	return m.styles.Separator
+}
+
+// detailFocusStatus describes the field cursor for the status line.
+func (m *Model) detailFocusStatus() string {
+	fields := m.cursorFields()
+	if len(fields) == 0 {
+		return " DETAIL: j/k scroll | C-d/C-u half page | C-f/C-b page | Tab/Esc done"
+	}
+	path := ""
+	if m.fieldCursor < len(fields) {
+		path = fields[m.fieldCursor].Key
+	}
+	if m.detailMode == detailTree {
+		return fmt.Sprintf(" FIELD %s: j/k select | C-d/C-u scroll | Enter fold/copy | y copy | Tab/Esc done", path)
+	}
+	return fmt.Sprintf(" FIELD %s: j/k select | C-d/C-u scroll | Enter/y copy value | Tab/Esc done", path)
+}
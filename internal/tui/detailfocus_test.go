@@ -0,0 +1,194 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// TestDetailFocus verifies Tab hands j/k to a field cursor that skips the
+// braces of nested objects, and Enter copies the selected value.
+func TestDetailFocus(t *testing.T) {
+	content := `{"level":"info","req":{"id":"r-42","tags":[]},"msg":"done"}
+{"level":"warn","msg":"other"}`
+	idx := createTestIndex(t, content)
+	defer closeIndex(idx)
+
+	m := New(idx)
+	m.Update(tea.WindowSizeMsg{Width: 140, Height: 20})
+	var copied string
+	m.copyText = func(s string) error {
+		copied = s
+		return nil
+	}
+
+	sendKeys(&m, "\tj")
+	if !m.detailFocus {
+		t.Fatal("expected Tab to focus the detail pane")
+	}
+	if m.cursorLine() != 1 {
+		t.Errorf("expected j to leave the table cursor on line 1, got %d", m.cursorLine())
+	}
+
+	raw, err := idx.GetLine(1)
+	if err != nil {
+		t.Fatalf("GetLine failed: %v", err)
+	}
+	lines := m.detailBody(raw, 1)
+	if got := strings.TrimSpace(ansi.Strip(lines[m.fieldStarts(raw, lines)[m.fieldCursor]])); got != `"id": "r-42",` {
+		t.Errorf("expected req.id selected, got %q", got)
+	}
+	if !strings.Contains(m.View(), "FIELD req.id") {
+		t.Error("expected the status line to name the selected field")
+	}
+
+	sendKeys(&m, "\n")
+	if copied != "r-42" || m.statusMsg != "copied req.id of line 1" {
+		t.Errorf("expected req.id copied, got %q (%q)", copied, m.statusMsg)
+	}
+
+	sendKeys(&m, "jjj")
+	if m.fieldCursor != 3 {
+		t.Errorf("expected the cursor to stop on the last field, got %d", m.fieldCursor)
+	}
+
+	sendKeys(&m, "\tj")
+	if m.detailFocus || m.cursorLine() != 2 {
+		t.Errorf("expected Tab to return j to the table, got focus %v on line %d", m.detailFocus, m.cursorLine())
+	}
+}
+
+// TestDetailFocusPaging verifies Ctrl+d/Ctrl+u and Ctrl+f/Ctrl+b scroll the
+// focused detail pane by half and whole pages, stop with the last line at
+// the bottom, and carry the field cursor along.
+func TestDetailFocusPaging(t *testing.T) {
+	var b strings.Builder
+	b.WriteString(`{"msg":"long"`)
+	for i := range 60 {
+		fmt.Fprintf(&b, `,"k%02d":%d`, i, i)
+	}
+	b.WriteString("}")
+	idx := createTestIndex(t, b.String())
+	defer closeIndex(idx)
+
+	m := New(idx)
+	m.Update(tea.WindowSizeMsg{Width: 140, Height: 24})
+	sendKeys(&m, "\t")
+	m.View()
+
+	raw, err := idx.GetLine(1)
+	if err != nil {
+		t.Fatalf("GetLine failed: %v", err)
+	}
+	lines, fields := m.detailLines(raw, 1)
+	height := m.detailHeight()
+	half := height / 2
+	last := len(lines) - height
+
+	steps := []struct {
+		msg  tea.KeyMsg
+		want int
+	}{
+		{tea.KeyMsg{Type: tea.KeyCtrlD}, half},
+		{tea.KeyMsg{Type: tea.KeyCtrlF}, half + height},
+		{tea.KeyMsg{Type: tea.KeyCtrlF}, last},
+		{tea.KeyMsg{Type: tea.KeyCtrlD}, last},
+		{tea.KeyMsg{Type: tea.KeyCtrlU}, last - half},
+		{tea.KeyMsg{Type: tea.KeyCtrlB}, last - half - height},
+		{tea.KeyMsg{Type: tea.KeyCtrlB}, 0},
+	}
+	for i, s := range steps {
+		m.Update(s.msg)
+		if m.detailOffset != s.want {
+			t.Fatalf("step %d (%s): expected offset %d, got %d", i, s.msg, s.want, m.detailOffset)
+		}
+		if f := fields[m.fieldCursor]; f < m.detailOffset || f >= m.detailOffset+height {
+			t.Errorf("step %d (%s): field cursor on line %d, outside %d-%d", i, s.msg, f, m.detailOffset, m.detailOffset+height-1)
+		}
+		m.View()
+		if m.detailOffset != s.want {
+			t.Errorf("step %d (%s): redraw moved the offset to %d", i, s.msg, m.detailOffset)
+		}
+	}
+	if !m.detailFocus {
+		t.Error("expected paging to keep the detail focused")
+	}
+}
+
+// TestDetailFocusScroll verifies Tab focuses the detail of a line without
+// fields too, where j/k scroll it a line at a time, and that the divider
+// between the panes marks the focus.
+func TestDetailFocusScroll(t *testing.T) {
+	// Plain text, wrapped to many lines
+	line := strings.Repeat("not json ", 400)
+	idx := createTestIndex(t, line)
+	defer closeIndex(idx)
+
+	m := New(idx, WithWrapDetail(true))
+	m.Update(tea.WindowSizeMsg{Width: 140, Height: 24})
+	m.View()
+	if got, want := m.separatorStyle().GetForeground(), m.styles.Separator.GetForeground(); got != want {
+		t.Errorf("expected the plain separator color unfocused, got %v", got)
+	}
+
+	sendKeys(&m, "\tjj")
+	if !m.detailFocus {
+		t.Fatal("expected Tab to focus a line without fields")
+	}
+	if m.detailOffset != 2 || m.cursorLine() != 1 {
+		t.Errorf("expected j to scroll the detail to 2 on line 1, got %d on line %d", m.detailOffset, m.cursorLine())
+	}
+	if !strings.Contains(m.View(), "DETAIL: j/k scroll") {
+		t.Error("expected the status line to say the detail scrolls")
+	}
+	if got, want := m.separatorStyle().GetForeground(), m.styles.Title.GetForeground(); got != want {
+		t.Errorf("expected the separator in the title color while focused, got %v", got)
+	}
+
+	sendKeys(&m, "k")
+	if m.detailOffset != 1 {
+		t.Errorf("expected k to scroll back to 1, got %d", m.detailOffset)
+	}
+	sendKeys(&m, "G")
+	lines, _ := m.detailLines([]byte(line), 1)
+	if last := len(lines) - m.detailHeight(); m.detailOffset != last {
+		t.Errorf("expected G to scroll to the end at %d, got %d", last, m.detailOffset)
+	}
+	sendKeys(&m, "g")
+	if m.detailOffset != 0 {
+		t.Errorf("expected g to scroll to the top, got %d", m.detailOffset)
+	}
+
+	sendKeys(&m, "y")
+	if m.statusMsg != "no fields to copy on this line" {
+		t.Errorf("expected y to say there's nothing to copy, got %q", m.statusMsg)
+	}
+	sendKeys(&m, "\t")
+	if m.detailFocus {
+		t.Error("expected Tab to return the keys to the table")
+	}
+}
+
+// TestIsLeafLine verifies which lines of indented JSON hold values.
+func TestIsLeafLine(t *testing.T) {
+	tests := map[string]bool{
+		`{`:                 false,
+		`  "req": {`:        false,
+		`  "tags": [`:       false,
+		`  },`:              false,
+		`]`:                 false,
+		`  "id": "r-42",`:   true,
+		`  "tags": [],`:     true,
+		`  "meta": {}`:      true,
+		`    3,`:            true,
+		`  "msg": "open {"`: true,
+	}
+	for line, want := range tests {
+		if got := isLeafLine(line); got != want {
+			t.Errorf("isLeafLine(%q) = %v, want %v", line, got, want)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package tui
+
+import (
+	"container/list"
+
+	"github.com/lbe/jsonlogviewer/internal/parser"
+)
+
+// DefaultEntryCacheSize is how many lines the entry cache keeps unless
+// WithEntryCache changes it: several screens of rows, so scrolling back
+// and forth redraws from the cache.
+const DefaultEntryCacheSize = 1024
+
+// entryCache is a least-recently-used cache of parsed lines keyed by file
+// line number, so redrawing rows while scrolling doesn't parse and
+// pretty-print the same lines again. A nil cache is disabled: lookups
+// miss and stores are dropped.
+type entryCache struct {
+	size  int
+	items map[int]*list.Element
+	// order holds the *cachedLine values, most recently used first.
+	order *list.List
+}
+
+// cachedLine is what the cache keeps for one line.
+type cachedLine struct {
+	line   int
+	parsed bool
+	entry  parser.LogEntry
+	err    error
+	// pretty is the line's pretty-printed JSON, set once hasPretty.
+	pretty    string
+	prettyErr error
+	hasPretty bool
+}
+
+// newEntryCache returns a cache holding up to size lines, or nil (a
+// disabled cache) when size is 0 or less.
+func newEntryCache(size int) *entryCache {
+	if size <= 0 {
+		return nil
+	}
+	return &entryCache{
+		size:  size,
+		items: make(map[int]*list.Element, size),
+		order: list.New(),
+	}
+}
+
+// get returns the cached line n, marking it most recently used.
+func (c *entryCache) get(n int) (*cachedLine, bool) {
+	if c == nil {
+		return nil, false
+	}
+	elem, ok := c.items[n]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(elem)
+	return elem.Value.(*cachedLine), true
+}
+
+// slot returns the cached line n for filling in, adding an empty one and
+// evicting the least recently used line when it isn't cached. It returns
+// a throwaway value on a disabled cache.
+func (c *entryCache) slot(n int) *cachedLine {
+	if c == nil {
+		return &cachedLine{line: n}
+	}
+	if item, ok := c.get(n); ok {
+		return item
+	}
+	if c.order.Len() >= c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*cachedLine).line)
+	}
+	item := &cachedLine{line: n}
+	c.items[n] = c.order.PushFront(item)
+	return item
+}
+
+// clear drops every cached line.
+func (c *entryCache) clear() {
+	if c == nil {
+		return
+	}
+	clear(c.items)
+	c.order.Init()
+}
+
+// len returns how many lines are cached.
+func (c *entryCache) len() int {
+	if c == nil {
+		return 0
+	}
+	return c.order.Len()
+}
+
+// parseLine parses raw, file line n, into entry like parser.ParseInto,
+// reusing the cached result when the line was parsed before.
+func (m *Model) parseLine(raw []byte, n int, entry *parser.LogEntry) error {
+	if item, ok := m.entries.get(n); ok && item.parsed {
+		*entry = item.entry
+		return item.err
+	}
+	err := m.parser.ParseInto(raw, n, entry)
+	item := m.entries.slot(n)
+	item.parsed, item.entry, item.err = true, *entry, err
+	return err
+}
+
+// formatLine pretty-prints raw, file line n, like parser.FormatPretty,
+// reusing the cached result. The first failure of each line is logged to
+// the model's logger.
+func (m *Model) formatLine(raw []byte, n int) (string, error) {
+	if item, ok := m.entries.get(n); ok && item.hasPretty {
+		return item.pretty, item.prettyErr
+	}
+	pretty, err := m.parser.FormatPretty(raw)
+	if err != nil && m.logger != nil && !m.formatLogged[n] {
+		if m.formatLogged == nil {
+			m.formatLogged = make(map[int]bool)
+		}
+		m.formatLogged[n] = true
+		m.logger.Debug("format failed", "row", n, "error", err, "line", parser.LogText(raw))
+	}
+	item := m.entries.slot(n)
+	item.pretty, item.prettyErr, item.hasPretty = pretty, err, true
+	return pretty, err
+}
+
+// invalidateEntries drops the cached lines, for when the index or the
+// parser settings change what a line parses to.
+func (m *Model) invalidateEntries() {
+	m.entries.clear()
+}
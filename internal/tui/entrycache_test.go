@@ -0,0 +1,98 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lbe/jsonlogviewer/internal/index"
+	"github.com/lbe/jsonlogviewer/internal/parser"
+)
+
+// TestEntryCacheEviction verifies the cache keeps the most recently used
+// lines up to its size, and that a disabled cache stores nothing.
+func TestEntryCacheEviction(t *testing.T) {
+	c := newEntryCache(2)
+	c.slot(1)
+	c.slot(2)
+	c.get(1)
+	c.slot(3) // evicts 2, the least recently used
+	if _, ok := c.get(2); ok {
+		t.Error("expected line 2 evicted")
+	}
+	for _, n := range []int{1, 3} {
+		if _, ok := c.get(n); !ok {
+			t.Errorf("expected line %d cached", n)
+		}
+	}
+	if c.len() != 2 {
+		t.Errorf("expected 2 cached lines, got %d", c.len())
+	}
+	c.clear()
+	if c.len() != 0 {
+		t.Errorf("expected clear to empty the cache, got %d lines", c.len())
+	}
+
+	var disabled *entryCache
+	if disabled = newEntryCache(0); disabled != nil {
+		t.Fatal("expected size 0 to disable the cache")
+	}
+	disabled.slot(1).hasPretty = true
+	if _, ok := disabled.get(1); ok || disabled.len() != 0 {
+		t.Error("expected a disabled cache to keep nothing")
+	}
+}
+
+// TestEntryCacheParse verifies cached entries keep their whole message
+// whatever the column width, and are dropped when follow mode picks up a
+// completed last line.
+func TestEntryCacheParse(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	if err := os.WriteFile(path, []byte(`{"level":"info","msg":"first message"}`+"\n"+`{"level":"error","msg":"c`), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	idx, err := index.Open(path)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer closeIndex(idx)
+
+	m := New(idx)
+	m.Update(tea.WindowSizeMsg{Width: 160, Height: 20})
+	m.View()
+	if m.entries.len() != 2 {
+		t.Fatalf("expected both rows cached after a render, got %d", m.entries.len())
+	}
+
+	var entry parser.LogEntry
+	raw, _ := idx.GetLine(1)
+	m.adjustMsgLen(-100)
+	if err := m.parseLine(raw, 1, &entry); err != nil || entry.Msg != "first message" {
+		t.Errorf("expected the cached entry's whole message, got %q (%v)", entry.Msg, err)
+	}
+
+	raw, _ = idx.GetLine(2)
+	if err := m.parseLine(raw, 2, &entry); err == nil && entry.Msg == "cut" {
+		t.Fatalf("expected the partial last line to lack its message, got %q", entry.Msg)
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatalf("failed to open file: %v", err)
+	}
+	if _, err := f.WriteString(`ut"}` + "\n" + `{"level":"info","msg":"third"}` + "\n"); err != nil {
+		t.Fatalf("failed to append: %v", err)
+	}
+	_ = f.Close()
+
+	m.SetFollow(true)
+	m.Update(followTickMsg{})
+	if m.entries.len() != 0 {
+		t.Errorf("expected follow to drop the cache, got %d lines", m.entries.len())
+	}
+	raw, _ = idx.GetLine(2)
+	if err := m.parseLine(raw, 2, &entry); err != nil || entry.Msg != "cut" {
+		t.Errorf("expected the completed line parsed, got %q (%v)", entry.Msg, err)
+	}
+}
@@ -0,0 +1,80 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+)
+
+// exportLines writes the given file lines to path, one raw line per line,
+// and reports the outcome in the status line. A nil lines writes the whole
+// file.
+func (m *Model) exportLines(path string, lines []int) {
+	f, err := os.Create(path)
+	if err != nil {
+		m.statusMsg = fmt.Sprintf("export failed: %v", err)
+		return
+	}
+
+	if err := m.idx.WriteLines(f, lines); err != nil {
+		_ = f.Close()
+		m.statusMsg = fmt.Sprintf("export failed: %v", err)
+		return
+	}
+	if err := f.Close(); err != nil {
+		m.statusMsg = fmt.Sprintf("export failed: %v", err)
+		return
+	}
+
+	count := len(lines)
+	if lines == nil {
+		count = m.idx.LineCount()
+	}
+	m.statusMsg = fmt.Sprintf("wrote %d lines to %s", count, path)
+}
+
+// shownLines returns the file lines shown in the table, or nil when every
+// line is shown.
+func (m *Model) shownLines() []int {
+	return m.visible
+}
+
+// matchingLines returns the lines matched by the current search, or shown
+// by the active filters when there's no search. ok is false when neither
+// is active.
+func (m *Model) matchingLines() (lines []int, ok bool) {
+	if m.searchQuery != "" {
+		lines = make([]int, 0, len(m.searchMatches))
+		for _, n := range m.searchMatches {
+			if m.isShown(n) {
+				lines = append(lines, n)
+			}
+		}
+		return lines, true
+	}
+	if m.filtering() {
+		return m.visible, true
+	}
+	return nil, false
+}
+
+// openExport opens the prompt for the file to write matching lines to.
+func (m *Model) openExport() {
+	if _, ok := m.matchingLines(); !ok {
+		m.statusMsg = "no search or filter active (use :w to write every line)"
+		return
+	}
+	m.openPrompt(promptExport)
+}
+
+// exportMatches writes the matching lines to path, asking before
+// overwriting an existing file.
+func (m *Model) exportMatches(path string) {
+	if path == "" {
+		return
+	}
+	lines, ok := m.matchingLines()
+	if !ok {
+		return
+	}
+	m.confirmOverwrite(path, false, func() { m.exportLines(path, lines) })
+}
@@ -0,0 +1,65 @@
+package tui
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/lbe/jsonlogviewer/internal/parser"
+)
+
+// fieldSearchPattern matches a search aimed at one field: the gjson path
+// after "field:", then "=" for an exact value or "~" for a regex.
+var fieldSearchPattern = regexp.MustCompile(`^field:([A-Za-z0-9_.@-]+)(=|~)(.*)$`)
+
+// searchPredicate compiles a search query. "field:path=value" matches
+// lines whose field at the gjson path is exactly value, and
+// "field:path~regex", or "path=~regex" as at the & prompt, those whose
+// field matches the regular expression, so the text inside unrelated
+// fields doesn't match. Any other query matches lines containing it,
+// ignoring case.
+func searchPredicate(query string) (linePredicate, error) {
+	if sub := regexFieldPattern.FindStringSubmatch(query); sub != nil {
+		return fieldRegexPredicate(sub[1], sub[2])
+	}
+	sub := fieldSearchPattern.FindStringSubmatch(query)
+	if sub == nil {
+		return substringPredicate(query), nil
+	}
+	field, op, operand := sub[1], sub[2], sub[3]
+	if op == "=" {
+		return func(raw []byte) bool {
+			return parser.ExtractField(raw, field) == operand
+		}, nil
+	}
+	return fieldRegexPredicate(field, operand)
+}
+
+// fieldRegexPredicate returns a predicate matching lines whose field at
+// the gjson path matches expr.
+func fieldRegexPredicate(field, expr string) (linePredicate, error) {
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("%s~: %w", field, err)
+	}
+	return func(raw []byte) bool {
+		return re.MatchString(parser.ExtractField(raw, field))
+	}, nil
+}
+
+// searchMark returns the text the detail pane marks for query: the value
+// of a field:path=value search, nothing for a field regex, and otherwise
+// the query itself.
+func searchMark(query string) string {
+	if regexFieldPattern.MatchString(query) {
+		return ""
+	}
+	sub := fieldSearchPattern.FindStringSubmatch(query)
+	switch {
+	case sub == nil:
+		return query
+	case sub[2] == "=":
+		return sub[3]
+	default:
+		return ""
+	}
+}
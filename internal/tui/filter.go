@@ -0,0 +1,271 @@
+package tui
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/lbe/jsonlogviewer/internal/parser"
+)
+
+// linePredicate reports whether a raw log line matches a filter.
+type linePredicate func(raw []byte) bool
+
+// substringPredicate returns a predicate matching lines that contain query,
+// ignoring case.
+func substringPredicate(query string) linePredicate {
+	needle := bytes.ToLower([]byte(query))
+	return func(raw []byte) bool {
+		return bytes.Contains(bytes.ToLower(raw), needle)
+	}
+}
+
+// levelNames maps a parser.LevelRank to the name shown for it.
+var levelNames = []string{"", "TRACE", "DEBUG", "INFO", "WARN", "ERROR", "FATAL"}
+
+// maxLevelRank is the highest minimum level that can be set.
+const maxLevelRank = 6
+
+// levelToggles names the levels f1 through f5 show and hide. TRACE goes
+// with DEBUG and PANIC with FATAL.
+var levelToggles = [...]string{"DEBUG", "INFO", "WARN", "ERROR", "FATAL"}
+
+// levelToggle returns the index into levelToggles for a parser.LevelRank,
+// or -1 for an unknown level, which no toggle hides.
+func levelToggle(rank int) int {
+	if rank == 0 {
+		return -1
+	}
+	return max(rank-2, 0)
+}
+
+// The table shows rows, not lines: with no filter active row n is line n,
+// otherwise the rows are the lines listed in visible. A sort puts those
+// same lines in another order, listed in sorted.rows. The viewport works
+// in rows, so anything naming a file line goes through lineAt and posOf.
+
+// filtering reports whether any filter is hiding lines.
+func (m *Model) filtering() bool {
+	return m.levelFiltering() || m.timeFiltering() || m.regexActive()
+}
+
+// levelFiltering reports whether a level filter is hiding lines.
+func (m *Model) levelFiltering() bool {
+	if m.minLevel > 0 {
+		return true
+	}
+	for _, hidden := range m.hiddenLevels {
+		if hidden {
+			return true
+		}
+	}
+	return false
+}
+
+// rowCount returns the number of rows in the table.
+func (m *Model) rowCount() int {
+	if m.sorted != nil {
+		return len(m.sorted.rows)
+	}
+	if m.visible == nil {
+		return m.idx.LineCount()
+	}
+	return len(m.visible)
+}
+
+// lineAt returns the file line shown at 1-indexed row pos, or 0 if there
+// is no such row.
+func (m *Model) lineAt(pos int) int {
+	if pos < 1 || pos > m.rowCount() {
+		return 0
+	}
+	if m.sorted != nil {
+		return m.sorted.rows[pos-1]
+	}
+	if m.visible == nil {
+		return pos
+	}
+	return m.visible[pos-1]
+}
+
+// cursorLine returns the file line under the cursor, or 0 when the table
+// is empty.
+func (m *Model) cursorLine() int {
+	return m.lineAt(m.viewport.Cursor)
+}
+
+// posOf returns the row showing file line n. A hidden line maps to the next
+// row after it, or the last row if nothing follows; when sorted, to the
+// row of the next line shown in file order.
+func (m *Model) posOf(n int) int {
+	if m.sorted != nil {
+		pos := m.sorted.pos
+		for i := max(n, 1); i < len(pos); i++ {
+			if pos[i] > 0 {
+				return pos[i]
+			}
+		}
+		return len(m.sorted.rows)
+	}
+	if m.visible == nil {
+		return n
+	}
+	lo, hi := 0, len(m.visible)
+	for lo < hi {
+		mid := (lo + hi) / 2
+		if m.visible[mid] < n {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+	if lo == len(m.visible) {
+		return len(m.visible)
+	}
+	return lo + 1
+}
+
+// isShown reports whether file line n passes the active filters.
+func (m *Model) isShown(n int) bool {
+	return m.visible == nil || m.lineAt(m.posOf(n)) == n
+}
+
+// gotoLine moves the cursor to file line n, or the nearest row after it
+// when n is filtered out.
+func (m *Model) gotoLine(n int) {
+	m.viewport.Goto(m.posOf(n))
+}
+
+// centerLine is gotoLine with the row scrolled to the middle of the table,
+// so the lines leading up to it are in view.
+func (m *Model) centerLine(n int) {
+	m.viewport.Center(m.posOf(n))
+}
+
+// passesFilters reports whether a parsed line survives every active filter.
+func (m *Model) passesFilters(entry *parser.LogEntry) bool {
+	if m.regexActive() && !m.regex.match(entry.Raw) {
+		return false
+	}
+	return m.passesLevelFilters(entry) && m.inTimeRange(entry)
+}
+
+// passesLevelFilters reports whether a parsed line survives the level
+// filters.
+func (m *Model) passesLevelFilters(entry *parser.LogEntry) bool {
+	rank := parser.LevelRank(entry.CanonicalLevel)
+	if rank < m.minLevel {
+		return false
+	}
+	if t := levelToggle(rank); t >= 0 && m.hiddenLevels[t] {
+		return false
+	}
+	return true
+}
+
+// applyFilters rebuilds the visible rows from the active filters, keeping
+// the cursor on the same line, or the next one shown if it was hidden. With
+// a regex filter only its matches are checked against the level and time
+// filters.
+func (m *Model) applyFilters() {
+	line := m.cursorLine()
+	switch {
+	case !m.filtering():
+		m.visible = nil
+	case !m.levelFiltering() && !m.timeFiltering():
+		m.visible = slices.Clone(m.regex.matches)
+	default:
+		visible := make([]int, 0)
+		var entry parser.LogEntry
+		keep := func(n int) {
+			raw, err := m.idx.GetLine(n)
+			if err == nil && m.parser.ParseInto(raw, n, &entry) == nil && m.passesLevelFilters(&entry) && m.inTimeRange(&entry) {
+				visible = append(visible, n)
+			}
+		}
+		if m.regexActive() {
+			for _, n := range m.regex.matches {
+				keep(n)
+			}
+		} else {
+			for n := 1; n <= m.idx.LineCount(); n++ {
+				keep(n)
+			}
+		}
+		m.visible = visible
+	}
+	if m.sorted != nil {
+		m.resort()
+	}
+	m.viewport.SetTotalLines(m.rowCount())
+	if line > 0 {
+		m.gotoLine(line)
+	}
+}
+
+// adjustMinLevel raises (delta > 0) or lowers the minimum level shown,
+// clamped between showing everything and showing only FATAL.
+func (m *Model) adjustMinLevel(delta int) {
+	level := min(max(m.minLevel+delta, 0), maxLevelRank)
+	if level == m.minLevel {
+		return
+	}
+	m.minLevel = level
+	m.applyFilters()
+	if !m.filtering() {
+		m.statusMsg = "showing all levels"
+	} else {
+		m.statusMsg = fmt.Sprintf("showing %s: %d of %d lines", m.levelState(), m.rowCount(), m.idx.LineCount())
+	}
+}
+
+// toggleLevel shows or hides the level at index t of levelToggles.
+func (m *Model) toggleLevel(t int) {
+	m.hiddenLevels[t] = !m.hiddenLevels[t]
+	m.applyFilters()
+	verb := "showing"
+	if m.hiddenLevels[t] {
+		verb = "hiding"
+	}
+	m.statusMsg = fmt.Sprintf("%s %s: %d of %d lines", verb, levelToggles[t], m.rowCount(), m.idx.LineCount())
+}
+
+// clearFilters removes every level, time, and regex filter so all lines
+// show again.
+func (m *Model) clearFilters() {
+	m.minLevel = 0
+	m.hiddenLevels = [len(levelToggles)]bool{}
+	m.clearTimeRange()
+	m.clearRegexFilter()
+	m.applyFilters()
+}
+
+// levelState describes the level filters for the status line, e.g.
+// "≥WARN" or "≥INFO -WARN -ERROR". Empty when no level filter is active.
+func (m *Model) levelState() string {
+	var parts []string
+	if m.minLevel > 0 {
+		parts = append(parts, "≥"+levelNames[m.minLevel])
+	}
+	for t, hidden := range m.hiddenLevels {
+		if hidden {
+			parts = append(parts, "-"+levelToggles[t])
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// lineCountState describes the line count for the app header, showing how
+// many lines pass the filters when any are active and marking a count that
+// is still growing while a lazy index scans the file.
+func (m *Model) lineCountState() string {
+	state := fmt.Sprintf("%d lines", m.idx.LineCount())
+	if m.filtering() {
+		state = fmt.Sprintf("%d/%d lines", m.rowCount(), m.idx.LineCount())
+	}
+	if m.indexing {
+		state += " (indexing…)"
+	}
+	return state
+}
@@ -0,0 +1,139 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+const levelContent = `{"level":"debug","msg":"a"}
+{"level":"info","msg":"b"}
+{"level":"warn","msg":"c"}
+{"level":"info","msg":"d"}
+{"level":"error","msg":"e"}
+{"level":"fatal","msg":"f"}
+{"msg":"no level"}`
+
+// TestMinLevelFilter verifies +/- step the minimum level, hiding lower
+// levels, and clamp at both ends.
+func TestMinLevelFilter(t *testing.T) {
+	idx := createTestIndex(t, levelContent)
+	defer closeIndex(idx)
+
+	m := New(idx)
+	m.width = 120
+	m.height = 30
+
+	steps := []struct {
+		keys      string
+		wantLevel string
+		wantRows  int
+	}{
+		{"+", "≥TRACE", 6},
+		{"++", "≥INFO", 5},
+		{"+", "≥WARN", 3},
+		{"++", "≥FATAL", 1},
+		{"+", "≥FATAL", 1},
+		{"-", "≥ERROR", 2},
+		{"----", "≥TRACE", 6},
+		{"-", "", 7},
+		{"-", "", 7},
+	}
+
+	for _, s := range steps {
+		sendKeys(&m, s.keys)
+		if m.rowCount() != s.wantRows {
+			t.Errorf("after %q to %s: expected %d rows, got %d", s.keys, s.wantLevel, s.wantRows, m.rowCount())
+		}
+		if m.viewport.TotalLines != s.wantRows {
+			t.Errorf("after %q: expected viewport over %d rows, got %d", s.keys, s.wantRows, m.viewport.TotalLines)
+		}
+		if got := m.levelState(); got != s.wantLevel {
+			t.Errorf("after %q: expected level state %q, got %q", s.keys, s.wantLevel, got)
+		}
+		m.statusMsg = ""
+		if got := strings.Contains(m.View(), "filter:"); got != (s.wantLevel != "") {
+			t.Errorf("after %q: expected a level filter in the status line: %v", s.keys, !got)
+		}
+	}
+}
+
+// TestMinLevelKeepsCursorLine verifies the cursor stays on its line when
+// it passes the filter, and moves to the next shown line when it doesn't.
+func TestMinLevelKeepsCursorLine(t *testing.T) {
+	idx := createTestIndex(t, levelContent)
+	defer closeIndex(idx)
+
+	m := New(idx)
+	m.width = 120
+	m.height = 30
+
+	m.viewport.Goto(5)
+	sendKeys(&m, "++++")
+	if m.cursorLine() != 5 {
+		t.Errorf("expected cursor to stay on line 5, got %d", m.cursorLine())
+	}
+
+	m.minLevel = 0
+	m.applyFilters()
+	m.viewport.Goto(4)
+	sendKeys(&m, "++++")
+	if m.cursorLine() != 5 {
+		t.Errorf("expected cursor to move from hidden line 4 to line 5, got %d", m.cursorLine())
+	}
+
+	// Line numbers typed for G still name file lines
+	sendKeys(&m, "6G")
+	if m.cursorLine() != 6 {
+		t.Errorf("expected 6G to reach line 6, got %d", m.cursorLine())
+	}
+
+	sendKeys(&m, "-----")
+	if m.visible != nil || m.cursorLine() != 6 {
+		t.Errorf("expected every line shown with the cursor on line 6, got line %d", m.cursorLine())
+	}
+}
+
+// TestLevelToggles verifies f1-f5 hide and show individual levels, combine
+// with the minimum level, and f0 clears every level filter.
+func TestLevelToggles(t *testing.T) {
+	idx := createTestIndex(t, levelContent)
+	defer closeIndex(idx)
+
+	m := New(idx)
+	m.width = 120
+	m.height = 30
+
+	steps := []struct {
+		keys      string
+		wantRows  []int
+		wantState string
+	}{
+		{"f1", []int{2, 3, 4, 5, 6, 7}, "-DEBUG"},
+		{"f2", []int{3, 5, 6, 7}, "-DEBUG -INFO"},
+		{"f1", []int{1, 3, 5, 6, 7}, "-INFO"},
+		{"++++", []int{3, 5, 6}, "≥WARN -INFO"},
+		{"f4", []int{3, 6}, "≥WARN -INFO -ERROR"},
+		{"f0", []int{1, 2, 3, 4, 5, 6, 7}, ""},
+	}
+
+	for _, s := range steps {
+		sendKeys(&m, s.keys)
+		var got []int
+		for pos := 1; pos <= m.rowCount(); pos++ {
+			got = append(got, m.lineAt(pos))
+		}
+		if fmt.Sprint(got) != fmt.Sprint(s.wantRows) {
+			t.Errorf("after %q: expected rows %v, got %v", s.keys, s.wantRows, got)
+		}
+		if m.levelState() != s.wantState {
+			t.Errorf("after %q: expected state %q, got %q", s.keys, s.wantState, m.levelState())
+		}
+	}
+
+	// gf still opens the source file rather than starting a toggle
+	sendKeys(&m, "gf")
+	if m.pendingPrefix != "" {
+		t.Errorf("expected gf not to leave a pending prefix, got %q", m.pendingPrefix)
+	}
+}
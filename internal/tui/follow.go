@@ -0,0 +1,158 @@
+package tui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lbe/jsonlogviewer/internal/parser"
+)
+
+const (
+	// followMinInterval is the refresh interval while lines are arriving.
+	followMinInterval = 500 * time.Millisecond
+	// followMaxInterval caps the refresh interval during long idle periods.
+	followMaxInterval = 8 * time.Second
+	// followIdleAfter is how long the file must go without new lines
+	// before the refresh interval starts to back off.
+	followIdleAfter = 2 * time.Second
+)
+
+// followBackoff paces follow-mode refreshes. While new lines keep arriving
+// it refreshes at its minimum interval; once the file has been idle for
+// followIdleAfter, each empty refresh doubles the interval up to its
+// maximum, and the first append drops it back to the minimum. This keeps
+// an idle follow session from waking up several times a second.
+type followBackoff struct {
+	min, max time.Duration
+	now      func() time.Time
+
+	lastAppend time.Time
+	interval   time.Duration
+}
+
+// newFollowBackoff creates a backoff between min and max, treating now as
+// the last append so the fast interval is used at first.
+func newFollowBackoff(min, max time.Duration) *followBackoff {
+	b := &followBackoff{
+		min:      min,
+		max:      max,
+		now:      time.Now,
+		interval: min,
+	}
+	b.lastAppend = b.now()
+	return b
+}
+
+// next records the outcome of a refresh, whether it found new lines, and
+// returns how long to wait before the next one.
+func (b *followBackoff) next(appended bool) time.Duration {
+	now := b.now()
+	if appended {
+		b.lastAppend = now
+		b.interval = b.min
+		return b.interval
+	}
+	if now.Sub(b.lastAppend) >= followIdleAfter {
+		b.interval = min(b.interval*2, b.max)
+	}
+	return b.interval
+}
+
+// followTickMsg asks the model to check the file for appended lines.
+type followTickMsg struct{}
+
+// followTick schedules the next follow-mode refresh after d.
+func followTick(d time.Duration) tea.Cmd {
+	return tea.Tick(d, func(time.Time) tea.Msg {
+		return followTickMsg{}
+	})
+}
+
+// SetFollow turns follow mode on or off. While on, the model re-reads the
+// file as it grows, like tail -f; Init starts the refresh loop, so this
+// must be called before the program runs.
+func (m *Model) SetFollow(on bool) {
+	m.follow = on
+	m.followBackoff = newFollowBackoff(followMinInterval, followMaxInterval)
+}
+
+// refreshFollow indexes lines appended since the last refresh and schedules
+// the next one. A cursor on the last row stays on the last row, so the
+// newest entry remains selected as lines arrive.
+func (m *Model) refreshFollow() tea.Cmd {
+	atBottom := m.viewport.Cursor >= m.rowCount()
+	last, size := m.idx.LineCount(), m.idx.Size()
+
+	_, err := m.idx.Refresh()
+	if err != nil {
+		m.statusMsg = fmt.Sprintf("follow: %v", err)
+	}
+	// A write that only completes the last line adds no lines but still
+	// changes that line, so any growth counts
+	changed := m.idx.Size() != size
+	if changed {
+		// The old last line may have been completed by this write, so
+		// it's checked again along with the new ones
+		m.linesAppended(last)
+		if atBottom {
+			m.viewport.GotoBottom()
+		}
+	}
+	return tea.Batch(followTick(m.followBackoff.next(changed)), m.histogramCmd())
+}
+
+// linesAppended updates the cached per-line state for lines from onward
+// after the index grew: filter rows and search matches are extended
+// rather than rebuilt, and whole-file summaries are recomputed on demand.
+func (m *Model) linesAppended(from int) {
+	if m.regexActive() {
+		m.regex.extend(m.idx, from)
+	}
+	if m.highlight != nil {
+		m.highlight.extend(m.idx, from)
+	}
+
+	if m.visible != nil {
+		m.visible = dropFrom(m.visible, from)
+		var entry parser.LogEntry
+		for n := from; n <= m.idx.LineCount(); n++ {
+			raw, err := m.idx.GetLine(n)
+			if err == nil && m.parser.ParseInto(raw, n, &entry) == nil && m.passesFilters(&entry) {
+				m.visible = append(m.visible, n)
+			}
+		}
+	}
+
+	if m.searchQuery != "" {
+		m.searchMatches = dropFrom(m.searchMatches, from)
+		// The query compiled when it was searched for
+		match, _ := searchPredicate(m.searchQuery)
+		for n := from; n <= m.idx.LineCount(); n++ {
+			if raw, err := m.idx.GetLine(n); err == nil && match(raw) {
+				m.searchMatches = append(m.searchMatches, n)
+			}
+		}
+	}
+
+	if m.sorted != nil {
+		m.extendSort(from)
+	}
+
+	m.extendParseErrors(from)
+	m.invalidateHistogram()
+	m.invalidateEntries()
+	m.tocCache = nil
+	m.schemaLine = 0
+	m.viewport.SetTotalLines(m.rowCount())
+}
+
+// dropFrom removes the trailing line numbers of a sorted list that are
+// from or later.
+func dropFrom(lines []int, from int) []int {
+	i := len(lines)
+	for i > 0 && lines[i-1] >= from {
+		i--
+	}
+	return lines[:i]
+}
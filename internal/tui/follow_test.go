@@ -0,0 +1,147 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lbe/jsonlogviewer/internal/index"
+	"github.com/lbe/jsonlogviewer/internal/parser"
+)
+
+// TestFollowBackoff verifies the refresh interval stays fast while lines
+// arrive, doubles up to the cap once the file is idle, and resets on append.
+func TestFollowBackoff(t *testing.T) {
+	clock := time.Unix(0, 0)
+	b := newFollowBackoff(time.Second, 8*time.Second)
+	b.now = func() time.Time { return clock }
+	b.lastAppend = clock
+
+	steps := []struct {
+		advance  time.Duration
+		appended bool
+		want     time.Duration
+	}{
+		{time.Second, false, time.Second},     // idle 1s: not idle long enough
+		{time.Second, false, 2 * time.Second}, // idle 2s: backoff starts
+		{2 * time.Second, false, 4 * time.Second},
+		{4 * time.Second, false, 8 * time.Second},
+		{8 * time.Second, false, 8 * time.Second}, // capped
+		{8 * time.Second, true, time.Second},      // append resets
+		{time.Second, false, time.Second},
+	}
+
+	for i, s := range steps {
+		clock = clock.Add(s.advance)
+		if got := b.next(s.appended); got != s.want {
+			t.Errorf("step %d: expected interval %v, got %v", i, s.want, got)
+		}
+	}
+}
+
+// TestFollowRefresh verifies a follow tick indexes appended lines, keeps a
+// cursor on the last row at the bottom, and leaves it alone elsewhere.
+func TestFollowRefresh(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	if err := os.WriteFile(path, []byte(`{"level":"info","msg":"a"}`+"\n"+`{"level":"info","msg":"b"}`+"\n"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	idx, err := index.Open(path)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer closeIndex(idx)
+
+	m := New(idx)
+	m.SetFollow(true)
+	if m.Init() == nil {
+		t.Fatal("expected Init to start the follow tick")
+	}
+
+	appendLog := func(lines string) {
+		f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+		if err != nil {
+			t.Fatalf("failed to open file: %v", err)
+		}
+		defer func() { _ = f.Close() }()
+		if _, err := f.WriteString(lines); err != nil {
+			t.Fatalf("failed to append: %v", err)
+		}
+	}
+
+	// Cursor on the last line follows the new bottom
+	m.viewport.GotoBottom()
+	appendLog(`{"level":"error","msg":"c"}` + "\n")
+	if _, cmd := m.Update(followTickMsg{}); cmd == nil {
+		t.Error("expected the next tick to be scheduled")
+	}
+	if m.viewport.TotalLines != 3 || m.cursorLine() != 3 {
+		t.Errorf("expected 3 lines with the cursor on line 3, got %d lines, line %d", m.viewport.TotalLines, m.cursorLine())
+	}
+
+	// Cursor elsewhere stays put
+	m.viewport.Goto(1)
+	appendLog(`{"level":"info","msg":"d"}` + "\n")
+	m.Update(followTickMsg{})
+	if m.viewport.TotalLines != 4 || m.cursorLine() != 1 {
+		t.Errorf("expected 4 lines with the cursor on line 1, got %d lines, line %d", m.viewport.TotalLines, m.cursorLine())
+	}
+
+	// Active filters extend to the new lines
+	sendKeys(&m, "+++++") // ≥ERROR
+	appendLog(`{"level":"error","msg":"e"}` + "\n" + `{"level":"info","msg":"f"}` + "\n")
+	m.Update(followTickMsg{})
+	if m.rowCount() != 2 || m.lineAt(2) != 5 {
+		t.Errorf("expected error lines 3 and 5 shown, got rows %v", m.visible)
+	}
+}
+
+// TestFollowCompletesLastLine verifies a write that finishes the partial
+// last line without adding one refreshes what's known about that line:
+// its filter membership and cached entry.
+func TestFollowCompletesLastLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	if err := os.WriteFile(path, []byte(`{"level":"info","msg":"a"}`+"\n"+`{"msg":"b","lev`), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	idx, err := index.Open(path)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer closeIndex(idx)
+
+	m := New(idx)
+	m.SetFollow(true)
+	var entry parser.LogEntry
+	raw, _ := idx.GetLine(2)
+	if err := m.parseLine(raw, 2, &entry); err != nil || entry.Level != "" {
+		t.Fatalf("expected the partial line without a level, got %q, %v", entry.Level, err)
+	}
+	sendKeys(&m, "+++++") // ≥ERROR
+	if m.rowCount() != 0 {
+		t.Fatalf("expected the partial line hidden, got %d rows", m.rowCount())
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatalf("failed to open file: %v", err)
+	}
+	_, err = f.WriteString(`el":"error"}`)
+	_ = f.Close()
+	if err != nil {
+		t.Fatalf("failed to append: %v", err)
+	}
+	m.Update(followTickMsg{})
+
+	if idx.LineCount() != 2 {
+		t.Fatalf("expected no line added, got %d lines", idx.LineCount())
+	}
+	if m.rowCount() != 1 || m.lineAt(1) != 2 {
+		t.Errorf("expected the completed error line shown, got %d rows", m.rowCount())
+	}
+	raw, _ = idx.GetLine(2)
+	if err := m.parseLine(raw, 2, &entry); err != nil || entry.Level != "error" {
+		t.Errorf("expected the cached entry refreshed, got level %q, %v", entry.Level, err)
+	}
+}
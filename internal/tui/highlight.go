@@ -0,0 +1,183 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lbe/jsonlogviewer/internal/index"
+)
+
+// highlighter emphasizes rows containing a pattern without hiding the
+// others.
+type highlighter struct {
+	// pattern is the text as typed at the * prompt.
+	pattern string
+	// match reports whether a raw line contains pattern, ignoring case.
+	// It's compiled once when the highlight is set and is safe to call
+	// from the background scan.
+	match linePredicate
+	// lines lists the highlighted lines once the scan has finished.
+	lines []int
+	// done is set when lines covers the whole file.
+	done bool
+	// gen identifies the scan, so results of a replaced highlight are
+	// dropped.
+	gen int
+	// cancel stops the background scan.
+	cancel context.CancelFunc
+}
+
+// highlightScanDoneMsg carries the lines from 1 to upTo that matched the
+// highlight started as scan gen.
+type highlightScanDoneMsg struct {
+	gen   int
+	lines []int
+	upTo  int
+}
+
+// setHighlight sets the standing highlight pattern and starts finding its
+// rows in the background. Rows matching it are styled distinctly but stay
+// visible and navigable. An empty pattern clears the highlight.
+func (m *Model) setHighlight(pattern string) tea.Cmd {
+	m.clearHighlight()
+	if pattern == "" {
+		m.statusMsg = "highlight cleared"
+		return nil
+	}
+	// The pattern is literal text, matched case-insensitively like a
+	// substring filter but without lowering every line on every frame
+	re := regexp.MustCompile("(?i)" + regexp.QuoteMeta(pattern))
+	m.highlightGen++
+	ctx, cancel := context.WithCancel(context.Background())
+	m.highlight = &highlighter{pattern: pattern, match: re.Match, gen: m.highlightGen, cancel: cancel}
+	return scanHighlight(ctx, m.idx, m.highlight.gen, m.highlight.match, m.idx.LineCount())
+}
+
+// clearHighlight stops any scan in progress and drops the highlight.
+func (m *Model) clearHighlight() {
+	if m.highlight != nil {
+		m.highlight.cancel()
+		m.highlight = nil
+	}
+}
+
+// scanHighlight returns a command matching lines 1 through upTo against
+// a highlight in the background. A cancelled scan sends no message.
+func scanHighlight(ctx context.Context, idx *index.Index, gen int, match linePredicate, upTo int) tea.Cmd {
+	return func() tea.Msg {
+		lines := make([]int, 0)
+		for n := 1; n <= upTo; n++ {
+			if n%regexCheckEvery == 0 && ctx.Err() != nil {
+				return nil
+			}
+			raw, err := idx.GetLine(n)
+			if err == nil && match(raw) {
+				lines = append(lines, n)
+			}
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+		return highlightScanDoneMsg{gen: gen, lines: lines, upTo: upTo}
+	}
+}
+
+// highlightScanDone installs the lines of a finished scan, matching any
+// lines added while it ran.
+func (m *Model) highlightScanDone(msg highlightScanDoneMsg) {
+	h := m.highlight
+	if h == nil || h.gen != msg.gen {
+		return
+	}
+	// The last scanned line may have been completed since, so it's
+	// matched again along with the new ones
+	h.lines = msg.lines
+	h.done = true
+	h.extend(m.idx, max(msg.upTo, 1))
+}
+
+// extend matches lines from onward after the index grew, replacing what
+// was found for them before. It does nothing while the scan is running.
+func (h *highlighter) extend(idx *index.Index, from int) {
+	if !h.done {
+		return
+	}
+	h.lines = dropFrom(h.lines, from)
+	for n := from; n <= idx.LineCount(); n++ {
+		raw, err := idx.GetLine(n)
+		if err == nil && h.match(raw) {
+			h.lines = append(h.lines, n)
+		}
+	}
+}
+
+// isHighlighted reports whether line n matches the active highlight.
+// Until the scan finishes the line itself is matched.
+func (m *Model) isHighlighted(n int) bool {
+	h := m.highlight
+	if h == nil {
+		return false
+	}
+	if h.done {
+		i := sort.SearchInts(h.lines, n)
+		return i < len(h.lines) && h.lines[i] == n
+	}
+	line, err := m.idx.GetLine(n)
+	if err != nil {
+		return false
+	}
+	return h.match(line)
+}
+
+// jumpHighlight moves the cursor to the next (dir > 0) or previous
+// (dir < 0) highlighted row. Like vim's bracket motions it doesn't wrap.
+// It steps through the scanned lines, so it waits for the scan.
+func (m *Model) jumpHighlight(dir int) {
+	h := m.highlight
+	if h == nil {
+		m.statusMsg = "no highlight set (use * to set one)"
+		return
+	}
+	if !h.done {
+		m.statusMsg = "still scanning for highlighted rows"
+		return
+	}
+	if pos := m.nextHighlightRow(dir); pos > 0 {
+		m.viewport.Goto(pos)
+		return
+	}
+	if dir > 0 {
+		m.statusMsg = fmt.Sprintf("no highlighted rows below: %s", h.pattern)
+	} else {
+		m.statusMsg = fmt.Sprintf("no highlighted rows above: %s", h.pattern)
+	}
+}
+
+// nextHighlightRow returns the nearest row past the cursor in direction
+// dir that shows a highlighted line, or 0 if there is none. In file order
+// it walks the highlighted lines, skipping those hidden by filters; sorted
+// rows have no such order, so each row is checked instead.
+func (m *Model) nextHighlightRow(dir int) int {
+	if m.sorted != nil {
+		for pos := m.viewport.Cursor + dir; pos >= 1 && pos <= m.rowCount(); pos += dir {
+			if m.isHighlighted(m.lineAt(pos)) {
+				return pos
+			}
+		}
+		return 0
+	}
+	lines, cur := m.highlight.lines, m.cursorLine()
+	i := sort.SearchInts(lines, cur+1)
+	if dir < 0 {
+		i = sort.SearchInts(lines, cur) - 1
+	}
+	for ; i >= 0 && i < len(lines); i += dir {
+		if pos := m.posOf(lines[i]); m.lineAt(pos) == lines[i] {
+			return pos
+		}
+	}
+	return 0
+}
@@ -0,0 +1,191 @@
+package tui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lbe/jsonlogviewer/internal/parser"
+)
+
+const highlightContent = `{"time":"2024-01-01T00:00:00Z","level":"info","msg":"starting"}
+{"time":"2024-01-01T00:00:01Z","level":"info","msg":"user login"}
+{"time":"2024-01-01T00:00:02Z","level":"info","msg":"tick"}
+{"time":"2024-01-01T00:00:03Z","level":"warn","msg":"USER logout"}
+{"time":"2024-01-01T00:00:04Z","level":"info","msg":"tick"}`
+
+// submitHighlight types pattern at the * prompt, submits it, and runs the
+// background scan to completion.
+func submitHighlight(m *Model, pattern string) {
+	sendKeys(m, "*"+pattern)
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	runCmds(m, cmd)
+}
+
+// TestHighlightRowStyle verifies matching rows get the highlight style and
+// non-matching rows don't, without hiding anything.
+func TestHighlightRowStyle(t *testing.T) {
+	idx := createTestIndex(t, highlightContent)
+	defer closeIndex(idx)
+
+	m := New(idx)
+	m.width = 120
+	m.height = 30
+
+	// Rows are matched directly until the scan finishes
+	sendKeys(&m, "*user\n")
+	if m.highlight == nil || m.highlight.pattern != "user" {
+		t.Fatalf("expected highlight pattern 'user', got %+v", m.highlight)
+	}
+	if !m.isHighlighted(2) || m.isHighlighted(3) {
+		t.Error("expected line 2 highlighted and line 3 not before the scan")
+	}
+
+	submitHighlight(&m, "user")
+	if !m.highlight.done || len(m.highlight.lines) != 2 {
+		t.Fatalf("expected the scan to find 2 lines, got %+v", m.highlight)
+	}
+	if m.prompt != promptNone {
+		t.Error("expected prompt to close after Enter")
+	}
+
+	wantBg := m.styles.Highlight.GetBackground()
+	for n := 2; n <= idx.LineCount(); n++ {
+		got := m.rowStyle(n, mustParse(t, &m, n)).GetBackground()
+		matched := n == 2 || n == 4
+		if matched && got != wantBg {
+			t.Errorf("line %d: expected highlight background", n)
+		}
+		if !matched && got == wantBg {
+			t.Errorf("line %d: unexpected highlight background", n)
+		}
+	}
+
+	// The cursor row keeps the selected style even when it matches
+	m.viewport.Goto(2)
+	if m.rowStyle(2, mustParse(t, &m, 2)).GetBackground() != m.styles.Selected.GetBackground() {
+		t.Error("expected selected style to win over highlight")
+	}
+
+	// Nothing is hidden: the table still covers every line
+	if m.viewport.TotalLines != idx.LineCount() {
+		t.Errorf("expected %d navigable lines, got %d", idx.LineCount(), m.viewport.TotalLines)
+	}
+}
+
+// TestHighlightJump verifies ]h and [h move between highlighted rows.
+func TestHighlightJump(t *testing.T) {
+	idx := createTestIndex(t, highlightContent)
+	defer closeIndex(idx)
+
+	m := New(idx)
+	m.width = 120
+	m.height = 30
+
+	sendKeys(&m, "*user\n")
+	sendKeys(&m, "]h")
+	if m.viewport.Cursor != 1 || m.statusMsg != "still scanning for highlighted rows" {
+		t.Errorf("expected ]h to wait for the scan, got cursor %d and %q", m.viewport.Cursor, m.statusMsg)
+	}
+
+	submitHighlight(&m, "user")
+	sendKeys(&m, "]h")
+	if m.viewport.Cursor != 2 {
+		t.Errorf("expected cursor at 2 after ]h, got %d", m.viewport.Cursor)
+	}
+	sendKeys(&m, "]h")
+	if m.viewport.Cursor != 4 {
+		t.Errorf("expected cursor at 4 after second ]h, got %d", m.viewport.Cursor)
+	}
+
+	// No more matches below: cursor stays and a message is shown
+	sendKeys(&m, "]h")
+	if m.viewport.Cursor != 4 {
+		t.Errorf("expected cursor to stay at 4, got %d", m.viewport.Cursor)
+	}
+	if m.statusMsg == "" {
+		t.Error("expected status message when no match remains")
+	}
+
+	sendKeys(&m, "[h")
+	if m.viewport.Cursor != 2 {
+		t.Errorf("expected cursor at 2 after [h, got %d", m.viewport.Cursor)
+	}
+
+	// A highlighted line hidden by a filter is skipped
+	m.viewport.Goto(1)
+	submitRegexFilter(&m, "warn|starting")
+	sendKeys(&m, "]h")
+	if m.cursorLine() != 4 {
+		t.Errorf("expected ]h to skip the hidden line 2 for line 4, got line %d", m.cursorLine())
+	}
+
+	// An empty pattern clears the highlight
+	sendKeys(&m, "*\n")
+	if m.highlight != nil {
+		t.Error("expected highlight to be cleared")
+	}
+}
+
+// TestPromptCancel verifies Esc closes the prompt without quitting.
+func TestPromptCancel(t *testing.T) {
+	idx := createTestIndex(t, highlightContent)
+	defer closeIndex(idx)
+
+	m := New(idx)
+	sendKeys(&m, "*abc")
+	if m.promptInput != "abc" {
+		t.Errorf("expected prompt input 'abc', got %q", m.promptInput)
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	if m.promptInput != "ab" {
+		t.Errorf("expected prompt input 'ab' after backspace, got %q", m.promptInput)
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.prompt != promptNone {
+		t.Error("expected prompt to close after Esc")
+	}
+	if m.confirmExit {
+		t.Error("Esc in a prompt should not ask to quit")
+	}
+	if m.highlight != nil {
+		t.Error("cancelled prompt should not set a highlight")
+	}
+}
+
+// TestLevelTint verifies zb tints error rows but not info rows, and the
+// cursor row keeps the selected style.
+func TestLevelTint(t *testing.T) {
+	content := `{"time":"2024-01-01T00:00:00Z","level":"info","msg":"fine"}
+{"time":"2024-01-01T00:00:01Z","level":"error","msg":"broken"}
+{"time":"2024-01-01T00:00:02Z","level":"error","msg":"still broken"}`
+	idx := createTestIndex(t, content)
+	defer closeIndex(idx)
+
+	m := New(idx)
+	m.width = 120
+	m.height = 30
+
+	tint := lipgloss.Color(parser.LevelTint("error"))
+	if m.rowStyle(2, mustParse(t, &m, 2)).GetBackground() == tint {
+		t.Error("expected no tint before zb")
+	}
+
+	sendKeys(&m, "zb")
+	if got := m.rowStyle(2, mustParse(t, &m, 2)).GetBackground(); got != tint {
+		t.Errorf("expected error row tint %v, got %v", tint, got)
+	}
+	if got := m.rowStyle(2, mustParse(t, &m, 2)).GetForeground(); got != lipgloss.Color(parser.LevelColor("error")) {
+		t.Errorf("expected tint to keep the level foreground, got %v", got)
+	}
+
+	m.viewport.Goto(3)
+	if m.rowStyle(1, mustParse(t, &m, 1)).GetBackground() == tint {
+		t.Error("expected info row to stay untinted")
+	}
+	if got := m.rowStyle(3, mustParse(t, &m, 3)).GetBackground(); got != m.styles.Selected.GetBackground() {
+		t.Errorf("expected selected style on the cursor row, got %v", got)
+	}
+}
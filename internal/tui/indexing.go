@@ -0,0 +1,72 @@
+package tui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lbe/jsonlogviewer/internal/index"
+)
+
+// indexPollInterval is how often the model picks up lines found by a lazy
+// index's background scan.
+const indexPollInterval = 200 * time.Millisecond
+
+// indexTickMsg asks the model to check a lazy index's scan progress.
+type indexTickMsg struct{}
+
+// indexTick schedules the next scan progress check.
+func indexTick() tea.Cmd {
+	return tea.Tick(indexPollInterval, func(time.Time) tea.Msg {
+		return indexTickMsg{}
+	})
+}
+
+// refreshIndexing adds the lines a lazy or streaming index found since the
+// last check and schedules the next check until the scan is done. As in
+// follow mode, a cursor on the last row stays on the last row.
+func (m *Model) refreshIndexing() tea.Cmd {
+	done, lines := m.idx.IndexProgress()
+	if lines > m.indexedLines {
+		atBottom := m.viewport.TotalLines > 0 && m.viewport.Cursor >= m.viewport.TotalLines
+		m.linesAppended(m.indexedLines + 1)
+		m.indexedLines = lines
+		if atBottom {
+			m.viewport.GotoBottom()
+		}
+	}
+	if !done {
+		return tea.Batch(indexTick(), m.histogramCmd())
+	}
+
+	m.indexing = false
+	if err := m.idx.IndexErr(); err != nil {
+		m.statusMsg = fmt.Sprintf("indexing stopped at line %d: %v", lines, err)
+	}
+	return m.histogramCmd()
+}
+
+// sizeState describes the data size and how long indexing took for the app
+// header, e.g. "1.3 GiB in 850ms". The time is left out while a lazy index
+// is still scanning.
+func (m *Model) sizeState() string {
+	state := index.FormatBytes(m.idx.Size())
+	if !m.indexing {
+		state += " in " + formatDuration(m.idx.IndexDuration())
+	}
+	return state
+}
+
+// formatDuration rounds d to a precision that suits its size: microseconds
+// below a millisecond, milliseconds below a second, and tenths of a second
+// above.
+func formatDuration(d time.Duration) string {
+	switch {
+	case d < time.Millisecond:
+		return d.Round(time.Microsecond).String()
+	case d < time.Second:
+		return d.Round(time.Millisecond).String()
+	default:
+		return d.Round(100 * time.Millisecond).String()
+	}
+}
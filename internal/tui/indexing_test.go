@@ -0,0 +1,136 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lbe/jsonlogviewer/internal/index"
+)
+
+// TestLazyIndexing verifies the model picks up lines from a lazy index's
+// background scan on each tick, extends active filters to them, and stops
+// ticking once the scan is done.
+func TestLazyIndexing(t *testing.T) {
+	// Larger than one scan chunk, so most of it is indexed in the background
+	const lines = 200000
+	var b strings.Builder
+	for n := 1; n <= lines; n++ {
+		if n%1000 == 0 {
+			b.WriteString(`{"level":"error","msg":"boom"}` + "\n")
+		} else {
+			b.WriteString(`{"level":"info","msg":"ok"}` + "\n")
+		}
+	}
+	path := filepath.Join(t.TempDir(), "big.log")
+	if err := os.WriteFile(path, []byte(b.String()), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	idx, err := index.OpenLazy(path, 100)
+	if err != nil {
+		t.Fatalf("OpenLazy failed: %v", err)
+	}
+	defer closeIndex(idx)
+
+	m := New(idx)
+	m.width = 120
+	m.height = 30
+	sendKeys(&m, "+++++") // ≥ERROR
+
+	for i := 0; m.indexing; i++ {
+		if i > 10000 {
+			t.Fatal("indexing never finished")
+		}
+		if _, cmd := m.Update(indexTickMsg{}); cmd == nil && m.indexing {
+			t.Fatal("expected another tick while indexing")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	if m.indexedLines != lines || m.idx.LineCount() != lines {
+		t.Errorf("expected %d lines picked up, got %d", lines, m.indexedLines)
+	}
+	if m.rowCount() != lines/1000 || m.viewport.TotalLines != lines/1000 {
+		t.Errorf("expected %d error rows, got %d (viewport %d)", lines/1000, m.rowCount(), m.viewport.TotalLines)
+	}
+	if strings.Contains(m.lineCountState(), "indexing") {
+		t.Errorf("expected no indexing marker once done, got %q", m.lineCountState())
+	}
+	if _, cmd := m.Update(indexTickMsg{}); cmd != nil {
+		t.Error("expected no further ticks once done")
+	}
+}
+
+// TestSizeState verifies the header shows the data size with the indexing
+// time, leaving the time out while a lazy index is still scanning.
+func TestSizeState(t *testing.T) {
+	idx := createTestIndex(t, levelContent)
+	defer closeIndex(idx)
+
+	m := New(idx)
+	m.width = 120
+	m.height = 30
+
+	size := index.FormatBytes(int64(len(levelContent)))
+	if got := m.sizeState(); !strings.HasPrefix(got, size+" in ") {
+		t.Errorf("expected %q with an index time, got %q", size, got)
+	}
+	if !strings.Contains(m.View(), size) {
+		t.Error("expected the size in the app header")
+	}
+
+	m.indexing = true
+	if got := m.sizeState(); got != size {
+		t.Errorf("expected only the size while indexing, got %q", got)
+	}
+}
+
+// TestFormatDuration verifies durations are rounded to suit their size.
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{1234 * time.Nanosecond, "1µs"},
+		{12345678 * time.Nanosecond, "12ms"},
+		{2345 * time.Millisecond, "2.3s"},
+	}
+	for _, tt := range tests {
+		if got := formatDuration(tt.d); got != tt.want {
+			t.Errorf("formatDuration(%v) = %q, expected %q", tt.d, got, tt.want)
+		}
+	}
+}
+
+// TestIndexingKeepsBottom verifies a cursor on the last row follows lines
+// picked up from a streaming index, as in follow mode, while a cursor
+// elsewhere stays put.
+func TestIndexingKeepsBottom(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		cursor int
+		want   int
+	}{
+		{"at bottom", 5, 7},
+		{"above bottom", 3, 3},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			idx := createTestIndex(t, levelContent)
+			defer closeIndex(idx)
+
+			// Pretend the last two lines haven't been picked up yet
+			m := New(idx)
+			m.indexing, m.indexedLines = true, 5
+			m.viewport.SetTotalLines(5)
+			m.viewport.Goto(tt.cursor)
+
+			m.refreshIndexing()
+			if m.viewport.Cursor != tt.want {
+				t.Errorf("expected cursor %d, got %d", tt.want, m.viewport.Cursor)
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// chromeHeight is the number of screen lines outside the data rows: the
+// app header, the column headers, the status line, and one spare line.
+const chromeHeight = 4
+
+// frameHeight is the extra lines used by the frame's top and bottom rules.
+const frameHeight = 2
+
+// layoutHeight sizes the viewport to the rows left over after the header,
+// status line, and (when shown) the frame rules, or to the table's share
+// of them when the panes are stacked. It does nothing until the terminal
+// size is known.
+func (m *Model) layoutHeight() {
+	if m.height == 0 {
+		return
+	}
+	if m.stacked && !m.hideDetail {
+		tableHeight, _ := m.stackedHeights()
+		m.viewport.SetHeight(tableHeight)
+		return
+	}
+	m.viewport.SetHeight(m.contentHeight())
+}
+
+// contentHeight returns the screen lines available to the panes.
+func (m *Model) contentHeight() int {
+	contentHeight := m.height - chromeHeight
+	if m.showFrame {
+		contentHeight -= frameHeight
+	}
+	if contentHeight < 1 {
+		contentHeight = 1
+	}
+	return contentHeight
+}
+
+// stackedHeights splits the pane lines between the table on top and the
+// detail below, leaving one line for the rule between them.
+func (m *Model) stackedHeights() (tableHeight, detailHeight int) {
+	contentHeight := m.contentHeight()
+	tableHeight = max((contentHeight-1)/2, 1)
+	detailHeight = max(contentHeight-1-tableHeight, 1)
+	return tableHeight, detailHeight
+}
+
+// toggleStacked switches between the table beside the detail and the
+// table above it, which suits tall, narrow terminals. Ctrl+r leaves the
+// choice alone since it follows the terminal's shape, not a view mode.
+func (m *Model) toggleStacked() {
+	m.stacked = !m.stacked
+	m.layoutHeight()
+	if m.stacked {
+		m.statusMsg = "panes stacked: detail below the table"
+	} else {
+		m.statusMsg = "panes side by side"
+	}
+}
+
+// toggleHideDetail collapses the detail pane so the table takes the whole
+// width, its message column growing into the space, and brings the pane
+// back. Like stacking, Ctrl+r leaves it alone.
+func (m *Model) toggleHideDetail() {
+	m.hideDetail = !m.hideDetail
+	m.detailFocus = false
+	m.layoutHeight()
+	if m.hideDetail {
+		m.statusMsg = "detail hidden: zd shows it again"
+	} else {
+		m.statusMsg = "detail pane shown"
+	}
+}
+
+// tableRows renders the table alone across the terminal, height lines
+// tall, while the detail pane is hidden.
+func (m *Model) tableRows(height int) []string {
+	rows := padLines(strings.Split(m.renderTable(), "\n"), height)
+	for i, row := range rows {
+		rows[i] = fitWidth(row, m.width)
+	}
+	return rows
+}
+
+// paneRows renders the table and the detail side by side, height lines
+// tall, with the separator column between them.
+func (m *Model) paneRows(height int) []string {
+	tableLines := padLines(strings.Split(m.renderTable(), "\n"), height)
+	detailLines := padLines(strings.Split(m.renderDetail(height), "\n"), height)
+
+	// Fit each table line to the table width so the separator lands in the
+	// same column on every row
+	tableWidth := m.tableWidth()
+	separators := m.separatorRows(height)
+	rows := make([]string, height)
+	for i := range rows {
+		rows[i] = fitWidth(tableLines[i], tableWidth) + separators[i] + detailLines[i]
+	}
+	return rows
+}
+
+// stackedRows renders the table over the detail, split by a rule that
+// meets the table's separator column and, like it, is colored while the
+// detail pane has the keys.
+func (m *Model) stackedRows() []string {
+	tableHeight, detailHeight := m.stackedHeights()
+	tableLines := padLines(strings.Split(m.renderTable(), "\n"), tableHeight)
+	detailLines := padLines(strings.Split(m.renderDetail(detailHeight), "\n"), detailHeight)
+
+	tableWidth := m.tableWidth()
+	separators := m.separatorRows(tableHeight)
+	rows := make([]string, 0, tableHeight+1+detailHeight)
+	for i := range tableHeight {
+		rows = append(rows, fitWidth(tableLines[i], tableWidth)+separators[i])
+	}
+	rows = append(rows, m.separatorStyle().Render(m.ruleText("┴")))
+	return append(rows, detailLines...)
+}
+
+// padLines pads or cuts lines to exactly height lines.
+func padLines(lines []string, height int) []string {
+	for len(lines) < height {
+		lines = append(lines, "")
+	}
+	return lines[:height]
+}
+
+// fitWidth cuts or pads s to exactly width display cells. Widths are
+// measured in terminal cells, so wide characters and ANSI styling don't
+// shift whatever is drawn after it.
+func fitWidth(s string, width int) string {
+	s = ansi.Truncate(s, width, "")
+	if w := ansi.StringWidth(s); w < width {
+		s += strings.Repeat(" ", width-w)
+	}
+	return s
+}
+
+// frameRule renders a horizontal rule across both panes with junction
+// where the separator column meets it. When the panes are stacked the
+// rule spans the terminal width, and with the detail hidden there's no
+// junction.
+func (m *Model) frameRule(junction string) string {
+	return m.styles.Separator.Render(m.ruleText(junction))
+}
+
+// ruleText is the unstyled text of frameRule.
+func (m *Model) ruleText(junction string) string {
+	if m.hideDetail {
+		return strings.Repeat("─", m.width)
+	}
+	rest := m.detailWidth()
+	if m.stacked {
+		rest = max(m.width-m.tableWidth()-1, 0)
+	}
+	return strings.Repeat("─", m.tableWidth()) + junction + strings.Repeat("─", rest)
+}
@@ -0,0 +1,201 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// separatorColumns returns the display column of the first separator on
+// each line of a rendered view that has one.
+func separatorColumns(view string, sep string) []int {
+	var cols []int
+	for _, line := range strings.Split(ansi.Strip(view), "\n") {
+		if i := strings.Index(line, sep); i >= 0 {
+			cols = append(cols, ansi.StringWidth(line[:i]))
+		}
+	}
+	return cols
+}
+
+// TestSeparatorAlignsWithWideCharacters verifies the separator sits in the
+// same display column on every row, even when cells hold wide characters.
+func TestSeparatorAlignsWithWideCharacters(t *testing.T) {
+	content := `{"time":"2024-01-01T00:00:00Z","level":"info","msg":"plain ascii message"}
+{"time":"2024-01-01T00:00:01Z","level":"info","msg":"日本語のログメッセージがとても長くて表の幅を超えてしまう場合"}
+{"time":"2024-01-01T00:00:02Z","level":"warn","msg":"mixed 漢字 and ascii 😀 emoji"}`
+	idx := createTestIndex(t, content)
+	defer closeIndex(idx)
+
+	m := New(idx)
+	m.Update(tea.WindowSizeMsg{Width: 140, Height: 20})
+
+	cols := separatorColumns(m.View(), "│")
+	if len(cols) != m.viewport.Height+1 {
+		t.Fatalf("expected a separator on the header and %d data rows, got %d", m.viewport.Height, len(cols))
+	}
+	for i, col := range cols {
+		if col != m.tableWidth() {
+			t.Errorf("row %d: separator at column %d, expected %d", i, col, m.tableWidth())
+		}
+	}
+}
+
+// TestFrameToggle verifies B draws corner junctions in the separator column
+// and shrinks the data region to keep the view on screen.
+func TestFrameToggle(t *testing.T) {
+	content := `{"time":"2024-01-01T00:00:00Z","level":"info","msg":"test"}`
+	idx := createTestIndex(t, content)
+	defer closeIndex(idx)
+
+	m := New(idx)
+	m.Update(tea.WindowSizeMsg{Width: 120, Height: 20})
+	height := m.viewport.Height
+
+	sendKeys(&m, "B")
+	if !m.showFrame {
+		t.Fatal("expected B to enable the frame")
+	}
+	if m.viewport.Height != height-frameHeight {
+		t.Errorf("expected data height %d, got %d", height-frameHeight, m.viewport.Height)
+	}
+
+	view := m.View()
+	if lines := strings.Count(view, "\n") + 1; lines > 20 {
+		t.Errorf("expected view to fit 20 lines, got %d", lines)
+	}
+	for _, junction := range []string{"┬", "┴"} {
+		cols := separatorColumns(view, junction)
+		if len(cols) != 1 || cols[0] != m.tableWidth() {
+			t.Errorf("expected one %s at column %d, got %v", junction, m.tableWidth(), cols)
+		}
+	}
+
+	sendKeys(&m, "B")
+	if m.showFrame || m.viewport.Height != height {
+		t.Error("expected second B to remove the frame and restore the height")
+	}
+}
+
+// TestFitWidth verifies strings are cut or padded by display width.
+func TestFitWidth(t *testing.T) {
+	tests := []struct {
+		in    string
+		width int
+		want  string
+	}{
+		{"abc", 5, "abc  "},
+		{"abcdef", 4, "abcd"},
+		{"日本語", 4, "日本"},
+		{"日本語", 5, "日本 "},
+	}
+
+	for _, tt := range tests {
+		if got := fitWidth(tt.in, tt.width); got != tt.want {
+			t.Errorf("fitWidth(%q, %d) = %q, expected %q", tt.in, tt.width, got, tt.want)
+		}
+	}
+}
+
+// TestStackedPanes verifies zp puts the detail below the table at full
+// width, splitting the rows between them, and that zp restores the
+// side-by-side layout.
+func TestStackedPanes(t *testing.T) {
+	content := `{"time":"2024-01-01T00:00:00Z","level":"info","msg":"test","user":"alice"}`
+	idx := createTestIndex(t, content)
+	defer closeIndex(idx)
+
+	m := New(idx)
+	m.Update(tea.WindowSizeMsg{Width: 90, Height: 40})
+	height := m.viewport.Height
+
+	sendKeys(&m, "zp")
+	if !m.stacked {
+		t.Fatal("expected zp to stack the panes")
+	}
+	tableHeight, detailHeight := m.stackedHeights()
+	if m.viewport.Height != tableHeight || tableHeight+1+detailHeight != height {
+		t.Errorf("expected %d table and %d detail lines to fill %d, viewport %d",
+			tableHeight, detailHeight, height, m.viewport.Height)
+	}
+	if m.detailWidth() != 90 {
+		t.Errorf("expected the detail to span the terminal, got width %d", m.detailWidth())
+	}
+
+	view := m.View()
+	if lines := strings.Count(view, "\n") + 1; lines > 40 {
+		t.Errorf("expected view to fit 40 lines, got %d", lines)
+	}
+	lines := strings.Split(ansi.Strip(view), "\n")
+	rule := 2 + tableHeight
+	if !strings.HasPrefix(lines[rule], "───") || separatorColumns(lines[rule], "┴")[0] != m.tableWidth() {
+		t.Errorf("expected a rule below the table, got %q", lines[rule])
+	}
+	if !strings.Contains(strings.Join(lines[rule+1:], "\n"), "alice") {
+		t.Error("expected the detail below the rule")
+	}
+	for _, line := range lines[2:rule] {
+		if strings.Contains(line, "alice") {
+			t.Errorf("expected no detail beside the table, got %q", line)
+		}
+	}
+
+	m.resetView()
+	if !m.stacked {
+		t.Error("expected Ctrl+r to keep the stacked layout")
+	}
+	sendKeys(&m, "zp")
+	if m.stacked || m.viewport.Height != height {
+		t.Error("expected second zp to restore the side-by-side layout")
+	}
+}
+
+// TestHideDetail verifies zd gives the table the full width, with the
+// message column showing text past its usual cut, and brings the split
+// back.
+func TestHideDetail(t *testing.T) {
+	msg := strings.Repeat("word ", 30) + "END"
+	content := `{"time":"2024-01-01T00:00:00Z","level":"info","msg":"` + msg + `","user":"alice"}`
+	idx := createTestIndex(t, content)
+	defer closeIndex(idx)
+
+	m := New(idx)
+	m.Update(tea.WindowSizeMsg{Width: 200, Height: 20})
+	tableWidth := m.tableWidth()
+
+	sendKeys(&m, "zd")
+	if !m.hideDetail || m.detailWidth() != 0 {
+		t.Fatalf("expected zd to hide the detail, got width %d", m.detailWidth())
+	}
+	if m.tableWidth() != 200 {
+		t.Errorf("expected the table to span 200 columns, got %d", m.tableWidth())
+	}
+
+	view := ansi.Strip(m.View())
+	if !strings.Contains(view, "END") {
+		t.Error("expected the whole message in the widened column")
+	}
+	if strings.Contains(view, "alice") || strings.Contains(view, "│") {
+		t.Error("expected no detail pane or separator")
+	}
+	for i, line := range strings.Split(view, "\n") {
+		if ansi.StringWidth(line) > 200 {
+			t.Errorf("line %d: expected at most 200 columns, got %d", i, ansi.StringWidth(line))
+		}
+	}
+
+	sendKeys(&m, "\t")
+	if m.detailFocus {
+		t.Error("expected no field cursor while the detail is hidden")
+	}
+
+	sendKeys(&m, "zd")
+	if m.hideDetail || m.tableWidth() != tableWidth {
+		t.Errorf("expected second zd to restore the %d-column table, got %d", tableWidth, m.tableWidth())
+	}
+	if !strings.Contains(m.View(), "alice") {
+		t.Error("expected the detail pane back")
+	}
+}
@@ -0,0 +1,68 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// markName returns the mark named by the key typed after m or ', which
+// must be a single ASCII letter.
+func markName(key string) (rune, bool) {
+	if len(key) != 1 {
+		return 0, false
+	}
+	r := rune(key[0])
+	return r, ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z')
+}
+
+// setMark stores the cursor's file line under the mark named by key, like
+// vim's m. File lines are stored so marks survive filtering.
+func (m *Model) setMark(key string) {
+	r, ok := markName(key)
+	if !ok {
+		m.statusMsg = fmt.Sprintf("invalid mark %q (use a-z or A-Z)", key)
+		return
+	}
+	if m.marks == nil {
+		m.marks = make(map[rune]int)
+	}
+	m.marks[r] = m.cursorLine()
+	m.statusMsg = fmt.Sprintf("mark '%c' set at line %d", r, m.marks[r])
+}
+
+// jumpMark moves the cursor to the line stored under the mark named by
+// key, like vim's '.
+func (m *Model) jumpMark(key string) {
+	r, ok := markName(key)
+	if !ok {
+		m.statusMsg = fmt.Sprintf("invalid mark %q (use a-z or A-Z)", key)
+		return
+	}
+	n, ok := m.marks[r]
+	if !ok {
+		m.statusMsg = fmt.Sprintf("mark '%c' not set", r)
+		return
+	}
+	m.gotoLineCommand(n)
+}
+
+// marksState lists the set marks in order for the help overlay, e.g.
+// "marks: a:120 b:4500", or "" when none are set.
+func (m *Model) marksState() string {
+	if len(m.marks) == 0 {
+		return ""
+	}
+	names := make([]rune, 0, len(m.marks))
+	for r := range m.marks {
+		names = append(names, r)
+	}
+	slices.Sort(names)
+
+	var b strings.Builder
+	b.WriteString("marks:")
+	for _, r := range names {
+		fmt.Fprintf(&b, " %c:%d", r, m.marks[r])
+	}
+	return b.String()
+}
@@ -0,0 +1,49 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestMarks verifies m sets a mark on the cursor's file line, ' jumps back
+// to it after moving and filtering, and bad or unset marks are reported.
+func TestMarks(t *testing.T) {
+	idx := createTestIndex(t, levelContent)
+	defer closeIndex(idx)
+
+	m := New(idx)
+	m.width = 200
+	m.height = 30
+
+	sendKeys(&m, "5Gma") // error line
+	sendKeys(&m, "2GmB") // info line
+	sendKeys(&m, "G'a")
+	if m.cursorLine() != 5 {
+		t.Errorf("expected 'a to jump to line 5, got %d", m.cursorLine())
+	}
+
+	// Marks name file lines, so they still work with lines hidden above
+	sendKeys(&m, "+++++") // ≥ERROR
+	sendKeys(&m, "gg'a")
+	if m.cursorLine() != 5 {
+		t.Errorf("expected 'a to reach line 5 while filtered, got %d", m.cursorLine())
+	}
+	sendKeys(&m, "'B")
+	if !strings.Contains(m.statusMsg, "hidden by filters") {
+		t.Errorf("expected a note that line 2 is hidden, got %q", m.statusMsg)
+	}
+
+	sendKeys(&m, "'z")
+	if m.statusMsg != "mark 'z' not set" {
+		t.Errorf("unexpected status %q", m.statusMsg)
+	}
+	sendKeys(&m, "m1")
+	if !strings.HasPrefix(m.statusMsg, "invalid mark") {
+		t.Errorf("unexpected status %q", m.statusMsg)
+	}
+
+	sendKeys(&m, "?")
+	if !strings.Contains(m.View(), "marks: B:2 a:5") {
+		t.Error("expected the set marks listed in the help overlay")
+	}
+}
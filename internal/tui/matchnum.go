@@ -0,0 +1,18 @@
+package tui
+
+// SetMatchNumbers adds a Match column after Row numbering the table rows
+// 1, 2, 3... in the order shown, while Row keeps the file line number.
+// With a filter active that's each line's place among the matches, to
+// read alongside the line numbers of grep -n. Off by default.
+func (m *Model) SetMatchNumbers(on bool) {
+	m.matchNumbers = on
+}
+
+// numbersWidth returns the width of the number columns before the first
+// configured column: Row, and Match when it's shown.
+func (m *Model) numbersWidth() int {
+	if m.matchNumbers {
+		return 2*rowNumWidth + 1
+	}
+	return rowNumWidth
+}
@@ -0,0 +1,44 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// TestFilteredRowNumbers verifies Row keeps the file line numbers while a
+// filter hides lines, and that the Match column numbers the rows shown.
+func TestFilteredRowNumbers(t *testing.T) {
+	idx := createTestIndex(t, levelContent)
+	defer closeIndex(idx)
+
+	m := New(idx)
+	m.width = 120
+	m.height = 30
+	sendKeys(&m, "+++++") // ≥ERROR: lines 5 and 6
+
+	table := m.renderTable()
+	for _, n := range []int{5, 6} {
+		if !strings.Contains(table, fmt.Sprintf("%*d ", rowNumWidth, n)) {
+			t.Errorf("expected line %d numbered in the filtered table", n)
+		}
+	}
+	if strings.Contains(m.renderTableHeader(), "Match") {
+		t.Error("expected no Match column by default")
+	}
+
+	width := m.tableWidth()
+	m.SetMatchNumbers(true)
+	if header := m.renderTableHeader(); !strings.Contains(header, fmt.Sprintf("%*s %*s", rowNumWidth, "Row", rowNumWidth, "Match")) {
+		t.Errorf("expected a Match column after Row, got %q", header)
+	}
+	table = m.renderTable()
+	for match, n := range []int{5, 6} {
+		if want := fmt.Sprintf("%*d %*d ", rowNumWidth, n, rowNumWidth, match+1); !strings.Contains(table, want) {
+			t.Errorf("expected line %d numbered as match %d, want %q in the table", n, match+1, want)
+		}
+	}
+	if got, want := m.tableWidth(), width+1+rowNumWidth; got != want {
+		t.Errorf("expected the Match column to widen the table to %d, got %d", want, got)
+	}
+}
@@ -5,13 +5,17 @@ package tui
 
 import (
 	"fmt"
+	"log/slog"
+	"slices"
 	"strings"
 	"time"
 
+	"github.com/atotto/clipboard"
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 	"github.com/lbe/jsonlogviewer/internal/index"
 	"github.com/lbe/jsonlogviewer/internal/nav"
 	"github.com/lbe/jsonlogviewer/internal/parser"
@@ -37,10 +41,17 @@ type Model struct {
 	// State
 	// showHelp toggles the help overlay.
 	showHelp bool
+	// helpAbout shows the about page of the help overlay instead of the
+	// key bindings.
+	helpAbout bool
 	// quitting indicates the user wants to exit.
 	quitting bool
 	// confirmExit indicates the user needs to confirm exit (after pressing Esc).
 	confirmExit bool
+	// confirm is a pending y/n question, such as whether to overwrite a file.
+	confirm *confirmation
+	// force skips overwrite confirmations for file-writing commands.
+	force bool
 	// pendingNumber accumulates digits for numbered commands.
 	pendingNumber string
 	// lastG tracks whether the last command was 'g' (for gg motion).
@@ -49,22 +60,217 @@ type Model struct {
 	resizeMode bool
 	// resizeTimer is the timeout for resize mode.
 	resizeTimer time.Time
-	// lastCursor tracks the previous cursor position to detect changes.
+	// lastCursor tracks the line previously under the cursor to detect
+	// changes.
 	lastCursor int
+	// minLevel is the parser.LevelRank below which lines are hidden; 0
+	// shows every line.
+	minLevel int
+	// hiddenLevels marks the levelToggles hidden with f1 through f5.
+	hiddenLevels [len(levelToggles)]bool
+	// since and until bound the timestamps of the lines shown; a zero
+	// bound is open. excludeUntimed also hides lines without one.
+	since, until   time.Time
+	excludeUntimed bool
+	// visible lists the file lines shown as table rows when a filter is
+	// active; nil shows every line.
+	visible []int
+	// sorted puts the rows in the order of a column's values; nil keeps
+	// file order.
+	sorted *sortOrder
+	// columns is the table column layout in display order.
+	columns []column
+	// initialColumns is the layout columns starts from and resets to.
+	initialColumns []column
+	// autoFields are detected fields to add as columns once the terminal
+	// width is known; see WithAutoColumns.
+	autoFields []string
+	// columnMode indicates the user is reordering columns.
+	columnMode bool
+	// selectedColumn is the index into columns acted on in column mode.
+	selectedColumn int
+	// showSparkline toggles the time histogram in the app header.
+	showSparkline bool
+	// detailMode selects the detail pane layout (pretty JSON or fields).
+	detailMode detailMode
+	// noTruncate keeps detail lines whole, reachable by horizontal scrolling,
+	// instead of cutting them at the pane edge.
+	noTruncate bool
+	// wrapDetail folds long detail lines onto indented continuation lines
+	// instead of cutting them at the pane edge.
+	wrapDetail bool
+	// detailHOffset is the first display column shown in the detail pane
+	// when noTruncate is on.
+	detailHOffset int
+	// detailMaxWidth is the display width of the widest line of the current
+	// detail, recorded by renderDetail.
+	detailMaxWidth int
+	// hScroll is how many display columns the table's message text is
+	// scrolled left by.
+	hScroll int
+	// resetHScroll scrolls the messages back to their start whenever the
+	// cursor moves to another row.
+	resetHScroll bool
+	// lockDetailScroll keeps the detail offsets when the cursor moves, so the
+	// same depth of each entry's JSON stays in view.
+	lockDetailScroll bool
+	// levelTint gives warning and worse rows a level-colored background.
+	levelTint bool
+	// contextLines is how many neighboring entries above and below the
+	// cursor are previewed under the detail; 0 turns the preview off.
+	contextLines int
+	// showRaw stacks the raw line above the pretty JSON in the detail pane.
+	showRaw bool
+	// stripDetailANSI removes ANSI color codes embedded in string values
+	// from the detail pane instead of rendering their colors. The table
+	// always strips them to keep its columns aligned.
+	stripDetailANSI bool
+	// sourceDir is the base directory for relative source.file paths.
+	sourceDir string
+	// copyText puts text on the system clipboard for y and Y.
+	copyText func(string) error
+	// schema is the detected schema of line schemaLine, cached for the
+	// status line; schemaLine is 0 when nothing is cached.
+	schema     parser.Schema
+	schemaLine int
+	// showScrollbar draws the pane separator as a scrollbar whose thumb
+	// marks the rows in view.
+	showScrollbar bool
+	// pageKeepCursor moves the cursor with the view when paging, keeping its
+	// screen row, instead of moving it to the edge of the new page.
+	pageKeepCursor bool
+	// relativeNumbers shows each row's distance from the cursor in the Row
+	// column instead of its line number.
+	relativeNumbers bool
+	// matchNumbers adds a column numbering the rows in display order.
+	matchNumbers bool
+	// logger records lines that fail to parse or pretty-print; nil
+	// records nothing.
+	logger *slog.Logger
+	// formatLogged holds the lines whose pretty-print failure was logged,
+	// so redrawing them doesn't log them again.
+	formatLogged map[int]bool
+	// showFrame draws rules with corner junctions above and below the data
+	// rows so the separator reads as one continuous line.
+	showFrame bool
+	// stacked puts the detail pane below the table instead of beside it.
+	stacked bool
+	// hideDetail collapses the detail pane so the table, and its message
+	// column, take the whole width.
+	hideDetail bool
+	// parseErrs tracks the lines that failed to parse.
+	parseErrs parseErrors
+	// detailFocus sends the movement keys to the detail pane, where they
+	// move a field cursor or scroll, instead of the table.
+	detailFocus bool
+	// fieldCursor is the index of the selected field among the cursor
+	// line's flattened fields.
+	fieldCursor int
+	// treeFolds marks the paths of the objects and arrays folded in the
+	// tree detail view.
+	treeFolds map[string]bool
+	// visual is set while a visual line selection is in progress; it runs
+	// from visualAnchor, a file line, to the cursor line.
+	visual       bool
+	visualAnchor int
+	// timeLayout is the Go time layout for table timestamps.
+	timeLayout string
+	// displayLocal shows table timestamps in the local time zone instead
+	// of UTC.
+	displayLocal bool
+	// entries caches parsed and pretty-printed lines for redrawing.
+	entries *entryCache
+	// histogram caches the sampled time histogram for the sparkline.
+	histogram *histogram
+	// histogramBuilt reports whether histogram has been computed (it may
+	// legitimately be nil when no timestamps parse).
+	histogramBuilt bool
+	// histogramPending is set while a background build is running.
+	histogramPending bool
+	// histogramGen identifies the histogram build, so the result of one
+	// started before the data changed is dropped.
+	histogramGen int
+	// prompt is the kind of status-line prompt currently open, if any.
+	prompt promptKind
+	// promptInput is the text typed into the open prompt.
+	promptInput string
+	// promptErr explains why the prompt's last input was rejected, such as
+	// invalid regex syntax, until the next key press.
+	promptErr string
+	// statusMsg is a transient message shown in the status line until the
+	// next key press.
+	statusMsg string
+	// pendingPrefix holds "[", "]", "z", "f", "m", or "'" while waiting for
+	// the second key of a prefixed command such as ]h, zn, f1, or ma.
+	pendingPrefix string
+	// marks maps mark letters set with m to file line numbers.
+	marks map[rune]int
+	// follow re-reads the file as it grows, like tail -f.
+	follow bool
+	// followBackoff paces follow-mode refreshes.
+	followBackoff *followBackoff
+	// reopen builds a fresh index of the file for R; nil when the source
+	// can't be reloaded.
+	reopen func() (*index.Index, error)
+	// indexing is set while a lazy index is still scanning the file.
+	indexing bool
+	// indexedLines is how many lines the model has picked up from a lazy
+	// index so far.
+	indexedLines int
+	// showTOC shows the outline of anchor lines in place of the panes.
+	showTOC bool
+	// tocMode selects which lines the outline lists.
+	tocMode tocMode
+	// tocSelected is the index of the selected outline anchor.
+	tocSelected int
+	// tocOffset is the index of the first outline anchor shown.
+	tocOffset int
+	// tocCache holds the anchors computed for each outline mode.
+	tocCache map[tocMode][]int
+	// stats is the open stats overlay, shown in place of the panes; nil
+	// when closed.
+	stats *statsView
+	// statsGen counts stats scans started, to tell their results apart.
+	statsGen int
+	// searchQuery is the last search, also highlighted in the detail pane.
+	searchQuery string
+	// searchMatches lists the lines matching searchQuery, found once per
+	// search so n and N don't rescan the file.
+	searchMatches []int
+	// highlight emphasizes matching rows without hiding the others, if
+	// one is set.
+	highlight *highlighter
+	// highlightGen counts highlights set, to tell their scans apart.
+	highlightGen int
+	// regex is the regex filter, if one is set.
+	regex *regexFilter
+	// regexGen counts regex filters set, to tell their scans apart.
+	regexGen int
 
 	// Styles
 	styles *Styles
+	// theme overrides the default level colors; nil uses the defaults.
+	theme *Theme
+	// background selects the default colors for a dark or light terminal.
+	background Background
 	// help is the help component.
 	help help.Model
 	// keys holds the key bindings.
 	keys KeyMap
 	// version is the application version string.
 	version string
+	// buildInfo describes the build on the about page; empty shows the
+	// Go version.
+	buildInfo string
 }
 
 // resizeTimeout is the duration for resize mode to remain active.
 const resizeTimeout = 2 * time.Second
 
+// defaultLeftWidth is the left pane width used after a window resize:
+// the table content width (row + time + level + msg + spaces).
+const defaultLeftWidth = 74
+
 // resizeTimeoutMsg is sent when resize mode times out.
 type resizeTimeoutMsg struct{}
 
@@ -74,8 +280,20 @@ type Styles struct {
 	Header lipgloss.Style
 	// Selected row style.
 	Selected lipgloss.Style
+	// Highlighted row style (rows matching the highlight pattern).
+	Highlight lipgloss.Style
 	// Normal row style.
 	Normal lipgloss.Style
+	// SearchMatch style for search matches in the detail pane.
+	SearchMatch lipgloss.Style
+	// Match style for table rows matching the active search.
+	Match lipgloss.Style
+	// Visual style for table rows in the visual selection.
+	Visual lipgloss.Style
+	// ErrorMsg style for the message of a structured error in the detail pane.
+	ErrorMsg lipgloss.Style
+	// ParseError style for table rows that aren't valid JSON.
+	ParseError lipgloss.Style
 	// Detail pane style.
 	Detail lipgloss.Style
 	// Title style.
@@ -90,8 +308,12 @@ type Styles struct {
 	DetailContainer lipgloss.Style
 }
 
-// DefaultStyles returns the default UI styles.
-func DefaultStyles() *Styles {
+// DefaultStyles returns the default UI styles for a dark or light
+// terminal background.
+func DefaultStyles(bg Background) *Styles {
+	if bg == BackgroundLight {
+		return lightStyles()
+	}
 	return &Styles{
 		Header: lipgloss.NewStyle().
 			Bold(true).
@@ -100,8 +322,23 @@ func DefaultStyles() *Styles {
 		Selected: lipgloss.NewStyle().
 			Foreground(lipgloss.Color("#FFFFFF")).
 			Background(lipgloss.Color("#5C5C5C")),
+		Highlight: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#FFFFFF")).
+			Background(lipgloss.Color("#5F4B00")),
 		Normal: lipgloss.NewStyle().
 			Foreground(lipgloss.Color("#E0E0E0")),
+		SearchMatch: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#000000")).
+			Background(lipgloss.Color("#FFFF00")),
+		Match: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#FFFFFF")).
+			Background(lipgloss.Color("#1F4A6E")),
+		Visual: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#FFFFFF")).
+			Background(lipgloss.Color("#4B3B6E")),
+		ErrorMsg: lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("#FF0000")),
 		Detail: lipgloss.NewStyle().
 			Foreground(lipgloss.Color("#E0E0E0")),
 		Title: lipgloss.NewStyle().
@@ -111,6 +348,9 @@ func DefaultStyles() *Styles {
 			Foreground(lipgloss.Color("#808080")),
 		Separator: lipgloss.NewStyle().
 			Foreground(lipgloss.Color("#606060")),
+		ParseError: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#D75F5F")).
+			Italic(true),
 		TableContainer:  lipgloss.NewStyle(),
 		DetailContainer: lipgloss.NewStyle(),
 	}
@@ -130,16 +370,85 @@ type KeyMap struct {
 	VimDown   key.Binding
 	VimTop    key.Binding
 	VimBottom key.Binding
+	Percent   key.Binding
 	// Actions
-	Quit key.Binding
-	Help key.Binding
+	Quit     key.Binding
+	Help     key.Binding
+	HelpPage key.Binding
 	// Pane navigation
-	Left  key.Binding
-	Right key.Binding
+	Left        key.Binding
+	Right       key.Binding
+	TableScroll key.Binding
 	// Resize
 	ResizeMode  key.Binding
 	ResizeLeft  key.Binding
 	ResizeRight key.Binding
+	// Columns
+	ColumnMode key.Binding
+	MsgLen     key.Binding
+	// Display toggles
+	Sparkline       key.Binding
+	TimeZone        key.Binding
+	Frame           key.Binding
+	LevelTint       key.Binding
+	RelativeNumbers key.Binding
+	Scrollbar       key.Binding
+	StackPanes      key.Binding
+	HideDetail      key.Binding
+	// Source
+	OpenSource key.Binding
+	Pager      key.Binding
+	// Command line
+	Command key.Binding
+	// Detail pane
+	NoTruncate   key.Binding
+	WrapDetail   key.Binding
+	DetailScroll key.Binding
+	LockDetail   key.Binding
+	RawDetail    key.Binding
+	ANSI         key.Binding
+	Yank         key.Binding
+	Visual       key.Binding
+	DetailMode   key.Binding
+	Context      key.Binding
+	DetailFocus  key.Binding
+	// Outline
+	TOC key.Binding
+	// Stats
+	Stats key.Binding
+	// Search
+	Search        key.Binding
+	SearchNext    key.Binding
+	ExportMatches key.Binding
+	// Highlight
+	Highlight     key.Binding
+	HighlightNext key.Binding
+	// Parse errors
+	ParseErrorNext key.Binding
+	// Marks
+	Marks key.Binding
+	// Filters
+	MinLevel    key.Binding
+	LevelToggle key.Binding
+	RegexFilter key.Binding
+	// Position
+	Position key.Binding
+	// Reset
+	ResetView key.Binding
+	Redraw    key.Binding
+	Reload    key.Binding
+}
+
+// sequenceBinding returns a help-only binding for a command typed as a
+// sequence of keys, such as zn, or several separated by slashes, such as
+// "]h/[h". Its keys are the whole sequences, which no single key press
+// matches, so key.Matches never fires on the prefix the way a binding on
+// z alone would; the help view still lists it because it has keys.
+func sequenceBinding(keys, desc string) key.Binding {
+	return key.NewBinding(
+		key.WithKeys(strings.Split(keys, "/")...),
+		key.WithHelp(keys, desc),
+	)
 }
 
 // DefaultKeyMap returns the default key bindings.
@@ -177,14 +486,15 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("j"),
 			key.WithHelp("j", "down"),
 		),
-		VimTop: key.NewBinding(
-			key.WithKeys("g"),
-			key.WithHelp("gg", "first line"),
-		),
+		VimTop: sequenceBinding("gg", "first line"),
 		VimBottom: key.NewBinding(
 			key.WithKeys("G"),
 			key.WithHelp("G", "last line"),
 		),
+		Percent: key.NewBinding(
+			key.WithKeys("%"),
+			key.WithHelp("{n}%", "jump to n% of the file"),
+		),
 		Quit: key.NewBinding(
 			key.WithKeys("q"),
 			key.WithHelp("q", "quit"),
@@ -193,6 +503,10 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("f1", "?"),
 			key.WithHelp("F1/?", "help"),
 		),
+		HelpPage: key.NewBinding(
+			key.WithKeys("tab"),
+			key.WithHelp("Tab", "help: version and file info"),
+		),
 		Left: key.NewBinding(
 			key.WithKeys("h"),
 			key.WithHelp("h", "scroll detail up"),
@@ -213,6 +527,119 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys(">"),
 			key.WithHelp(">", "resize right"),
 		),
+		ColumnMode: key.NewBinding(
+			key.WithKeys("C"),
+			key.WithHelp("C", "reorder or sort columns"),
+		),
+		MinLevel: key.NewBinding(
+			key.WithKeys("+", "-"),
+			key.WithHelp("+/-", "raise/lower minimum level"),
+		),
+		LevelToggle: sequenceBinding("f1-f5/f0", "toggle DEBUG..FATAL/clear filters"),
+		RegexFilter: key.NewBinding(
+			key.WithKeys("&"),
+			key.WithHelp("&", "filter by regex, field=~regex, or field > n"),
+		),
+		TableScroll: key.NewBinding(
+			key.WithKeys("shift+left", "shift+right"),
+			key.WithHelp("⇧←/⇧→", "scroll messages"),
+		),
+		MsgLen: key.NewBinding(
+			key.WithKeys("{", "}"),
+			key.WithHelp("{/}", "shorter/longer messages"),
+		),
+		Command: key.NewBinding(
+			key.WithKeys(":"),
+			key.WithHelp(":N / :w[!] file", "go to line / write lines to file"),
+		),
+		OpenSource: sequenceBinding("gf", "open source.file in $EDITOR"),
+		Pager:      sequenceBinding("gp", "view entry in $PAGER"),
+		Sparkline: key.NewBinding(
+			key.WithKeys("T"),
+			key.WithHelp("T", "time histogram"),
+		),
+		NoTruncate:   sequenceBinding("zn", "no-truncate detail"),
+		WrapDetail:   sequenceBinding("zw", "wrap detail lines"),
+		DetailScroll: sequenceBinding("zh/zl", "scroll detail left/right"),
+		LockDetail:   sequenceBinding("zs", "lock detail scroll"),
+		RawDetail:    sequenceBinding("zr", "raw + pretty detail"),
+		ANSI:         sequenceBinding("za", "render/strip ANSI colors in detail"),
+		Yank: key.NewBinding(
+			key.WithKeys("y", "Y"),
+			key.WithHelp("y/Y", "copy raw/pretty JSON"),
+		),
+		Visual: key.NewBinding(
+			key.WithKeys("V"),
+			key.WithHelp("V", "select lines (y copy, w write)"),
+		),
+		DetailMode: sequenceBinding("zv", "cycle detail view (JSON/fields/tree)"),
+		Context: key.NewBinding(
+			key.WithKeys("(", ")"),
+			key.WithHelp("(/)", "fewer/more context lines"),
+		),
+		DetailFocus: key.NewBinding(
+			key.WithKeys("tab"),
+			key.WithHelp("Tab", "focus the detail pane"),
+		),
+		Frame: key.NewBinding(
+			key.WithKeys("B"),
+			key.WithHelp("B", "toggle frame"),
+		),
+		RelativeNumbers: key.NewBinding(
+			key.WithKeys("#"),
+			key.WithHelp("#", "relative row numbers"),
+		),
+		LevelTint:  sequenceBinding("zb", "level row tint"),
+		Scrollbar:  sequenceBinding("zm", "scroll position bar"),
+		StackPanes: sequenceBinding("zp", "stack detail below table"),
+		HideDetail: sequenceBinding("zd", "hide/show detail pane"),
+		TimeZone: key.NewBinding(
+			key.WithKeys("t"),
+			key.WithHelp("t", "toggle UTC/local time"),
+		),
+		Search: key.NewBinding(
+			key.WithKeys("/"),
+			key.WithHelp("/", "search"),
+		),
+		SearchNext: key.NewBinding(
+			key.WithKeys("n", "N"),
+			key.WithHelp("n/N", "next/previous match"),
+		),
+		ExportMatches: key.NewBinding(
+			key.WithKeys("w"),
+			key.WithHelp("w", "write search/filter matches to file"),
+		),
+		TOC: key.NewBinding(
+			key.WithKeys("o"),
+			key.WithHelp("o", "outline of errors/level changes"),
+		),
+		Stats: key.NewBinding(
+			key.WithKeys("s"),
+			key.WithHelp("s", "count levels and field values"),
+		),
+		Highlight: key.NewBinding(
+			key.WithKeys("*"),
+			key.WithHelp("*", "highlight matches"),
+		),
+		HighlightNext:  sequenceBinding("]h/[h", "next/prev highlight"),
+		ParseErrorNext: sequenceBinding("]e/[e", "next/prev malformed line"),
+		Marks:          sequenceBinding("m{a-z}/'{a-z}", "set/jump to mark"),
+		Position: key.NewBinding(
+			key.WithKeys("ctrl+g"),
+			key.WithHelp("ctrl+g", "show position"),
+		),
+		ResetView: key.NewBinding(
+			key.WithKeys("ctrl+r"),
+			key.WithHelp("ctrl+r", "reset view"),
+		),
+		Redraw: key.NewBinding(
+			key.WithKeys("ctrl+l"),
+			key.WithHelp("ctrl+l", "redraw screen"),
+		),
+		Reload: key.NewBinding(
+			key.WithKeys("R"),
+			key.WithHelp("R", "reload file from disk"),
+		),
 	}
 }
 
@@ -226,39 +653,101 @@ func (k KeyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
 		{k.Up, k.Down, k.VimUp, k.VimDown},
 		{k.PageUp, k.PageDown, k.Home, k.End},
-		{k.VimTop, k.VimBottom, k.Left, k.Right},
-		{k.ResizeMode, k.ResizeLeft, k.ResizeRight},
-		{k.Help, k.Quit},
+		{k.VimTop, k.VimBottom, k.Percent, k.Left, k.Right},
+		{k.ResizeMode, k.ResizeLeft, k.ResizeRight, k.ColumnMode},
+		{k.Search, k.SearchNext, k.Highlight, k.HighlightNext, k.ParseErrorNext},
+		{k.MinLevel, k.LevelToggle, k.RegexFilter, k.MsgLen, k.TableScroll},
+		{k.NoTruncate, k.DetailScroll, k.WrapDetail, k.LockDetail},
+		{k.DetailMode, k.RawDetail, k.ANSI, k.Context, k.Yank, k.DetailFocus},
+		{k.Sparkline, k.TimeZone, k.Frame, k.LevelTint, k.RelativeNumbers, k.Scrollbar, k.StackPanes, k.HideDetail},
+		{k.TOC, k.Stats, k.Command, k.ExportMatches, k.Visual, k.OpenSource, k.Pager},
+		{k.Marks, k.Position, k.ResetView, k.Redraw, k.Reload, k.Help, k.HelpPage, k.Quit},
 	}
 }
 
-// New creates a new TUI model with the given index and version.
-func New(idx *index.Index, version string) Model {
+// New creates a new TUI model over idx. Without options it has the
+// default columns, styles, and modes; see Option for what can be changed.
+func New(idx *index.Index, opts ...Option) Model {
 	// Default left pane width is 50% of screen
 	leftWidth := 80 // Will be adjusted on first window resize
 
 	m := Model{
-		idx:       idx,
-		parser:    parser.New(),
-		viewport:  nav.New(idx.LineCount(), 20),
-		leftWidth: leftWidth,
-		styles:    DefaultStyles(),
-		help:      help.New(),
-		version:   version,
-		keys:      DefaultKeyMap(),
+		idx:            idx,
+		parser:         parser.New(),
+		viewport:       nav.New(idx.LineCount(), 20),
+		leftWidth:      leftWidth,
+		columns:        defaultColumns(),
+		initialColumns: defaultColumns(),
+		showSparkline:  true,
+		styles:         DefaultStyles(BackgroundDark),
+		help:           help.New(),
+		version:        defaultVersion,
+		keys:           DefaultKeyMap(),
+		timeLayout:     displayTimeLayout,
+		copyText:       clipboard.WriteAll,
+		entries:        newEntryCache(DefaultEntryCacheSize),
 	}
 	m.help.ShowAll = true
+	// formatRow cuts each message to its column, so the parser keeps them
+	// whole for a scrolled or widened message column
+	m.parser.SetMaxMsgLen(0)
+	for _, opt := range opts {
+		opt(&m)
+	}
+	done, lines := idx.IndexProgress()
+	m.indexing, m.indexedLines = !done, lines
 	return m
 }
 
 // Init initializes the model.
 func (m *Model) Init() tea.Cmd {
-	return nil
+	cmds := []tea.Cmd{scanParseErrors(m.idx, m.parseErrs.gen, m.idx.LineCount(), m.parser.Format(), m.logger)}
+	if m.follow {
+		cmds = append(cmds, followTick(followMinInterval))
+	}
+	if m.indexing {
+		cmds = append(cmds, indexTick())
+	}
+	if cmd := m.histogramCmd(); cmd != nil {
+		cmds = append(cmds, cmd)
+	}
+	return tea.Batch(cmds...)
 }
 
 // Update handles messages and updates the model.
 func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
+	case followTickMsg:
+		return m, m.refreshFollow()
+
+	case indexTickMsg:
+		return m, m.refreshIndexing()
+
+	case regexScanDoneMsg:
+		m.regexScanDone(msg)
+
+	case highlightScanDoneMsg:
+		m.highlightScanDone(msg)
+
+	case parseErrScanDoneMsg:
+		m.parseErrScanDone(msg)
+
+	case statsScanDoneMsg:
+		m.statsScanDone(msg)
+
+	case histogramDoneMsg:
+		m.histogramDone(msg)
+
+	case editorDoneMsg:
+		if msg.err != nil {
+			m.statusMsg = fmt.Sprintf("editor failed: %v", msg.err)
+		}
+
+	case pagerDoneMsg:
+		if msg.err != nil {
+			m.statusMsg = fmt.Sprintf("pager failed: %v", msg.err)
+		}
+
 	case resizeTimeoutMsg:
 		// Only exit resize mode if the timeout has actually expired
 		if m.resizeMode && time.Since(m.resizeTimer) >= resizeTimeout {
@@ -268,16 +757,14 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
-		// Reserve 2 lines for header and 1 for status
-		contentHeight := msg.Height - 4 // App header + column headers + help + padding
-		if contentHeight < 1 {
-			contentHeight = 1
-		}
-		m.viewport.SetHeight(contentHeight)
+		m.layoutHeight()
 		// Left pane width is fixed to table content width (row + time + level + msg + spaces)
 		// 6 + 1 + 20 + 1 + 6 + 1 + 40 = 75, but we use a compact 74
-		m.leftWidth = 74
+		m.leftWidth = defaultLeftWidth
 		m.help.Width = msg.Width
+		if m.autoFields != nil {
+			m.applyAutoColumns()
+		}
 
 	case tea.KeyMsg:
 		return m.handleKey(msg)
@@ -304,64 +791,134 @@ func (m *Model) View() string {
 
 	// App header
 	title := m.styles.Title.Render("JSON Log Viewer")
-	info := m.styles.Help.Render(fmt.Sprintf(" %d lines | Line %d ", m.idx.LineCount(), m.viewport.Cursor))
-	b.WriteString(lipgloss.JoinHorizontal(lipgloss.Left, title, info))
+	info := m.styles.Help.Render(fmt.Sprintf(" %s | %s | Line %d ", m.lineCountState(), m.sizeState(), m.cursorLine()))
+	b.WriteString(lipgloss.JoinHorizontal(lipgloss.Left, title, info, m.renderSparkline()))
 	b.WriteString("\n")
 
 	// Use viewport height for consistent rendering
 	dataHeight := m.viewport.Height
 
+	// Reset detail offset when cursor changes to a different row,
+	// unless the detail scroll is locked; renderDetail clamps a kept offset
+	// to the new entry's length.
+	if line := m.cursorLine(); line != m.lastCursor {
+		if !m.lockDetailScroll {
+			m.detailOffset = 0
+			m.detailHOffset = 0
+		}
+		if m.resetHScroll {
+			m.hScroll = 0
+		}
+		m.lastCursor = line
+	}
+	// Settle the message scroll before the header shows it
+	if m.hScroll > 0 {
+		m.clampHScroll()
+	}
+
 	// Column headers (always visible)
 	tableHeader := m.renderTableHeader()
-	rightWidth := m.width - m.leftWidth - 3 // Account for separator and borders
-	// Detail pane header is empty (just alignment space)
-	detailHeader := m.styles.Detail.Width(rightWidth).Render("")
 	separator := m.styles.Separator.Render("│")
-	headerRow := lipgloss.JoinHorizontal(lipgloss.Top, tableHeader, separator, detailHeader)
-	b.WriteString(headerRow)
+	switch {
+	case m.hideDetail:
+		b.WriteString(tableHeader)
+	case m.stacked:
+		// The detail sits below the table, so only the table has a header
+		b.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, tableHeader, separator))
+	default:
+		// Detail pane header is empty (just alignment space)
+		detailHeader := m.styles.Detail.Width(m.detailWidth()).Render("")
+		b.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, tableHeader, separator, detailHeader))
+	}
 	b.WriteString("\n")
 
 	// Data rows (scrollable)
-	// Reset detail offset when cursor changes to a different row
-	if m.viewport.Cursor != m.lastCursor {
-		m.detailOffset = 0
-		m.lastCursor = m.viewport.Cursor
+	if m.showFrame {
+		b.WriteString(m.frameRule("┬"))
+		b.WriteString("\n")
 	}
 
 	// Build table and detail content with explicit line-by-line joining
-	tableLines := strings.Split(m.renderTable(), "\n")
-	detailLines := strings.Split(m.renderDetail(dataHeight), "\n")
-
-	// Ensure both have exactly dataHeight lines
-	for len(tableLines) < dataHeight {
-		tableLines = append(tableLines, strings.Repeat(" ", 74))
-	}
-	for len(detailLines) < dataHeight {
-		detailLines = append(detailLines, "")
-	}
-	if len(tableLines) > dataHeight {
-		tableLines = tableLines[:dataHeight]
-	}
-	if len(detailLines) > dataHeight {
-		detailLines = detailLines[:dataHeight]
-	}
-
-	// Join line by line
 	var dataRows []string
-	for i := 0; i < dataHeight; i++ {
-		dataRows = append(dataRows, tableLines[i]+"│"+detailLines[i])
+	switch {
+	case m.showTOC && m.stacked:
+		dataRows = m.renderTOC(m.contentHeight())
+	case m.showTOC:
+		dataRows = m.renderTOC(dataHeight)
+	case m.stats != nil && m.stacked:
+		dataRows = m.renderStats(m.contentHeight())
+	case m.stats != nil:
+		dataRows = m.renderStats(dataHeight)
+	case m.hideDetail:
+		dataRows = m.tableRows(dataHeight)
+	case m.stacked:
+		dataRows = m.stackedRows()
+	default:
+		dataRows = m.paneRows(dataHeight)
 	}
 	b.WriteString(strings.Join(dataRows, "\n"))
 	b.WriteString("\n")
 
+	if m.showFrame {
+		junction := "┴"
+		if m.stacked {
+			junction = "─"
+		}
+		b.WriteString(m.frameRule(junction))
+		b.WriteString("\n")
+	}
+
 	// Help, confirmation, or status line
 	if m.confirmExit {
 		prompt := m.styles.Title.Render(" Quit? (y/n) ")
 		b.WriteString(prompt)
+	} else if m.confirm != nil {
+		b.WriteString(m.styles.Title.Render(" " + m.confirm.question + " "))
+	} else if m.prompt != promptNone {
+		b.WriteString(m.renderPrompt())
+	} else if m.statusMsg != "" {
+		b.WriteString(m.styles.Help.Render(" " + m.statusMsg))
+	} else if m.showHelp && m.helpAbout {
+		b.WriteString(m.renderAbout())
 	} else if m.showHelp {
 		b.WriteString(m.help.View(m.keys))
+		if marks := m.marksState(); marks != "" {
+			b.WriteString("\n" + m.styles.Help.Render(marks))
+		}
+	} else if m.showTOC {
+		b.WriteString(m.styles.Help.Render(m.tocStatus()))
+	} else if m.stats != nil {
+		b.WriteString(m.styles.Help.Render(m.statsStatus()))
+	} else if m.columnMode {
+		status := " COLUMNS: h/l select | </> move | s sort (again to reverse) | S file order | Enter/Esc done"
+		b.WriteString(m.styles.Help.Render(status))
+	} else if m.detailFocus {
+		b.WriteString(m.styles.Help.Render(m.detailFocusStatus()))
+	} else if m.visual {
+		b.WriteString(m.styles.Help.Render(m.visualStatus()))
 	} else {
-		status := fmt.Sprintf(" F1: Help | q: Quit | %s | v%s", m.viewport.State(), m.version)
+		status := fmt.Sprintf(" F1: Help | q: Quit | %s | %s | v%s", m.viewport.State(), m.zoneName(), m.version)
+		if m.noTruncate {
+			status += " | " + m.detailHState()
+		}
+		if m.hScroll > 0 {
+			status += " | " + m.hScrollState()
+		}
+		if state := m.activeState(); state != "" {
+			status += " | " + state
+		}
+		if state := m.schemaState(); state != "" {
+			status += " | " + state
+		}
+		if state := m.parseErrState(); state != "" {
+			status += " | " + state
+		}
+		if m.follow {
+			status += " | FOLLOW"
+		}
+		if m.width > 0 {
+			status = ansi.Truncate(status, m.width, "…")
+		}
 		b.WriteString(m.styles.Help.Render(status))
 	}
 
@@ -386,6 +943,44 @@ func (m *Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 	}
 
+	if m.confirm != nil {
+		return m.handleConfirmKey(msg)
+	}
+
+	if m.prompt != promptNone {
+		return m.handlePromptKey(msg)
+	}
+
+	m.statusMsg = ""
+
+	if m.columnMode {
+		return m.handleColumnKey(msg)
+	}
+
+	if m.showTOC {
+		return m.handleTOCKey(msg)
+	}
+
+	if m.stats != nil {
+		return m.handleStatsKey(msg)
+	}
+
+	if m.detailFocus {
+		return m.handleDetailFocusKey(msg)
+	}
+
+	// Second key of a prefixed command
+	if m.pendingPrefix != "" {
+		prefix := m.pendingPrefix
+		m.pendingPrefix = ""
+		m.handlePrefixedKey(prefix + msg.String())
+		return m, nil
+	}
+
+	if m.visual && m.handleVisualKey(msg) {
+		return m, nil
+	}
+
 	switch msg.String() {
 	// Quit
 	case "q":
@@ -407,6 +1002,7 @@ func (m *Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	// Help
 	case "f1", "?":
 		m.showHelp = !m.showHelp
+		m.helpAbout = false
 		return m, nil
 
 	// Arrow navigation
@@ -423,12 +1019,12 @@ func (m *Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 	// Page navigation
 	case "pgup":
-		m.viewport.PageUp()
+		m.pageUp()
 		m.pendingNumber = ""
 		m.lastG = false
 		m.resizeMode = false
 	case "pgdown":
-		m.viewport.PageDown()
+		m.pageDown()
 		m.pendingNumber = ""
 		m.lastG = false
 		m.resizeMode = false
@@ -465,16 +1061,31 @@ func (m *Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		if m.pendingNumber != "" && !m.lastG {
 			var line int
 			if _, err := fmt.Sscanf(m.pendingNumber, "%d", &line); err == nil && line > 0 {
-				m.viewport.Goto(line)
+				m.gotoLine(line)
 			}
 			m.pendingNumber = ""
 		}
+	case "f":
+		// gf opens the entry's source location in the editor; f alone
+		// starts a level toggle such as f1
+		if m.lastG {
+			m.lastG = false
+			return m, m.openSourceFile()
+		}
+		m.pendingPrefix = "f"
+		m.pendingNumber = ""
+	case "p":
+		// gp hands the entry to the pager
+		if m.lastG {
+			m.lastG = false
+			return m, m.openInPager()
+		}
 	case "G":
 		// If we have a pending number, it's {n}G
 		if m.pendingNumber != "" {
 			var line int
 			if _, err := fmt.Sscanf(m.pendingNumber, "%d", &line); err == nil && line > 0 {
-				m.viewport.Goto(line)
+				m.gotoLine(line)
 			}
 			m.pendingNumber = ""
 		} else {
@@ -482,6 +1093,19 @@ func (m *Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		m.lastG = false
 		m.resizeMode = false
+	case "%":
+		// {n}% jumps to n percent of the way through the rows
+		if m.pendingNumber != "" {
+			var percent int
+			if _, err := fmt.Sscanf(m.pendingNumber, "%d", &percent); err == nil && percent >= 1 && percent <= 100 {
+				m.viewport.JumpToPercent(percent)
+			} else {
+				m.statusMsg = "percent must be 1-100"
+			}
+		}
+		m.pendingNumber = ""
+		m.lastG = false
+		m.resizeMode = false
 
 	// Scroll commands
 	case "ctrl+e":
@@ -493,11 +1117,11 @@ func (m *Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.lastG = false
 		m.resizeMode = false
 	case "ctrl+b":
-		m.viewport.PageUp()
+		m.pageUp()
 		m.lastG = false
 		m.resizeMode = false
 	case "ctrl+f":
-		m.viewport.PageDown()
+		m.pageDown()
 		m.lastG = false
 		m.resizeMode = false
 	case "ctrl+u":
@@ -546,6 +1170,139 @@ func (m *Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		m.lastG = false
 
+	// Time histogram
+	case "T":
+		m.showSparkline = !m.showSparkline
+		m.lastG = false
+		m.resizeMode = false
+		return m, m.histogramCmd()
+
+	// Frame around the data rows
+	case "B":
+		m.showFrame = !m.showFrame
+		m.layoutHeight()
+		m.lastG = false
+		m.resizeMode = false
+
+	// Relative row numbers
+	case "#":
+		m.relativeNumbers = !m.relativeNumbers
+		m.lastG = false
+		m.resizeMode = false
+
+	// Time zone
+	case "t":
+		m.displayLocal = !m.displayLocal
+		m.lastG = false
+		m.resizeMode = false
+
+	// Command line
+	case ":":
+		m.openPrompt(promptCommand)
+
+	// Search
+	case "/":
+		m.openPrompt(promptSearch)
+	case "w":
+		m.openExport()
+	case "n":
+		m.searchNext(1)
+	case "N":
+		m.searchNext(-1)
+
+	// Highlight rows matching a pattern
+	case "*":
+		m.openPrompt(promptHighlight)
+
+	// Filter rows by a regular expression
+	case "&":
+		m.openPrompt(promptFilter)
+	case "]", "[", "z", "m", "'":
+		m.pendingPrefix = msg.String()
+		m.pendingNumber = ""
+		m.lastG = false
+		m.resizeMode = false
+
+	// Visual line selection
+	case "V":
+		m.toggleVisual()
+
+	// Copy the cursor line's JSON
+	case "y":
+		m.yank(false)
+	case "Y":
+		m.yank(true)
+
+	// Field cursor in the detail pane
+	case "tab":
+		if m.showHelp {
+			m.toggleHelpPage()
+			return m, nil
+		}
+		m.toggleDetailFocus()
+		m.pendingNumber = ""
+		m.lastG = false
+		m.resizeMode = false
+
+	// Outline of errors or level changes
+	case "o":
+		m.openTOC()
+
+	// Level and field value counts
+	case "s":
+		m.openPrompt(promptStats)
+
+	// Column reorder
+	case "C":
+		m.columnMode = true
+		m.lastG = false
+		m.resizeMode = false
+
+	// Message length
+	case "{":
+		m.adjustMsgLen(-msgLenStep)
+	case "}":
+		m.adjustMsgLen(msgLenStep)
+
+	// Horizontal scroll of table messages
+	case "shift+left":
+		m.scrollTableH(-tableHScrollStep)
+	case "shift+right":
+		m.scrollTableH(tableHScrollStep)
+
+	// Minimum level filter
+	case "+":
+		m.adjustMinLevel(1)
+	case "-":
+		m.adjustMinLevel(-1)
+
+	// Context preview around the cursor
+	case "(":
+		m.adjustContext(-1)
+	case ")":
+		m.adjustContext(1)
+
+	// Position report, as in vim
+	case "ctrl+g":
+		m.showPosition()
+
+	// Reset view state
+	case "ctrl+r":
+		m.resetView()
+		return m, m.histogramCmd()
+
+	// Clear and repaint a garbled terminal; nothing else changes
+	case "ctrl+l":
+		m.lastG = false
+		m.resizeMode = false
+		return m, tea.ClearScreen
+
+	// Re-read a file that was replaced rather than appended to
+	case "R":
+		m.lastG = false
+		m.resizeMode = false
+		return m, m.reload()
+
 	// Detail pane scroll
 	case "h":
 		// Scroll detail up
@@ -571,6 +1328,123 @@ func (m *Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// handlePrefixedKey runs a two-key command such as ]h or zn.
+func (m *Model) handlePrefixedKey(keys string) {
+	switch prefix, rest := keys[:1], keys[1:]; prefix {
+	case "m":
+		m.setMark(rest)
+		return
+	case "'":
+		m.jumpMark(rest)
+		return
+	}
+
+	switch keys {
+	case "]h":
+		m.jumpHighlight(1)
+	case "[h":
+		m.jumpHighlight(-1)
+	case "]e":
+		m.jumpParseError(1)
+	case "[e":
+		m.jumpParseError(-1)
+	case "zn":
+		m.SetNoTruncate(!m.noTruncate)
+		if m.noTruncate {
+			m.statusMsg = "no-truncate: zh/zl scroll the detail pane"
+		} else {
+			m.statusMsg = "detail lines truncated to fit"
+		}
+	case "zw":
+		m.SetWrapDetail(!m.wrapDetail)
+		if m.wrapDetail {
+			m.statusMsg = "wrap: long detail lines fold onto indented lines"
+		} else {
+			m.statusMsg = "detail lines truncated to fit"
+		}
+	case "zs":
+		m.lockDetailScroll = !m.lockDetailScroll
+		if m.lockDetailScroll {
+			m.statusMsg = "detail scroll locked across rows"
+		} else {
+			m.statusMsg = "detail scroll resets on each row"
+		}
+	case "zv":
+		m.cycleDetailMode()
+	case "zr":
+		m.showRaw = !m.showRaw
+		m.detailOffset = 0
+	case "za":
+		m.stripDetailANSI = !m.stripDetailANSI
+		if m.stripDetailANSI {
+			m.statusMsg = "detail: ANSI codes stripped"
+		} else {
+			m.statusMsg = "detail: ANSI colors rendered"
+		}
+	case "zb":
+		m.levelTint = !m.levelTint
+	case "zm":
+		m.showScrollbar = !m.showScrollbar
+	case "zp":
+		m.toggleStacked()
+	case "zd":
+		m.toggleHideDetail()
+	case "f1", "f2", "f3", "f4", "f5":
+		m.toggleLevel(int(keys[1] - '1'))
+	case "f0":
+		m.clearFilters()
+		m.statusMsg = "filters cleared"
+	case "zh":
+		m.scrollDetailH(-detailHScrollStep)
+	case "zl":
+		m.scrollDetailH(detailHScrollStep)
+	}
+}
+
+// resetView returns every display mode to its default without moving the
+// cursor: highlights are cleared, columns return to their default order,
+// and pane sizes and toggles are restored.
+func (m *Model) resetView() {
+	m.columns = slices.Clone(m.initialColumns)
+	m.columnMode = false
+	m.selectedColumn = 0
+	m.showSparkline = true
+	m.displayLocal = false
+	m.showFrame = false
+	m.showScrollbar = false
+	m.relativeNumbers = false
+	m.layoutHeight()
+	m.clearHighlight()
+	m.clearSearch()
+	m.detailOffset = 0
+	m.detailHOffset = 0
+	m.hScroll = 0
+	m.noTruncate = false
+	m.wrapDetail = false
+	m.lockDetailScroll = false
+	m.contextLines = 0
+	m.levelTint = false
+	m.showRaw = false
+	m.stripDetailANSI = false
+	m.detailFocus = false
+	m.visual = false
+	m.detailMode = detailPretty
+	m.treeFolds = nil
+	m.showTOC = false
+	m.tocMode = tocErrors
+	m.stats = nil
+	m.sorted = nil
+	m.clearFilters()
+	m.pendingNumber = ""
+	m.pendingPrefix = ""
+	m.lastG = false
+	m.resizeMode = false
+	if m.width > 0 {
+		m.leftWidth = defaultLeftWidth
+	}
+	m.statusMsg = "view reset to defaults"
+}
+
 // enterResizeMode activates resize mode and starts the timeout timer.
 func (m *Model) enterResizeMode() (tea.Model, tea.Cmd) {
 	m.resizeMode = true
@@ -599,58 +1473,87 @@ func (m *Model) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
 			m.viewport.ScrollDown(3)
 		}
 	}
+	if msg.Action == tea.MouseActionPress && msg.Button == tea.MouseButtonLeft {
+		if row, ok := m.tableRowAt(msg.X, msg.Y); ok {
+			m.viewport.ClickAt(row)
+		}
+	}
 	return m, nil
 }
 
+// tableRowAt maps a screen position to a data row of the table, relative
+// to the top of the viewport. ok is false for the header lines, the frame,
+// the detail pane, the status line, blank rows past the last line, and
+// while the outline or stats overlay replaces the panes.
+func (m *Model) tableRowAt(x, y int) (row int, ok bool) {
+	if m.showTOC || m.stats != nil || x >= m.tableWidth() {
+		return 0, false
+	}
+	// The app header and column headers sit above the rows
+	row = y - 2
+	if m.showFrame {
+		row--
+	}
+	if row < 0 || row >= m.viewport.Height || m.viewport.Offset+row > m.rowCount() {
+		return 0, false
+	}
+	return row, true
+}
+
 // renderTable renders the left pane table view.
 // The header is always shown at the top, data rows scroll underneath.
 func (m *Model) renderTable() string {
 	if m.idx.LineCount() == 0 {
 		return m.styles.Normal.Render("No data")
 	}
+	if m.rowCount() == 0 {
+		return m.styles.Normal.Render("No matching lines")
+	}
 
-	// Calculate column widths - use fixed compact widths
-	rowNumWidth := 6
-	timeWidth := 20
-	levelWidth := 6
-	msgWidth := 40 // Fixed message column width
+	tableWidth := m.tableWidth()
 
-	// Calculate total table width (columns + spaces between them)
-	tableWidth := rowNumWidth + 1 + timeWidth + 1 + levelWidth + 1 + msgWidth
+	// A new page may have shorter messages than the scroll allows
+	if m.hScroll > 0 {
+		m.clampHScroll()
+	}
 
 	// Build data rows only (header is rendered separately in View)
+	// One entry is reused for every visible row to keep rendering
+	// allocation-free on the parse side
 	start, end := m.viewport.VisibleRange()
+	end = min(end, m.rowCount())
+	lines, err := m.rowLines(start, end)
+	if err != nil {
+		return m.styles.Normal.Render(fmt.Sprintf("Error: %v", err))
+	}
 	var rows []string
-	for i := start; i <= end && i <= m.idx.LineCount(); i++ {
-		line, err := m.idx.GetLine(i)
-		if err != nil {
-			continue
-		}
-
-		entry, err := m.parser.Parse(line, i)
-		if err != nil {
-			continue
+	var entry parser.LogEntry
+	for pos := start; pos <= end; pos++ {
+		i := m.lineAt(pos)
+		line := lines[pos-start]
+
+		// A line that isn't JSON keeps its row, with its text (or why it
+		// failed, when it's empty) in place of the message
+		malformed := false
+		if err := m.parseLine(line, i, &entry); err != nil {
+			entry = parser.LogEntry{Raw: line, Msg: string(line)}
+			if len(line) == 0 {
+				entry.Msg = err.Error()
+			}
+			malformed = true
 		}
 
-		// Format row with compact columns
-		rowStr := fmt.Sprintf("%*d %-*s %-*s %s",
-			rowNumWidth, entry.Row,
-			timeWidth, truncate(entry.Time, timeWidth),
-			levelWidth, parser.ShortenLevel(entry.Level),
-			truncate(entry.Msg, msgWidth))
+		// Row is only displayed from here on, so it can carry the
+		// relative number in place of the line number
+		entry.Row = m.rowNumber(pos, i)
 
-		var styled string
-		if i == m.viewport.Cursor {
-			styled = m.styles.Selected.Width(tableWidth).Render(rowStr)
-		} else {
-			// Apply level color
-			style := m.styles.Normal
-			if color := parser.LevelColor(entry.Level); color != "" {
-				style = style.Foreground(lipgloss.Color(color))
-			}
-			styled = style.Width(tableWidth).Render(rowStr)
+		// Fit before styling so an over-wide row can't wrap onto a second line
+		rowStr := fitWidth(m.formatRowAt(pos, &entry), tableWidth)
+		style := m.rowStyle(i, &entry)
+		if malformed && i != m.cursorLine() && !m.inSelection(i) {
+			style = m.styles.ParseError
 		}
-		rows = append(rows, styled)
+		rows = append(rows, style.Width(tableWidth).Render(rowStr))
 	}
 
 	// Pad with empty rows to maintain consistent height
@@ -662,21 +1565,56 @@ func (m *Model) renderTable() string {
 	return lipgloss.JoinVertical(lipgloss.Left, rows...)
 }
 
+// rowLines returns the raw lines shown in rows start through end. Without
+// filters the rows are a contiguous run of lines, read in one batch.
+func (m *Model) rowLines(start, end int) ([][]byte, error) {
+	if m.visible == nil {
+		return m.idx.GetLines(start, end)
+	}
+	lines := make([][]byte, 0, end-start+1)
+	for pos := start; pos <= end; pos++ {
+		line, err := m.idx.GetLine(m.lineAt(pos))
+		if err != nil {
+			return nil, err
+		}
+		lines = append(lines, line)
+	}
+	return lines, nil
+}
+
+// rowStyle returns the style for the table row showing line n.
+// The cursor row takes precedence, then rows in the visual selection, then
+// rows matching the search, then highlighted rows, then the level color
+// with its background tint when enabled.
+func (m *Model) rowStyle(n int, entry *parser.LogEntry) lipgloss.Style {
+	if n == m.cursorLine() {
+		return m.styles.Selected
+	}
+	if m.inSelection(n) {
+		return m.styles.Visual
+	}
+	if m.isSearchMatch(n) {
+		return m.styles.Match
+	}
+	if m.isHighlighted(n) {
+		return m.styles.Highlight
+	}
+	style := m.styles.Normal
+	if color := m.levelColor(entry.Level); color != "" {
+		style = style.Foreground(lipgloss.Color(color))
+	}
+	if m.levelTint {
+		if tint := m.levelTintColor(entry.Level); tint != "" {
+			style = style.Background(lipgloss.Color(tint))
+		}
+	}
+	return style
+}
+
 // renderTableHeader renders the table header row.
 func (m *Model) renderTableHeader() string {
-	rowNumWidth := 6
-	timeWidth := 20
-	levelWidth := 6
-	msgWidth := 40
-	tableWidth := rowNumWidth + 1 + timeWidth + 1 + levelWidth + 1 + msgWidth
-
-	return m.styles.Header.Width(tableWidth).Render(
-		fmt.Sprintf("%*s %-*s %-*s %s",
-			rowNumWidth, "Row",
-			timeWidth, "Time",
-			levelWidth, "Lvl",
-			"Message"),
-	)
+	tableWidth := m.tableWidth()
+	return m.styles.Header.Width(tableWidth).Render(fitWidth(m.formatHeader(), tableWidth))
 }
 
 // renderDetail renders the right pane detail view.
@@ -685,19 +1623,21 @@ func (m *Model) renderDetail(height int) string {
 		return m.styles.Normal.Render("No selection")
 	}
 
-	line, err := m.idx.GetLine(m.viewport.Cursor)
-	if err != nil {
-		return m.styles.Normal.Render(fmt.Sprintf("Error: %v", err))
+	if m.rowCount() == 0 {
+		return m.styles.Normal.Render("No matching lines")
 	}
 
-	formatted, err := m.parser.FormatPretty(line)
+	line, err := m.idx.GetLine(m.cursorLine())
 	if err != nil {
-		// Show raw if formatting fails
-		formatted = string(line)
+		return m.styles.Normal.Render(fmt.Sprintf("Error: %v", err))
 	}
 
-	// Split into lines and apply scroll offset
-	lines := strings.Split(formatted, "\n")
+	// Lay out the entry and apply scroll offset
+	lines, fields := m.detailLines(line, m.cursorLine())
+	focus := -1
+	if m.detailFocus && m.fieldCursor < len(fields) {
+		focus = fields[m.fieldCursor]
+	}
 	totalLines := len(lines)
 
 	// Clamp offset to valid range
@@ -708,28 +1648,83 @@ func (m *Model) renderDetail(height int) string {
 		m.detailOffset = 0
 	}
 
+	// The context preview takes the bottom of the pane, leaving at least
+	// one line for the detail itself
+	context := m.renderContext()
+	if len(context) > height-1 {
+		context = context[:max(height-1, 0)]
+	}
+	detailHeight := height - len(context)
+
+	// Keep the selected field in view
+	if focus >= 0 {
+		if focus < m.detailOffset {
+			m.detailOffset = focus
+		} else if focus >= m.detailOffset+detailHeight {
+			m.detailOffset = focus - detailHeight + 1
+		}
+	}
+
 	// Show visible portion starting from offset
 	visibleLines := lines[m.detailOffset:]
-	if len(visibleLines) > height {
-		visibleLines = visibleLines[:height]
+	if len(visibleLines) > detailHeight {
+		visibleLines = visibleLines[:detailHeight]
+	}
+
+	// Fit each line to the pane by display width so wide terminals show
+	// as much of each value as fits and nothing spills past the edge.
+	// In no-truncate mode the pane is a window onto the full lines instead.
+	if width := m.detailWidth(); width > 0 {
+		if m.noTruncate {
+			m.detailMaxWidth = maxLineWidth(lines)
+			m.clampDetailHOffset()
+			for i, line := range visibleLines {
+				visibleLines[i] = ansi.Cut(line, m.detailHOffset, m.detailHOffset+width)
+			}
+		} else {
+			for i, line := range visibleLines {
+				visibleLines[i] = ansi.Truncate(line, width, "...")
+			}
+		}
 	}
 
 	// Pad with empty lines to ensure consistent height
-	for len(visibleLines) < height {
+	for len(visibleLines) < detailHeight {
 		visibleLines = append(visibleLines, "")
 	}
+	visibleLines = append(visibleLines, context...)
 
 	content := strings.Join(visibleLines, "\n")
 	return content
 }
 
-// truncate truncates a string to the given length.
+// detailWidth returns the display width available to the detail pane:
+// whatever the terminal has left after the table and the separator, or
+// the whole width when the panes are stacked. Returns 0 before the
+// terminal size is known or while the pane is hidden.
+func (m *Model) detailWidth() int {
+	if m.width == 0 || m.hideDetail {
+		return 0
+	}
+	if m.stacked {
+		return m.width
+	}
+	width := m.width - m.tableWidth() - 1
+	if width < 1 {
+		width = 1
+	}
+	return width
+}
+
+// truncate cuts s to at most maxLen display cells, ending in "..." when
+// there's room for it. Wide characters such as CJK and emoji take two
+// cells and are never split, so the result may be a cell short.
 func truncate(s string, maxLen int) string {
-	if len(s) <= maxLen {
+	if ansi.StringWidth(s) <= maxLen {
 		return s
 	}
 	if maxLen <= 3 {
-		return s[:maxLen]
+		return ansi.Truncate(s, maxLen, "")
 	}
-	return s[:maxLen-3] + "..."
+	return ansi.Truncate(s, maxLen, "...")
 }
@@ -1,15 +1,19 @@
 package tui
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 	"github.com/lbe/jsonlogviewer/internal/index"
 )
 
 // createTestIndex creates a test index with sample log data.
-func createTestIndex(t *testing.T, content string) *index.Index {
+func createTestIndex(t testing.TB, content string) *index.Index {
 	t.Helper()
 	r := strings.NewReader(content)
 	idx, err := index.OpenReader(r, "test")
@@ -24,6 +28,24 @@ func closeIndex(idx *index.Index) {
 	_ = idx.Close()
 }
 
+// sendKeys feeds each rune of keys to the model as a key press.
+func sendKeys(m *Model, keys string) {
+	for _, r := range keys {
+		var msg tea.KeyMsg
+		switch r {
+		case '\n':
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		case '\t':
+			msg = tea.KeyMsg{Type: tea.KeyTab}
+		case ' ':
+			msg = tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}}
+		default:
+			msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}}
+		}
+		m.Update(msg)
+	}
+}
+
 // TestNew verifies model initialization.
 func TestNew(t *testing.T) {
 	content := `{"time":"2024-01-01T00:00:00Z","level":"info","msg":"test1"}
@@ -31,7 +53,7 @@ func TestNew(t *testing.T) {
 	idx := createTestIndex(t, content)
 	defer closeIndex(idx)
 
-	m := New(idx, "test")
+	m := New(idx)
 
 	if m.idx != idx {
 		t.Error("index not set correctly")
@@ -47,17 +69,27 @@ func TestNew(t *testing.T) {
 	}
 }
 
-// TestInit verifies the Init method.
+// TestInit verifies the Init method starts only the parse error scan.
 func TestInit(t *testing.T) {
 	content := `{"time":"2024-01-01T00:00:00Z","level":"info","msg":"test"}`
 	idx := createTestIndex(t, content)
 	defer closeIndex(idx)
 
-	m := New(idx, "test")
+	m := New(idx)
 	cmd := m.Init()
 
-	if cmd != nil {
-		t.Error("expected nil command from Init")
+	if cmd == nil {
+		t.Fatal("expected the parse error scan from Init")
+	}
+	batch, ok := cmd().(tea.BatchMsg)
+	if !ok || len(batch) != 2 {
+		t.Fatalf("expected Init to start two scans, got %#v", batch)
+	}
+	if _, ok := batch[0]().(parseErrScanDoneMsg); !ok {
+		t.Error("expected the parse error scan first")
+	}
+	if _, ok := batch[1]().(histogramDoneMsg); !ok {
+		t.Error("expected the histogram build second")
 	}
 }
 
@@ -67,7 +99,7 @@ func TestUpdateWindowSize(t *testing.T) {
 	idx := createTestIndex(t, content)
 	defer closeIndex(idx)
 
-	m := New(idx, "test")
+	m := New(idx)
 	msg := tea.WindowSizeMsg{Width: 120, Height: 40}
 
 	newM, cmd := m.Update(msg)
@@ -97,7 +129,7 @@ func TestHandleKeyQuit(t *testing.T) {
 	idx := createTestIndex(t, content)
 	defer closeIndex(idx)
 
-	m := New(idx, "test")
+	m := New(idx)
 	m.width = 80
 	m.height = 24
 
@@ -120,7 +152,7 @@ func TestHandleKeyEscConfirmation(t *testing.T) {
 	idx := createTestIndex(t, content)
 	defer closeIndex(idx)
 
-	m := New(idx, "test")
+	m := New(idx)
 	m.width = 80
 	m.height = 24
 
@@ -155,7 +187,7 @@ func TestHandleKeyEscCancel(t *testing.T) {
 	idx := createTestIndex(t, content)
 	defer closeIndex(idx)
 
-	m := New(idx, "test")
+	m := New(idx)
 	m.width = 80
 	m.height = 24
 	m.confirmExit = true
@@ -182,7 +214,7 @@ func TestHandleKeyEscFromHelp(t *testing.T) {
 	idx := createTestIndex(t, content)
 	defer closeIndex(idx)
 
-	m := New(idx, "test")
+	m := New(idx)
 	m.width = 80
 	m.height = 24
 	m.showHelp = true
@@ -209,7 +241,7 @@ func TestHandleKeyNavigation(t *testing.T) {
 	idx := createTestIndex(t, content)
 	defer closeIndex(idx)
 
-	m := New(idx, "test")
+	m := New(idx)
 	m.width = 120
 	m.height = 30
 
@@ -247,7 +279,7 @@ func TestHandleKeyGoto(t *testing.T) {
 	idx := createTestIndex(t, content)
 	defer closeIndex(idx)
 
-	m := New(idx, "test")
+	m := New(idx)
 	m.width = 120
 	m.height = 30
 
@@ -279,7 +311,7 @@ func TestHandleKeyHelp(t *testing.T) {
 	idx := createTestIndex(t, content)
 	defer closeIndex(idx)
 
-	m := New(idx, "test")
+	m := New(idx)
 	m.width = 80
 	m.height = 24
 
@@ -302,13 +334,47 @@ func TestHandleKeyHelp(t *testing.T) {
 	}
 }
 
+// TestHelpAboutPage verifies Tab in the help overlay switches to the about
+// page with the version, build, and source details, and back, without
+// focusing the detail pane.
+func TestHelpAboutPage(t *testing.T) {
+	content := `{"time":"2024-01-01T00:00:00Z","level":"info","msg":"test"}
+{"time":"2024-01-01T00:00:01Z","level":"info","msg":"test"}`
+	idx := createTestIndex(t, content)
+	defer closeIndex(idx)
+
+	m := New(idx, WithVersion("1.2.3"), WithBuildInfo("go1.99, built today"))
+	m.Update(tea.WindowSizeMsg{Width: 120, Height: 30})
+
+	sendKeys(&m, "?\t")
+	if m.detailFocus {
+		t.Error("expected Tab in help not to focus the detail pane")
+	}
+	view := m.View()
+	for _, want := range []string{"jsonlogviewer 1.2.3", "go1.99, built today", idx.Name(), "Lines", " 2", "Indexed in"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("expected the about page to show %q", want)
+		}
+	}
+
+	sendKeys(&m, "\t")
+	if strings.Contains(m.View(), "go1.99") {
+		t.Error("expected Tab to switch back to the key bindings")
+	}
+
+	sendKeys(&m, "\t??")
+	if !m.showHelp || m.helpAbout {
+		t.Error("expected reopening help to start on the key bindings")
+	}
+}
+
 // TestHandleKeyPaneResize verifies pane resize keys (Ctrl+w enters resize mode, then multiple >/< work).
 func TestHandleKeyPaneResize(t *testing.T) {
 	content := `{"time":"2024-01-01T00:00:00Z","level":"info","msg":"test"}`
 	idx := createTestIndex(t, content)
 	defer closeIndex(idx)
 
-	m := New(idx, "test")
+	m := New(idx)
 	m.width = 120
 	m.height = 24
 	m.leftWidth = 60
@@ -386,7 +452,7 @@ func TestHandleMouse(t *testing.T) {
 	idx := createTestIndex(t, content)
 	defer closeIndex(idx)
 
-	m := New(idx, "test")
+	m := New(idx)
 	m.width = 120
 	m.height = 30
 
@@ -401,13 +467,69 @@ func TestHandleMouse(t *testing.T) {
 	m = *newM.(*Model)
 }
 
+// TestClickRow verifies a left click on a table row moves the cursor there,
+// allowing for the header lines and the frame, and that clicks outside the
+// table's rows are ignored.
+func TestClickRow(t *testing.T) {
+	content := ""
+	for i := 0; i < 50; i++ {
+		content += `{"time":"2024-01-01T00:00:00Z","level":"info","msg":"test"}` + "\n"
+	}
+	idx := createTestIndex(t, content)
+	defer closeIndex(idx)
+
+	m := New(idx)
+	m.Update(tea.WindowSizeMsg{Width: 160, Height: 30})
+	m.viewport.Goto(20)
+	offset := m.viewport.Offset
+
+	tests := []struct {
+		name  string
+		frame bool
+		x, y  int
+		want  int
+	}{
+		{"first row", false, 5, 2, offset},
+		{"fifth row", false, 5, 6, offset + 4},
+		{"app header", false, 5, 0, 20},
+		{"column header", false, 5, 1, 20},
+		{"detail pane", false, m.tableWidth() + 3, 6, 20},
+		{"status line", false, 5, 29, 20},
+		{"frame rule", true, 5, 2, 20},
+		{"first row under frame", true, 5, 3, offset},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m.showFrame = tt.frame
+			m.layoutHeight()
+			m.viewport.Goto(20)
+			m.viewport.Offset = offset
+			m.Update(tea.MouseMsg{X: tt.x, Y: tt.y, Action: tea.MouseActionPress, Button: tea.MouseButtonLeft})
+			if m.viewport.Cursor != tt.want {
+				t.Errorf("click at (%d,%d): expected cursor %d, got %d", tt.x, tt.y, tt.want, m.viewport.Cursor)
+			}
+		})
+	}
+
+	// Blank rows below the last line don't select it
+	short := createTestIndex(t, `{"msg":"a"}`+"\n"+`{"msg":"b"}`)
+	defer closeIndex(short)
+	m = New(short)
+	m.Update(tea.WindowSizeMsg{Width: 160, Height: 30})
+	m.Update(tea.MouseMsg{X: 5, Y: 10, Action: tea.MouseActionPress, Button: tea.MouseButtonLeft})
+	if m.viewport.Cursor != 1 {
+		t.Errorf("expected a click below the rows to leave the cursor on 1, got %d", m.viewport.Cursor)
+	}
+}
+
 // TestView verifies the view renders without error.
 func TestView(t *testing.T) {
 	content := `{"time":"2024-01-01T00:00:00Z","level":"info","msg":"test message"}`
 	idx := createTestIndex(t, content)
 	defer closeIndex(idx)
 
-	m := New(idx, "test")
+	m := New(idx)
 	m.width = 120
 	m.height = 30
 
@@ -428,7 +550,7 @@ func TestViewQuitting(t *testing.T) {
 	idx := createTestIndex(t, content)
 	defer closeIndex(idx)
 
-	m := New(idx, "test")
+	m := New(idx)
 	m.quitting = true
 
 	view := m.View()
@@ -443,7 +565,7 @@ func TestViewConfirmation(t *testing.T) {
 	idx := createTestIndex(t, content)
 	defer closeIndex(idx)
 
-	m := New(idx, "test")
+	m := New(idx)
 	m.width = 120
 	m.height = 30
 	m.confirmExit = true
@@ -460,7 +582,7 @@ func TestViewHelp(t *testing.T) {
 	idx := createTestIndex(t, content)
 	defer closeIndex(idx)
 
-	m := New(idx, "test")
+	m := New(idx)
 	m.width = 120
 	m.height = 30
 	m.showHelp = true
@@ -473,7 +595,7 @@ func TestViewHelp(t *testing.T) {
 
 // TestDefaultStyles verifies styles are created.
 func TestDefaultStyles(t *testing.T) {
-	styles := DefaultStyles()
+	styles := DefaultStyles(BackgroundDark)
 
 	// Verify styles are properly initialized
 	if styles.Header.GetBold() != true {
@@ -500,6 +622,17 @@ func TestDefaultKeyMap(t *testing.T) {
 	if km.Down.Keys() == nil {
 		t.Error("Down binding has no keys")
 	}
+
+	// Multi-key commands are listed in help but never match their prefix
+	for _, b := range []key.Binding{km.VimTop, km.NoTruncate, km.LevelToggle, km.HighlightNext, km.Marks} {
+		if !b.Enabled() {
+			t.Errorf("%s: expected the binding shown in help", b.Help().Key)
+		}
+		prefix := []rune(b.Help().Key)[0]
+		if key.Matches(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{prefix}}, b) {
+			t.Errorf("%s: expected %q alone not to match", b.Help().Key, prefix)
+		}
+	}
 }
 
 // TestKeyMapHelp verifies the help interface.
@@ -530,6 +663,11 @@ func TestTruncate(t *testing.T) {
 		{"longer text here", 5, "lo..."},
 		{"ab", 2, "ab"},
 		{"abc", 5, "abc"},
+		{"日本語テキスト", 14, "日本語テキスト"},
+		{"日本語テキスト", 9, "日本語..."},
+		{"日本語テキスト", 8, "日本..."},
+		{"🔥 fire 🔥 fire", 10, "🔥 fire..."},
+		{"日本語", 3, "日"},
 	}
 
 	for _, tt := range tests {
@@ -543,13 +681,47 @@ func TestTruncate(t *testing.T) {
 	}
 }
 
+// TestRenderTableWide verifies CJK and emoji messages are cut and padded
+// by display width, so a column after the message starts in the same cell
+// on every row.
+func TestRenderTableWide(t *testing.T) {
+	content := `{"level":"info","msg":"ascii message"}
+{"level":"info","msg":"データベース接続がタイムアウトしました。再試行しています。再試行しています。"}
+{"level":"warn","msg":"🔥🔥 disk almost full 🔥🔥 on the primary volume of the cluster"}
+{"level":"info","msg":"短い"}`
+	idx := createTestIndex(t, content)
+	defer closeIndex(idx)
+
+	m := New(idx)
+	if err := m.SetColumns("msg,level"); err != nil {
+		t.Fatalf("SetColumns failed: %v", err)
+	}
+	m.Update(tea.WindowSizeMsg{Width: 160, Height: 20})
+
+	want := -1
+	for i, row := range strings.Split(m.renderTable(), "\n")[:4] {
+		plain := ansi.Strip(row)
+		at := max(strings.LastIndex(plain, "INF"), strings.LastIndex(plain, "WRN"))
+		if at < 0 {
+			t.Fatalf("row %d: no level in %q", i+1, plain)
+		}
+		col := ansi.StringWidth(plain[:at])
+		if want < 0 {
+			want = col
+		}
+		if col != want {
+			t.Errorf("row %d: expected the level at cell %d, got %d: %q", i+1, want, col, plain)
+		}
+	}
+}
+
 // TestRenderTable verifies table rendering.
 func TestRenderTable(t *testing.T) {
 	content := `{"time":"2024-01-01T00:00:00Z","level":"info","msg":"test message"}`
 	idx := createTestIndex(t, content)
 	defer closeIndex(idx)
 
-	m := New(idx, "test")
+	m := New(idx)
 	m.width = 120
 	m.height = 30
 
@@ -564,13 +736,43 @@ func TestRenderTable(t *testing.T) {
 	}
 }
 
+// TestRenderTableTabs verifies tabs in cell text don't break column alignment.
+func TestRenderTableTabs(t *testing.T) {
+	content := `{"time":"2024-01-01T00:00:00Z","level":"info","msg":"plain message"}
+{"time":"2024-01-01T00:00:01Z","level":"in\tfo","msg":"tab\tseparated\tmessage"}`
+	idx := createTestIndex(t, content)
+	defer closeIndex(idx)
+
+	m := New(idx)
+	m.width = 120
+	m.height = 30
+
+	lines := strings.Split(m.renderTable(), "\n")
+	if len(lines) < 2 {
+		t.Fatalf("expected at least 2 rows, got %d", len(lines))
+	}
+	if strings.Contains(lines[1], "\t") {
+		t.Error("rendered row should not contain a literal tab")
+	}
+	if !strings.Contains(lines[1], "tab separated message") {
+		t.Errorf("expected tabs replaced with spaces, got %q", lines[1])
+	}
+	if lipgloss.Width(lines[0]) != lipgloss.Width(lines[1]) {
+		t.Errorf("row widths differ: %d vs %d", lipgloss.Width(lines[0]), lipgloss.Width(lines[1]))
+	}
+	// The message column must start at the same position in both rows.
+	if strings.Index(lines[0], "plain") != strings.Index(lines[1], "tab separated") {
+		t.Errorf("message columns misaligned:\n%q\n%q", lines[0], lines[1])
+	}
+}
+
 // TestRenderDetail verifies detail pane rendering.
 func TestRenderDetail(t *testing.T) {
 	content := `{"time":"2024-01-01T00:00:00Z","level":"info","msg":"test message"}`
 	idx := createTestIndex(t, content)
 	defer closeIndex(idx)
 
-	m := New(idx, "test")
+	m := New(idx)
 	m.width = 120
 	m.height = 30
 
@@ -583,13 +785,50 @@ func TestRenderDetail(t *testing.T) {
 	}
 }
 
+// TestRenderDetailWide verifies the detail pane uses the full width of a wide
+// terminal and truncates by display width on a narrow one.
+func TestRenderDetailWide(t *testing.T) {
+	longValue := strings.Repeat("x", 100)
+	content := `{"time":"2024-01-01T00:00:00Z","level":"info","msg":"test","payload":"` + longValue + `"}`
+	idx := createTestIndex(t, content)
+	defer closeIndex(idx)
+
+	m := New(idx)
+	m.width = 200
+	m.height = 30
+
+	if got, want := m.detailWidth(), 200-m.tableWidth()-1; got != want {
+		t.Errorf("detailWidth: expected %d, got %d", want, got)
+	}
+	detail := m.renderDetail(10)
+	if !strings.Contains(detail, longValue) {
+		t.Error("expected the full value to fit in a 200-column terminal")
+	}
+	for _, line := range strings.Split(m.View(), "\n") {
+		if w := lipgloss.Width(line); w > m.width {
+			t.Errorf("view line exceeds terminal width (%d > %d): %q", w, m.width, line)
+		}
+	}
+
+	// At 120 columns the same line is cut to fit the narrower pane
+	m.width = 120
+	for _, line := range strings.Split(m.renderDetail(10), "\n") {
+		if w := lipgloss.Width(line); w > m.detailWidth() {
+			t.Errorf("detail line exceeds pane width (%d > %d): %q", w, m.detailWidth(), line)
+		}
+	}
+	if !strings.Contains(m.renderDetail(10), "...") {
+		t.Error("expected truncated detail line to end with ...")
+	}
+}
+
 // TestViewLoading verifies loading state view.
 func TestViewLoading(t *testing.T) {
 	content := `{"time":"2024-01-01T00:00:00Z","level":"info","msg":"test"}`
 	idx := createTestIndex(t, content)
 	defer closeIndex(idx)
 
-	m := New(idx, "test")
+	m := New(idx)
 	// width and height are 0, so should show loading
 
 	view := m.View()
@@ -607,7 +846,7 @@ func TestIntegration(t *testing.T) {
 	idx := createTestIndex(t, content)
 	defer closeIndex(idx)
 
-	m := New(idx, "test")
+	m := New(idx)
 	m.width = 120
 	m.height = 24
 
@@ -638,7 +877,7 @@ func TestDetailScroll(t *testing.T) {
 	idx := createTestIndex(t, content)
 	defer closeIndex(idx)
 
-	m := New(idx, "test")
+	m := New(idx)
 	m.width = 120
 	m.height = 30
 
@@ -671,7 +910,7 @@ func TestNumberPrefix(t *testing.T) {
 	idx := createTestIndex(t, content)
 	defer closeIndex(idx)
 
-	m := New(idx, "test")
+	m := New(idx)
 	m.width = 120
 	m.height = 30
 
@@ -693,6 +932,44 @@ func TestNumberPrefix(t *testing.T) {
 	}
 }
 
+// TestPercentJump verifies {n}% jumps to n percent of the file and that
+// values outside 1-100 are rejected without moving the cursor.
+func TestPercentJump(t *testing.T) {
+	content := ""
+	for i := 0; i < 200; i++ {
+		content += `{"time":"2024-01-01T00:00:00Z","level":"info","msg":"test"}` + "\n"
+	}
+	idx := createTestIndex(t, content)
+	defer closeIndex(idx)
+
+	m := New(idx)
+	m.width = 120
+	m.height = 30
+
+	sendKeys(&m, "50%")
+	if m.viewport.Cursor != 100 {
+		t.Errorf("expected cursor at 100 after 50%%, got %d", m.viewport.Cursor)
+	}
+	if m.pendingNumber != "" {
+		t.Errorf("expected the count consumed, got %q", m.pendingNumber)
+	}
+
+	sendKeys(&m, "100%")
+	if m.viewport.Cursor != 200 {
+		t.Errorf("expected cursor at 200 after 100%%, got %d", m.viewport.Cursor)
+	}
+
+	sendKeys(&m, "101%")
+	if m.viewport.Cursor != 200 || m.statusMsg == "" {
+		t.Errorf("expected 101%% rejected with a message, got cursor %d and %q", m.viewport.Cursor, m.statusMsg)
+	}
+
+	sendKeys(&m, "%")
+	if m.viewport.Cursor != 200 {
+		t.Errorf("expected %% without a count to do nothing, got cursor %d", m.viewport.Cursor)
+	}
+}
+
 // TestHalfPageCommands verifies half-page navigation.
 func TestHalfPageCommands(t *testing.T) {
 	content := ""
@@ -702,7 +979,7 @@ func TestHalfPageCommands(t *testing.T) {
 	idx := createTestIndex(t, content)
 	defer closeIndex(idx)
 
-	m := New(idx, "test")
+	m := New(idx)
 	m.width = 120
 	m.height = 30
 	m.viewport.SetHeight(20)
@@ -730,7 +1007,7 @@ func TestCtrlBFCommands(t *testing.T) {
 	idx := createTestIndex(t, content)
 	defer closeIndex(idx)
 
-	m := New(idx, "test")
+	m := New(idx)
 	m.width = 120
 	m.height = 30
 
@@ -762,7 +1039,7 @@ func TestCtrlEYCommands(t *testing.T) {
 	idx := createTestIndex(t, content)
 	defer closeIndex(idx)
 
-	m := New(idx, "test")
+	m := New(idx)
 	m.width = 120
 	m.height = 30
 
@@ -790,7 +1067,7 @@ func TestHomeEndCommands(t *testing.T) {
 	idx := createTestIndex(t, content)
 	defer closeIndex(idx)
 
-	m := New(idx, "test")
+	m := New(idx)
 	m.width = 120
 	m.height = 30
 
@@ -822,7 +1099,7 @@ func TestHMLCommands(t *testing.T) {
 	idx := createTestIndex(t, content)
 	defer closeIndex(idx)
 
-	m := New(idx, "test")
+	m := New(idx)
 	m.width = 120
 	m.height = 30
 	m.viewport.SetHeight(20)
@@ -857,3 +1134,132 @@ func TestHMLCommands(t *testing.T) {
 		t.Errorf("expected cursor at middle after M, got %d, expected %d", m.viewport.Cursor, expectedMiddle)
 	}
 }
+
+// TestResetView verifies ctrl+r restores display modes but keeps the cursor.
+func TestResetView(t *testing.T) {
+	content := ""
+	for i := 0; i < 20; i++ {
+		content += `{"time":"2024-01-01T00:00:00Z","level":"info","msg":"test"}` + "\n"
+	}
+	idx := createTestIndex(t, content)
+	defer closeIndex(idx)
+
+	m := New(idx)
+	newM, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 30})
+	m = *newM.(*Model)
+
+	// Change several modes
+	m.viewport.Goto(7)
+	sendKeys(&m, "*test\n") // highlight
+	sendKeys(&m, "C>")      // move the Time column right
+	m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	sendKeys(&m, "T") // hide the sparkline
+	sendKeys(&m, "ll")
+	m.leftWidth = 50
+
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlR})
+
+	if m.highlight != nil {
+		t.Error("expected highlight to be cleared")
+	}
+	if m.columns[0].key != "time" {
+		t.Errorf("expected default column order, got %s first", m.columns[0].key)
+	}
+	if !m.showSparkline {
+		t.Error("expected sparkline to be shown again")
+	}
+	if m.detailOffset != 0 {
+		t.Errorf("expected detail offset 0, got %d", m.detailOffset)
+	}
+	if m.leftWidth != defaultLeftWidth {
+		t.Errorf("expected leftWidth %d, got %d", defaultLeftWidth, m.leftWidth)
+	}
+	if m.viewport.Cursor != 7 {
+		t.Errorf("expected cursor to stay at 7, got %d", m.viewport.Cursor)
+	}
+}
+
+// TestRedraw verifies Ctrl+l asks for a cleared screen and leaves the
+// cursor, scroll, and detail offsets where they were.
+func TestRedraw(t *testing.T) {
+	idx := createTestIndex(t, levelContent)
+	defer closeIndex(idx)
+
+	m := New(idx)
+	m.Update(tea.WindowSizeMsg{Width: 120, Height: 8})
+	sendKeys(&m, "jjjjj")
+	m.View()
+	sendKeys(&m, "l")
+	before := m.View()
+	cursor, offset, detail := m.viewport.Cursor, m.viewport.Offset, m.detailOffset
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlL})
+	if cmd == nil {
+		t.Fatal("expected Ctrl+l to return a command")
+	}
+	if msg := cmd(); msg != tea.ClearScreen() {
+		t.Errorf("expected a clear screen message, got %T", msg)
+	}
+	if m.viewport.Cursor != cursor || m.viewport.Offset != offset || m.detailOffset != detail {
+		t.Errorf("expected the view unchanged, got cursor %d offset %d detail %d", m.viewport.Cursor, m.viewport.Offset, m.detailOffset)
+	}
+	if after := m.View(); after != before {
+		t.Error("expected the same screen redrawn")
+	}
+}
+
+// BenchmarkRenderTable benchmarks rendering one screen of table rows,
+// reporting allocations for the hot scrolling path.
+func BenchmarkRenderTable(b *testing.B) {
+	var content strings.Builder
+	for i := 0; i < 1000; i++ {
+		content.WriteString(`{"time":"2024-01-01T00:00:00Z","level":"info","msg":"benchmark message"}`)
+		content.WriteByte('\n')
+	}
+	idx := createTestIndex(b, content.String())
+	defer closeIndex(idx)
+
+	m := New(idx)
+	m.Update(tea.WindowSizeMsg{Width: 160, Height: 50})
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.viewport.Goto((i % 900) + 1)
+		_ = m.renderTable()
+	}
+}
+
+// BenchmarkScrollRender benchmarks redrawing the table and detail pane as
+// the cursor steps through a window of rows, with and without the entry
+// cache, the way holding j re-renders mostly the same lines.
+func BenchmarkScrollRender(b *testing.B) {
+	var content strings.Builder
+	for i := 0; i < 1000; i++ {
+		fmt.Fprintf(&content, `{"time":"2024-01-01T00:00:00Z","level":"info","msg":"benchmark message %d","req":{"id":"r-%d","path":"/api/items","status":200}}`, i, i)
+		content.WriteByte('\n')
+	}
+	idx := createTestIndex(b, content.String())
+	defer closeIndex(idx)
+
+	for _, bm := range []struct {
+		name string
+		size int
+	}{
+		{"cached", DefaultEntryCacheSize},
+		{"uncached", 0},
+	} {
+		b.Run(bm.name, func(b *testing.B) {
+			m := New(idx, WithEntryCache(bm.size))
+			m.Update(tea.WindowSizeMsg{Width: 160, Height: 50})
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				m.viewport.Goto((i % 200) + 1)
+				_ = m.renderTable()
+				_ = m.renderDetail(m.viewport.Height)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package tui
+
+import "log/slog"
+
+// defaultVersion is the version shown when New isn't given WithVersion.
+const defaultVersion = "dev"
+
+// Option configures a Model as New creates it. Options are applied in
+// order, so a later one wins when two set the same thing.
+type Option func(*Model)
+
+// WithVersion sets the version shown in the status line.
+func WithVersion(version string) Option {
+	return func(m *Model) {
+		m.version = version
+	}
+}
+
+// WithBuildInfo sets the build description on the help overlay's about
+// page, e.g. "go1.22.1, built 2024-05-01T12:00:00Z". Without it the page
+// shows the Go version.
+func WithBuildInfo(info string) Option {
+	return func(m *Model) {
+		m.buildInfo = info
+	}
+}
+
+// WithColumns starts the table with a layout from ParseColumns, which
+// Ctrl+r also resets to.
+func WithColumns(cols Columns) Option {
+	return func(m *Model) {
+		m.setColumns(cols)
+	}
+}
+
+// WithAutoColumns adds fields found by parser.DetectColumns as table
+// columns, in the order given, for as many as fit once the terminal width
+// is known. Columns set with WithColumns are kept, with the fields placed
+// before the message.
+func WithAutoColumns(fields []string) Option {
+	return func(m *Model) {
+		m.autoFields = fields
+	}
+}
+
+// WithEntryCache sets how many parsed lines are kept for redrawing rows
+// and the detail pane while scrolling; 0 turns the cache off.
+func WithEntryCache(size int) Option {
+	return func(m *Model) {
+		m.entries = newEntryCache(size)
+	}
+}
+
+// WithTheme applies a color theme; see SetTheme. A nil theme keeps the
+// default colors.
+func WithTheme(theme *Theme) Option {
+	return func(m *Model) {
+		m.SetTheme(theme)
+	}
+}
+
+// WithWrapDetail starts the detail pane with long lines wrapped; see
+// SetWrapDetail.
+func WithWrapDetail(on bool) Option {
+	return func(m *Model) {
+		m.SetWrapDetail(on)
+	}
+}
+
+// WithFollow starts the model in follow mode; see SetFollow.
+func WithFollow(on bool) Option {
+	return func(m *Model) {
+		m.SetFollow(on)
+	}
+}
+
+// WithLogger records lines that fail to parse or pretty-print to logger at
+// debug level, with their row and the start of their text. Each line is
+// logged once: parse failures by the background scan for malformed lines,
+// and pretty-print failures the first time the line is shown. A nil
+// logger, the default, records nothing.
+func WithLogger(logger *slog.Logger) Option {
+	return func(m *Model) {
+		m.logger = logger
+	}
+}
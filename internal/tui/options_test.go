@@ -0,0 +1,79 @@
+package tui
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// TestNewOptions verifies New applies its options over the defaults, and
+// that a model built without any keeps them.
+func TestNewOptions(t *testing.T) {
+	content := `{"time":"2024-01-01T00:00:00Z","level":"info","msg":"test","user":"alice"}`
+	idx := createTestIndex(t, content)
+	defer closeIndex(idx)
+
+	m := New(idx)
+	if m.version != defaultVersion || m.wrapDetail || m.follow || m.theme != nil || len(m.columns) != 3 {
+		t.Error("expected New without options to use the defaults")
+	}
+
+	cols, err := ParseColumns("level,user:User")
+	if err != nil {
+		t.Fatalf("ParseColumns failed: %v", err)
+	}
+	theme := &Theme{Levels: map[string]string{"INFO": "#005F00"}}
+	m = New(idx,
+		WithVersion("1.2.3"),
+		WithColumns(cols),
+		WithTheme(theme),
+		WithWrapDetail(true),
+		WithFollow(true),
+	)
+	if !m.wrapDetail || !m.follow || m.levelColor("info") != "#005F00" {
+		t.Error("expected wrap, follow, and the theme to be applied")
+	}
+	if got := m.formatHeader(); !strings.Contains(got, "User") || strings.Contains(got, "Message") {
+		t.Errorf("expected the Lvl and User columns, got %q", got)
+	}
+	m.Update(tea.WindowSizeMsg{Width: 140, Height: 20})
+	if !strings.Contains(m.View(), "v1.2.3") {
+		t.Error("expected the version in the status line")
+	}
+
+	if _, err := ParseColumns("time,,msg"); err == nil {
+		t.Error("expected an error for an empty column")
+	}
+}
+
+// TestWithLogger verifies a line that fails to parse and pretty-print is
+// logged once with its row, however often it's redrawn.
+func TestWithLogger(t *testing.T) {
+	idx := createTestIndex(t, `{"level":"info","msg":"ok"}`+"\n"+`<not json>`)
+	defer closeIndex(idx)
+
+	var buf bytes.Buffer
+	m := New(idx, WithLogger(slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))))
+	m.width = 120
+	m.height = 30
+	runParseErrScan(t, &m)
+	m.gotoLine(2)
+	for range 3 {
+		m.invalidateEntries()
+		m.renderTable()
+		m.renderDetail(20)
+	}
+
+	got := buf.String()
+	for _, want := range []string{`msg="parse failed" row=2`, `msg="format failed" row=2`} {
+		if n := strings.Count(got, want); n != 1 {
+			t.Errorf("expected %q logged once, got %d times in %q", want, n, got)
+		}
+	}
+	if strings.Contains(got, "row=1") {
+		t.Errorf("expected nothing logged for line 1, got %q", got)
+	}
+}
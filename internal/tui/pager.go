@@ -0,0 +1,55 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// pagerDoneMsg is sent when the pager started by gp exits.
+type pagerDoneMsg struct {
+	err error
+}
+
+// pagerCommand builds the command viewing path: $PAGER, else $EDITOR,
+// else less. Either variable may carry arguments, as in "less -R".
+func pagerCommand(path string) *exec.Cmd {
+	pager := os.Getenv("PAGER")
+	if strings.TrimSpace(pager) == "" {
+		pager = os.Getenv("EDITOR")
+	}
+	args := strings.Fields(pager)
+	if len(args) == 0 {
+		args = []string{"less"}
+	}
+	return exec.Command(args[0], append(args[1:], path)...)
+}
+
+// openInPager writes the cursor entry, pretty-printed, to a temp file and
+// opens it in the pager, suspending the TUI until the pager exits. The
+// file is removed afterward. Problems are reported in the status line.
+func (m *Model) openInPager() tea.Cmd {
+	n := m.cursorLine()
+	raw, err := m.idx.GetLine(n)
+	if err != nil {
+		m.statusMsg = fmt.Sprintf("cannot read line: %v", err)
+		return nil
+	}
+	text, err := m.parser.FormatPretty(raw)
+	if err != nil {
+		// A line that isn't JSON is still worth reading in full
+		text = string(raw)
+	}
+	path, err := writeTempCopy(text)
+	if err != nil {
+		m.statusMsg = fmt.Sprintf("cannot open line %d in pager: %v", n, err)
+		return nil
+	}
+	return tea.ExecProcess(pagerCommand(path), func(err error) tea.Msg {
+		_ = os.Remove(path)
+		return pagerDoneMsg{err: err}
+	})
+}
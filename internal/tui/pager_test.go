@@ -0,0 +1,48 @@
+package tui
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// TestPagerCommand verifies $PAGER wins over $EDITOR, arguments in either
+// are kept, and less is the fallback.
+func TestPagerCommand(t *testing.T) {
+	tests := []struct {
+		pager, editor string
+		want          []string
+	}{
+		{"less -R", "vim", []string{"less", "-R", "entry.json"}},
+		{"", "nano", []string{"nano", "entry.json"}},
+		{" ", "", []string{"less", "entry.json"}},
+	}
+	for _, tt := range tests {
+		t.Setenv("PAGER", tt.pager)
+		t.Setenv("EDITOR", tt.editor)
+		if got := pagerCommand("entry.json").Args; !slices.Equal(got, tt.want) {
+			t.Errorf("PAGER=%q EDITOR=%q: expected %q, got %q", tt.pager, tt.editor, tt.want, got)
+		}
+	}
+}
+
+// TestOpenInPager verifies gp starts the pager and a failed launch is
+// reported in the status line.
+func TestOpenInPager(t *testing.T) {
+	idx := createTestIndex(t, `{"level":"info","msg":"test"}`)
+	defer closeIndex(idx)
+
+	t.Setenv("TMPDIR", t.TempDir())
+	m := New(idx)
+	sendKeys(&m, "g")
+	if _, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'p'}}); cmd == nil {
+		t.Error("expected gp to start the pager")
+	}
+
+	m.Update(pagerDoneMsg{err: errors.New(`exec: "nopager": executable file not found in $PATH`)})
+	if m.statusMsg != `pager failed: exec: "nopager": executable file not found in $PATH` {
+		t.Errorf("expected the launch error in the status line, got %q", m.statusMsg)
+	}
+}
@@ -0,0 +1,26 @@
+package tui
+
+// SetPageKeepCursor makes paging move the cursor along with the view so it
+// stays on the same screen row, as in less, instead of jumping to the top
+// or bottom of the new page.
+func (m *Model) SetPageKeepCursor(on bool) {
+	m.pageKeepCursor = on
+}
+
+// pageDown moves down one screen in the configured paging style.
+func (m *Model) pageDown() {
+	if m.pageKeepCursor {
+		m.viewport.PageDownKeepCursor()
+		return
+	}
+	m.viewport.PageDown()
+}
+
+// pageUp moves up one screen in the configured paging style.
+func (m *Model) pageUp() {
+	if m.pageKeepCursor {
+		m.viewport.PageUpKeepCursor()
+		return
+	}
+	m.viewport.PageUp()
+}
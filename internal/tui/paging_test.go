@@ -0,0 +1,52 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// TestPageKeepCursor verifies PgDn, PgUp, Ctrl+f, and Ctrl+b keep the
+// cursor's screen row with -page-keep-cursor and move it to the edge of the
+// new page without it.
+func TestPageKeepCursor(t *testing.T) {
+	var b strings.Builder
+	for n := 1; n <= 200; n++ {
+		fmt.Fprintf(&b, `{"level":"info","msg":"line %d"}`+"\n", n)
+	}
+	idx := createTestIndex(t, b.String())
+	defer closeIndex(idx)
+
+	tests := []struct {
+		name       string
+		keep       bool
+		key        tea.KeyMsg
+		wantCursor int
+	}{
+		{"pgdown", false, tea.KeyMsg{Type: tea.KeyPgDown}, 65},
+		{"pgup", false, tea.KeyMsg{Type: tea.KeyPgUp}, 44},
+		{"pgdown keep", true, tea.KeyMsg{Type: tea.KeyPgDown}, 74},
+		{"pgup keep", true, tea.KeyMsg{Type: tea.KeyPgUp}, 34},
+		{"ctrl+f keep", true, tea.KeyMsg{Type: tea.KeyCtrlF}, 74},
+		{"ctrl+b keep", true, tea.KeyMsg{Type: tea.KeyCtrlB}, 34},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := New(idx)
+			m.SetPageKeepCursor(tt.keep)
+			m.viewport.SetHeight(20)
+			m.viewport.Cursor, m.viewport.Offset = 54, 45
+
+			rel := m.viewport.CursorRelative()
+			m.Update(tt.key)
+			if m.viewport.Cursor != tt.wantCursor {
+				t.Errorf("expected cursor %d, got %s", tt.wantCursor, m.viewport.State())
+			}
+			if tt.keep && m.viewport.CursorRelative() != rel {
+				t.Errorf("expected screen row %d kept, got %d", rel, m.viewport.CursorRelative())
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package tui
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+	"sort"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lbe/jsonlogviewer/internal/index"
+	"github.com/lbe/jsonlogviewer/internal/parser"
+)
+
+// parseErrors tracks the lines that don't parse, so they can be counted
+// and stepped through in a messy log.
+type parseErrors struct {
+	// lines lists the malformed lines in file order.
+	lines []int
+	// done is set once lines covers the whole file; until then the
+	// background scan is still running.
+	done bool
+	// gen identifies the scan, so the results of a scan of a reloaded
+	// file's old index are dropped.
+	gen int
+}
+
+// parseErrScanDoneMsg carries the malformed lines from 1 to upTo found by
+// the scan started as gen.
+type parseErrScanDoneMsg struct {
+	gen   int
+	lines []int
+	upTo  int
+}
+
+// parseError returns why p can't parse raw, or nil if it can.
+func parseError(p *parser.Parser, raw []byte) error {
+	var entry parser.LogEntry
+	return p.ParseInto(raw, 0, &entry)
+}
+
+// logParseError records that line n, raw, failed to parse with err. A nil
+// logger records nothing.
+func logParseError(logger *slog.Logger, n int, raw []byte, err error) {
+	if logger != nil {
+		logger.Debug("parse failed", "row", n, "error", err, "line", parser.LogText(raw))
+	}
+}
+
+// scanParseErrors returns a command finding the malformed lines from 1
+// through upTo in the background as scan gen. Lines are read in format
+// with the scan's own parser, so it doesn't share state with rendering.
+// Each malformed line is logged to logger as it's found.
+func scanParseErrors(idx *index.Index, gen, upTo int, format parser.Format, logger *slog.Logger) tea.Cmd {
+	return func() tea.Msg {
+		p := parser.New()
+		p.SetFormat(format)
+		lines := make([]int, 0)
+		for n := 1; n <= upTo; n++ {
+			raw, err := idx.GetLine(n)
+			if err != nil {
+				continue
+			}
+			if err := parseError(p, raw); err != nil {
+				lines = append(lines, n)
+				logParseError(logger, n, raw, err)
+			}
+		}
+		return parseErrScanDoneMsg{gen: gen, lines: lines, upTo: upTo}
+	}
+}
+
+// parseErrScanDone installs the result of the background scan, checking
+// any lines added while it ran.
+func (m *Model) parseErrScanDone(msg parseErrScanDoneMsg) {
+	if msg.gen != m.parseErrs.gen {
+		return
+	}
+	// The last scanned line may have been completed since, so it's
+	// checked again along with the new ones
+	m.parseErrs.lines = msg.lines
+	m.parseErrs.done = true
+	m.extendParseErrors(max(msg.upTo, 1))
+}
+
+// extendParseErrors checks lines from onward after the index grew. It
+// does nothing while the background scan is still running, since the
+// scan picks those lines up when it finishes. Lines already known to be
+// malformed aren't logged again.
+func (m *Model) extendParseErrors(from int) {
+	if !m.parseErrs.done {
+		return
+	}
+	kept := dropFrom(m.parseErrs.lines, from)
+	known := slices.Clone(m.parseErrs.lines[len(kept):])
+	m.parseErrs.lines = kept
+	for n := from; n <= m.idx.LineCount(); n++ {
+		raw, err := m.idx.GetLine(n)
+		if err != nil {
+			continue
+		}
+		if err := parseError(m.parser, raw); err != nil {
+			m.parseErrs.lines = append(m.parseErrs.lines, n)
+			if len(known) > 0 && known[0] == n {
+				known = known[1:]
+			} else {
+				logParseError(m.logger, n, raw, err)
+			}
+		}
+	}
+}
+
+// jumpParseError moves the cursor to the next (dir > 0) or previous
+// malformed line.
+func (m *Model) jumpParseError(dir int) {
+	lines, cur := m.parseErrs.lines, m.cursorLine()
+	var target int
+	if dir > 0 {
+		i := sort.SearchInts(lines, cur+1)
+		if i < len(lines) {
+			target = lines[i]
+		}
+	} else {
+		i := sort.SearchInts(lines, cur) - 1
+		if i >= 0 {
+			target = lines[i]
+		}
+	}
+
+	switch {
+	case target > 0:
+		m.gotoLine(target)
+		if m.cursorLine() != target {
+			m.statusMsg = fmt.Sprintf("malformed line %d is hidden by filters", target)
+		}
+	case !m.parseErrs.done:
+		m.statusMsg = "still scanning for malformed lines"
+	case dir > 0:
+		m.statusMsg = "no malformed lines below"
+	default:
+		m.statusMsg = "no malformed lines above"
+	}
+}
+
+// parseErrState describes the malformed lines for the status line, e.g.
+// "3 parse errors", or "" when none have been found.
+func (m *Model) parseErrState() string {
+	count := len(m.parseErrs.lines)
+	switch {
+	case count == 0:
+		return ""
+	case count == 1:
+		return "1 parse error"
+	default:
+		return fmt.Sprintf("%d parse errors", count)
+	}
+}
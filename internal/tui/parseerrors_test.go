@@ -0,0 +1,79 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// malformedContent has non-JSON lines at 2 and 4.
+const malformedContent = `{"time":"2024-01-01T00:00:00Z","level":"info","msg":"one"}
+panic: runtime error
+{"time":"2024-01-01T00:00:02Z","level":"error","msg":"three"}
+garbage text
+{"time":"2024-01-01T00:00:04Z","level":"info","msg":"five"}`
+
+// runParseErrScan runs the background scan Init starts and delivers its
+// result to m.
+func runParseErrScan(t *testing.T, m *Model) {
+	t.Helper()
+	msg, ok := scanParseErrors(m.idx, m.parseErrs.gen, m.idx.LineCount(), m.parser.Format(), m.logger)().(parseErrScanDoneMsg)
+	if !ok {
+		t.Fatal("expected a parseErrScanDoneMsg")
+	}
+	m.Update(msg)
+}
+
+// TestParseErrorScan verifies the scan counts the malformed lines for the
+// status line and ]e/[e step through them.
+func TestParseErrorScan(t *testing.T) {
+	idx := createTestIndex(t, malformedContent)
+	defer closeIndex(idx)
+
+	m := New(idx)
+	sendKeys(&m, "]e")
+	if m.statusMsg != "still scanning for malformed lines" {
+		t.Errorf("expected the scan to be reported as running, got %q", m.statusMsg)
+	}
+
+	runParseErrScan(t, &m)
+	if got := m.parseErrState(); got != "2 parse errors" {
+		t.Errorf("expected 2 parse errors, got %q", got)
+	}
+
+	for _, want := range []int{2, 4} {
+		sendKeys(&m, "]e")
+		if got := m.cursorLine(); got != want {
+			t.Errorf("expected ]e to reach line %d, got %d", want, got)
+		}
+	}
+	sendKeys(&m, "]e")
+	if m.cursorLine() != 4 || m.statusMsg != "no malformed lines below" {
+		t.Errorf("expected to stay on line 4 with a message, got %d %q", m.cursorLine(), m.statusMsg)
+	}
+	sendKeys(&m, "[e")
+	if got := m.cursorLine(); got != 2 {
+		t.Errorf("expected [e to go back to line 2, got %d", got)
+	}
+}
+
+// TestParseErrorRows verifies malformed lines keep their table row, shown
+// with their text in the parse error style.
+func TestParseErrorRows(t *testing.T) {
+	idx := createTestIndex(t, malformedContent)
+	defer closeIndex(idx)
+
+	m := New(idx)
+	rows := strings.Split(m.renderTable(), "\n")
+	if !strings.Contains(ansi.Strip(rows[1]), "panic: runtime error") {
+		t.Errorf("expected line 2's text in its row, got %q", ansi.Strip(rows[1]))
+	}
+	if !strings.Contains(ansi.Strip(rows[4]), "five") {
+		t.Errorf("expected line 5 on the fifth row, got %q", ansi.Strip(rows[4]))
+	}
+	want := m.styles.ParseError.Width(m.tableWidth()).Render(fitWidth(ansi.Strip(rows[3]), m.tableWidth()))
+	if rows[3] != want {
+		t.Errorf("expected line 4 in the parse error style, got %q", rows[3])
+	}
+}
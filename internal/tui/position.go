@@ -0,0 +1,24 @@
+package tui
+
+import "fmt"
+
+// showPosition reports where the cursor is, like Ctrl+g in vim: the file,
+// the line and how far through the file it is, and the byte offset the
+// line starts at, e.g. `"app.log" line 120 of 480 --25%-- byte 18432`.
+// With filters on it also gives the row among those shown.
+func (m *Model) showPosition() {
+	total := m.idx.LineCount()
+	if total == 0 || m.rowCount() == 0 {
+		m.statusMsg = fmt.Sprintf("%q no lines shown", m.idx.Name())
+		return
+	}
+	line := m.cursorLine()
+	msg := fmt.Sprintf("%q line %d of %d --%d%%--", m.idx.Name(), line, total, line*100/total)
+	if off, err := m.idx.LineOffset(line); err == nil {
+		msg += fmt.Sprintf(" byte %d", off)
+	}
+	if m.filtering() {
+		msg += fmt.Sprintf(", row %d of %d shown", m.viewport.Cursor, m.rowCount())
+	}
+	m.statusMsg = msg
+}
@@ -0,0 +1,35 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// TestShowPosition verifies Ctrl+g reports the file, line, percentage, and
+// byte offset, adding the row among those shown when filtered.
+func TestShowPosition(t *testing.T) {
+	idx := createTestIndex(t, levelContent)
+	defer closeIndex(idx)
+
+	m := New(idx)
+	m.width = 120
+	m.height = 30
+
+	ctrlG := tea.KeyMsg{Type: tea.KeyCtrlG}
+	sendKeys(&m, "jjj")
+	m.Update(ctrlG)
+	offset := strings.Index(levelContent, `{"level":"info","msg":"d"}`)
+	want := fmt.Sprintf("%q line 4 of 7 --57%%-- byte %d", idx.Name(), offset)
+	if m.statusMsg != want {
+		t.Errorf("expected %q, got %q", want, m.statusMsg)
+	}
+
+	submitRegexFilter(&m, `"info"`)
+	m.Update(ctrlG)
+	if !strings.Contains(m.statusMsg, "line 4 of 7") || !strings.HasSuffix(m.statusMsg, ", row 2 of 2 shown") {
+		t.Errorf("expected the filtered row in %q", m.statusMsg)
+	}
+}
@@ -0,0 +1,109 @@
+package tui
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// promptKind identifies what the status-line prompt is collecting input for.
+type promptKind int
+
+const (
+	// promptNone means no prompt is open.
+	promptNone promptKind = iota
+	// promptHighlight collects a pattern for highlighting matching rows.
+	promptHighlight
+	// promptCommand collects an ex-style command such as "w out.log".
+	promptCommand
+	// promptSearch collects a query to search for.
+	promptSearch
+	// promptExport collects the path to write matching lines to.
+	promptExport
+	// promptFilter collects a regular expression to filter lines by.
+	promptFilter
+	// promptExportSelection collects the path to write the visual
+	// selection to.
+	promptExportSelection
+	// promptStats collects the field whose values the stats overlay
+	// counts.
+	promptStats
+)
+
+// promptLabels holds the text shown before the input for each prompt kind.
+var promptLabels = map[promptKind]string{
+	promptHighlight: "highlight: ",
+	promptCommand:   ":",
+	promptSearch:    "/",
+	promptExport:    "write matches to: ",
+	promptFilter:    "&/",
+
+	promptExportSelection: "write selection to: ",
+	promptStats:           "count values of field (empty for levels only): ",
+}
+
+// openPrompt opens the status-line prompt for the given kind.
+func (m *Model) openPrompt(kind promptKind) {
+	m.prompt = kind
+	m.promptInput = ""
+	m.promptErr = ""
+	m.pendingNumber = ""
+	m.lastG = false
+	m.resizeMode = false
+}
+
+// handlePromptKey handles input while a prompt is open. Enter submits,
+// Esc cancels, and Backspace deletes the last character.
+func (m *Model) handlePromptKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	m.promptErr = ""
+	switch msg.Type {
+	case tea.KeyEnter:
+		kind, input := m.prompt, m.promptInput
+		m.prompt = promptNone
+		m.promptInput = ""
+		return m.submitPrompt(kind, input)
+	case tea.KeyEsc, tea.KeyCtrlC:
+		m.prompt = promptNone
+		m.promptInput = ""
+	case tea.KeyBackspace:
+		if r := []rune(m.promptInput); len(r) > 0 {
+			m.promptInput = string(r[:len(r)-1])
+		}
+	case tea.KeySpace:
+		m.promptInput += " "
+	case tea.KeyRunes:
+		m.promptInput += string(msg.Runes)
+	}
+	return m, nil
+}
+
+// submitPrompt acts on the input collected by a prompt.
+func (m *Model) submitPrompt(kind promptKind, input string) (tea.Model, tea.Cmd) {
+	switch kind {
+	case promptHighlight:
+		return m, m.setHighlight(input)
+	case promptCommand:
+		m.runCommand(input)
+	case promptSearch:
+		m.search(input)
+	case promptExport:
+		m.exportMatches(strings.TrimSpace(input))
+	case promptFilter:
+		return m, m.setRegexFilter(input)
+	case promptExportSelection:
+		m.exportSelection(strings.TrimSpace(input))
+	case promptStats:
+		return m, m.openStats(strings.TrimSpace(input))
+	}
+	return m, nil
+}
+
+// renderPrompt renders the open prompt for the status line, followed by
+// the error from the last submission when it was rejected.
+func (m *Model) renderPrompt() string {
+	prompt := m.styles.Title.Render(promptLabels[m.prompt]) + m.promptInput + "█"
+	if m.promptErr != "" {
+		prompt += "  " + m.styles.ErrorMsg.Render(m.promptErr)
+	}
+	return prompt
+}
@@ -0,0 +1,232 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/lbe/jsonlogviewer/internal/index"
+	"github.com/tidwall/gjson"
+)
+
+// regexCheckEvery is how many lines the background regex scan matches
+// between checks for cancellation.
+const regexCheckEvery = 4096
+
+// regexFieldPattern matches the gjson path in a "path=~regex" filter.
+var regexFieldPattern = regexp.MustCompile(`^([A-Za-z0-9_.@-]+)=~(.*)$`)
+
+// regexFilter hides lines that don't match a regular expression, applied
+// to the raw line or to one field of it, or a numeric comparison on a field.
+type regexFilter struct {
+	// pattern is the filter as typed, for the status line.
+	pattern string
+	// compare marks a numeric comparison rather than a regular expression.
+	compare bool
+	// match reports whether a raw line passes the filter. It's safe to
+	// call from the background scan.
+	match linePredicate
+	// nonNumeric reports whether a comparison's field is present in a raw
+	// line but not a number; nil for a regular expression.
+	nonNumeric linePredicate
+	// note explains how the input was read, e.g. that a comparison with
+	// an operand that isn't a number became a regular expression.
+	note string
+	// matches lists the matching lines once the scan has finished.
+	matches []int
+	// skipped lists the lines nonNumeric holds for once the scan has
+	// finished.
+	skipped []int
+	// done is set when matches covers the whole file.
+	done bool
+	// gen identifies the scan, so results of a replaced filter are dropped.
+	gen int
+	// cancel stops the background scan.
+	cancel context.CancelFunc
+}
+
+// regexScanDoneMsg carries the lines from 1 to upTo that matched the
+// filter started as scan gen.
+type regexScanDoneMsg struct {
+	gen     int
+	matches []int
+	skipped []int
+	upTo    int
+}
+
+// parseFilter compiles the filter typed at the & prompt: a numeric
+// comparison (see parseCompareFilter) or a regular expression (see
+// parseRegexFilter).
+func parseFilter(input string) (*regexFilter, error) {
+	if f, ok := parseCompareFilter(input); ok {
+		return f, nil
+	}
+	f, err := parseRegexFilter(input)
+	if err == nil {
+		if operand := nonNumericOperand(input); operand != "" {
+			f.note = fmt.Sprintf("%q isn't a number, so this is a regular expression", operand)
+		}
+	}
+	return f, err
+}
+
+// parseRegexFilter compiles a filter of the form "regex", matched against
+// the raw line, or "path=~regex", matched against the string value of the
+// gjson path.
+func parseRegexFilter(input string) (*regexFilter, error) {
+	field, expr := "", input
+	if sub := regexFieldPattern.FindStringSubmatch(input); sub != nil {
+		field, expr = sub[1], sub[2]
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &regexFilter{pattern: input}
+	if field == "" {
+		f.match = re.Match
+	} else {
+		f.match = func(raw []byte) bool {
+			v := gjson.GetBytes(raw, field)
+			return v.Exists() && re.MatchString(v.String())
+		}
+	}
+	return f, nil
+}
+
+// regexActive reports whether a regex filter has finished its scan and is
+// hiding lines.
+func (m *Model) regexActive() bool {
+	return m.regex != nil && m.regex.done
+}
+
+// setRegexFilter replaces the regex filter with input and starts matching
+// it in the background, or clears it when input is empty. Invalid syntax
+// reopens the prompt with the error shown.
+func (m *Model) setRegexFilter(input string) tea.Cmd {
+	if strings.TrimSpace(input) == "" {
+		m.clearRegexFilter()
+		m.applyFilters()
+		m.statusMsg = "regex filter cleared"
+		return nil
+	}
+	f, err := parseFilter(input)
+	if err != nil {
+		m.openPrompt(promptFilter)
+		m.promptInput = input
+		m.promptErr = err.Error()
+		return nil
+	}
+
+	m.clearRegexFilter()
+	m.regexGen++
+	f.gen = m.regexGen
+	ctx, cancel := context.WithCancel(context.Background())
+	f.cancel = cancel
+	m.regex = f
+	return scanRegex(ctx, m.idx, f.gen, f.match, f.nonNumeric, m.idx.LineCount())
+}
+
+// clearRegexFilter stops any scan in progress and drops the regex filter.
+// The caller rebuilds the visible rows.
+func (m *Model) clearRegexFilter() {
+	if m.regex != nil {
+		m.regex.cancel()
+		m.regex = nil
+	}
+}
+
+// scanRegex returns a command matching lines 1 through upTo in the
+// background, also collecting the lines nonNumeric, when set, holds for.
+// A cancelled scan sends no message.
+func scanRegex(ctx context.Context, idx *index.Index, gen int, match, nonNumeric linePredicate, upTo int) tea.Cmd {
+	return func() tea.Msg {
+		matches := make([]int, 0)
+		var skipped []int
+		for n := 1; n <= upTo; n++ {
+			if n%regexCheckEvery == 0 && ctx.Err() != nil {
+				return nil
+			}
+			raw, err := idx.GetLine(n)
+			switch {
+			case err != nil:
+			case match(raw):
+				matches = append(matches, n)
+			case nonNumeric != nil && nonNumeric(raw):
+				skipped = append(skipped, n)
+			}
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+		return regexScanDoneMsg{gen: gen, matches: matches, skipped: skipped, upTo: upTo}
+	}
+}
+
+// regexScanDone installs the matches of a finished scan, matching any lines
+// added while it ran, and rebuilds the visible rows.
+func (m *Model) regexScanDone(msg regexScanDoneMsg) {
+	f := m.regex
+	if f == nil || f.gen != msg.gen {
+		return
+	}
+	// The last scanned line may have been completed since, so it's
+	// matched again along with the new ones
+	f.matches, f.skipped = msg.matches, msg.skipped
+	f.extend(m.idx, max(msg.upTo, 1))
+	f.done = true
+	m.applyFilters()
+	m.statusMsg = fmt.Sprintf("filter %s: %d of %d lines", f.label(), m.rowCount(), m.idx.LineCount())
+	if f.note != "" {
+		m.statusMsg += " (" + f.note + ")"
+	}
+}
+
+// extend matches lines from onward after the index grew, replacing what
+// was found for them before.
+func (f *regexFilter) extend(idx *index.Index, from int) {
+	f.matches = dropFrom(f.matches, from)
+	f.skipped = dropFrom(f.skipped, from)
+	for n := from; n <= idx.LineCount(); n++ {
+		raw, err := idx.GetLine(n)
+		switch {
+		case err != nil:
+		case f.match(raw):
+			f.matches = append(f.matches, n)
+		case f.nonNumeric != nil && f.nonNumeric(raw):
+			f.skipped = append(f.skipped, n)
+		}
+	}
+}
+
+// label shows the filter as it's written at the & prompt, e.g.
+// "&/err(or)?/" or "&duration_ms > 500".
+func (f *regexFilter) label() string {
+	if f.compare {
+		return "&" + f.pattern
+	}
+	return "&/" + f.pattern + "/"
+}
+
+// regexState describes the regex filter for the status line, e.g.
+// "&/err(or)?/ 12 matches", or "" when none is set. A comparison also
+// counts the lines whose field isn't a number, e.g. "&duration_ms > 500
+// 2 matches, 1 not a number". A long pattern is cut short.
+func (m *Model) regexState() string {
+	if m.regex == nil {
+		return ""
+	}
+	label := ansi.Truncate(m.regex.label(), maxStatePattern, "…")
+	if !m.regex.done {
+		return label + " filtering…"
+	}
+	state := fmt.Sprintf("%s %d matches", label, len(m.regex.matches))
+	if n := len(m.regex.skipped); n > 0 {
+		state += fmt.Sprintf(", %d not a number", n)
+	}
+	return state
+}
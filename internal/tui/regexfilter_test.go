@@ -0,0 +1,170 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// submitRegexFilter types pattern at the & prompt, submits it, and runs the
+// background scan to completion.
+func submitRegexFilter(m *Model, pattern string) {
+	sendKeys(m, "&"+pattern)
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		return
+	}
+	if msg := cmd(); msg != nil {
+		m.Update(msg)
+	}
+}
+
+// TestParseRegexFilter verifies patterns match the raw line, path=~regex
+// matches one field, and invalid syntax is an error.
+func TestParseRegexFilter(t *testing.T) {
+	raw := []byte(`{"level":"error","msg":"disk full","req":{"id":"r-42"}}`)
+	tests := []struct {
+		input   string
+		want    bool
+		wantErr bool
+	}{
+		{`disk\s+full`, true, false},
+		{`"level":"error"`, true, false},
+		{`^warn`, false, false},
+		{`msg=~^disk`, true, false},
+		{`level=~^disk`, false, false},
+		{`req.id=~r-\d+`, true, false},
+		{`missing=~.*`, false, false},
+		{`a=b`, false, false},
+		{`(unclosed`, false, true},
+		{`msg=~[`, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			f, err := parseRegexFilter(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseRegexFilter failed: %v", err)
+			}
+			if got := f.match(raw); got != tt.want {
+				t.Errorf("expected match %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+// TestRegexFilter verifies & hides lines that don't match, combines with
+// the level filters, shows its match count, and is cleared by an empty
+// pattern or f0.
+func TestRegexFilter(t *testing.T) {
+	idx := createTestIndex(t, levelContent)
+	defer closeIndex(idx)
+
+	m := New(idx)
+	m.width = 120
+	m.height = 30
+
+	steps := []struct {
+		name      string
+		apply     func()
+		wantRows  []int
+		wantState string
+	}{
+		{"raw line", func() { submitRegexFilter(&m, `"msg":"[a-c]"`) }, []int{1, 2, 3}, `&/"msg":"[a-c]"/ 3 matches`},
+		{"with min level", func() { sendKeys(&m, "+++") }, []int{2, 3}, `&/"msg":"[a-c]"/ 3 matches`},
+		{"field", func() { submitRegexFilter(&m, `msg=~^[d-f]$`) }, []int{4, 5, 6}, `&/msg=~^[d-f]$/ 3 matches`},
+		{"f0", func() { sendKeys(&m, "f0") }, []int{1, 2, 3, 4, 5, 6, 7}, ""},
+		{"again", func() { submitRegexFilter(&m, `"level":`) }, []int{1, 2, 3, 4, 5, 6}, `&/"level":/ 6 matches`},
+		{"empty clears", func() { submitRegexFilter(&m, "") }, []int{1, 2, 3, 4, 5, 6, 7}, ""},
+	}
+
+	for _, s := range steps {
+		s.apply()
+		var got []int
+		for pos := 1; pos <= m.rowCount(); pos++ {
+			got = append(got, m.lineAt(pos))
+		}
+		if fmt.Sprint(got) != fmt.Sprint(s.wantRows) {
+			t.Errorf("%s: expected rows %v, got %v", s.name, s.wantRows, got)
+		}
+		if m.regexState() != s.wantState {
+			t.Errorf("%s: expected state %q, got %q", s.name, s.wantState, m.regexState())
+		}
+	}
+}
+
+// TestRegexFilterInvalid verifies invalid syntax keeps the prompt open with
+// the error shown and leaves the rows alone.
+func TestRegexFilterInvalid(t *testing.T) {
+	idx := createTestIndex(t, levelContent)
+	defer closeIndex(idx)
+
+	m := New(idx)
+	m.width = 120
+	m.height = 30
+
+	submitRegexFilter(&m, `("a"`)
+	if m.prompt != promptFilter || m.promptInput != `("a"` {
+		t.Fatalf("expected the prompt to stay open with %q, got kind %d input %q", `("a"`, m.prompt, m.promptInput)
+	}
+	if !strings.Contains(m.View(), "missing closing )") {
+		t.Error("expected the syntax error in the view")
+	}
+	if m.rowCount() != 7 || m.regex != nil {
+		t.Errorf("expected no filter, got %d rows", m.rowCount())
+	}
+
+	// Fixing the pattern clears the error and applies it
+	sendKeys(&m, ")")
+	if m.promptErr != "" {
+		t.Errorf("expected typing to clear the error, got %q", m.promptErr)
+	}
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m.Update(cmd())
+	if m.rowCount() != 1 {
+		t.Errorf("expected 1 row matching (\"a\"), got %d", m.rowCount())
+	}
+}
+
+// TestRegexFilterReplaced verifies a filter replaced while its scan runs is
+// cancelled and its results are dropped, and that lines added during a
+// scan are matched when it finishes.
+func TestRegexFilterReplaced(t *testing.T) {
+	idx := createTestIndex(t, levelContent)
+	defer closeIndex(idx)
+
+	m := New(idx)
+	m.width = 120
+	m.height = 30
+
+	first := m.setRegexFilter("info")
+	if !strings.Contains(m.regexState(), "filtering…") || m.rowCount() != 7 {
+		t.Errorf("expected every row while scanning, got %d rows, state %q", m.rowCount(), m.regexState())
+	}
+	second := m.setRegexFilter("error")
+	if msg := first(); msg != nil {
+		t.Errorf("expected the cancelled scan to send nothing, got %#v", msg)
+	}
+
+	// A stale result is ignored
+	m.regexScanDone(regexScanDoneMsg{gen: m.regexGen - 1, matches: []int{2, 4}, upTo: 7})
+	if m.regexActive() {
+		t.Fatal("expected a stale scan not to apply")
+	}
+
+	// A scan that stopped short is extended to the end of the file
+	msg := second().(regexScanDoneMsg)
+	msg.matches, msg.upTo = nil, 3
+	m.regexScanDone(msg)
+	if m.rowCount() != 1 || m.lineAt(1) != 5 {
+		t.Errorf("expected only line 5, got %d rows starting at %d", m.rowCount(), m.lineAt(1))
+	}
+}
@@ -0,0 +1,21 @@
+package tui
+
+// SetRelativeNumbers turns relative row numbers on or off. Off by default,
+// so the Row column shows file line numbers unless asked otherwise.
+func (m *Model) SetRelativeNumbers(on bool) {
+	m.relativeNumbers = on
+}
+
+// rowNumber returns the number shown in the Row column for file line n at
+// row pos. With relative numbers, rows other than the cursor's show their
+// distance from it, like vim's relativenumber, so counts for 10j and 10k
+// can be read off the table.
+func (m *Model) rowNumber(pos, n int) int {
+	if !m.relativeNumbers || pos == m.viewport.Cursor {
+		return n
+	}
+	if pos < m.viewport.Cursor {
+		return m.viewport.Cursor - pos
+	}
+	return pos - m.viewport.Cursor
+}
@@ -0,0 +1,52 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// TestRelativeNumbers verifies # switches other rows to their distance
+// from the cursor while the cursor row keeps its line number, counting
+// rows rather than file lines when a filter hides some.
+func TestRelativeNumbers(t *testing.T) {
+	idx := createTestIndex(t, levelContent)
+	defer closeIndex(idx)
+
+	m := New(idx)
+	m.width = 120
+	m.height = 30
+	m.viewport.Goto(3)
+
+	tests := []struct {
+		name string
+		keys string
+		want map[int]int // row -> number shown
+	}{
+		{"off", "", map[int]int{1: 1, 3: 3, 5: 5}},
+		{"on", "#", map[int]int{1: 2, 2: 1, 3: 3, 4: 1, 7: 4}},
+		{"filtered", "f2", map[int]int{1: 1, 2: 3, 3: 1, 5: 3}},
+		{"off again", "#", map[int]int{1: 1, 2: 3, 5: 7}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sendKeys(&m, tt.keys)
+			for pos, want := range tt.want {
+				if got := m.rowNumber(pos, m.lineAt(pos)); got != want {
+					t.Errorf("row %d: expected %d, got %d", pos, want, got)
+				}
+			}
+		})
+	}
+
+	// Line 7 is three rows below the cursor
+	sendKeys(&m, "#")
+	if strings.Contains(m.renderTable(), fmt.Sprintf("%*d ", rowNumWidth, 7)) {
+		t.Error("expected the rendered table to show distances, not line 7")
+	}
+	m.resetView()
+	if m.relativeNumbers {
+		t.Error("expected reset to turn relative numbers off")
+	}
+}
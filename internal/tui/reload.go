@@ -0,0 +1,79 @@
+package tui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lbe/jsonlogviewer/internal/index"
+)
+
+// WithReload lets R re-read the log from disk: reopen builds a fresh index
+// of the same source the way the one passed to New was built. Without it,
+// as for stdin, R reports that the source can't be reloaded.
+func WithReload(reopen func() (*index.Index, error)) Option {
+	return func(m *Model) {
+		m.reopen = reopen
+	}
+}
+
+// reload replaces the index with a fresh one of the same file, for a log
+// that was rotated or rewritten in place rather than appended to, which
+// follow mode can't pick up. Filters, the search, and marks are kept and
+// matched against the new contents, and the cursor stays on the same line
+// number when the file still has it. A file that can't be reopened, such
+// as one that was deleted, leaves the old index in place.
+func (m *Model) reload() tea.Cmd {
+	if m.reopen == nil {
+		m.statusMsg = fmt.Sprintf("%s can't be reloaded", m.idx.Name())
+		return nil
+	}
+	idx, err := m.reopen()
+	if err != nil {
+		m.statusMsg = fmt.Sprintf("reload failed: %v", err)
+		return nil
+	}
+
+	line := m.cursorLine()
+	// Background scans of the old index read data it keeps in memory, so
+	// closing it under them is safe; their results are dropped below
+	_ = m.idx.Close()
+	m.idx = idx
+	wasIndexing := m.indexing
+	done, lines := idx.IndexProgress()
+	m.indexing, m.indexedLines = !done, lines
+
+	m.visual = false
+	m.stats = nil
+	m.detailOffset = 0
+	m.detailHOffset = 0
+	for r, n := range m.marks {
+		if n > idx.LineCount() {
+			delete(m.marks, r)
+		}
+	}
+
+	m.parseErrs = parseErrors{gen: m.parseErrs.gen + 1}
+	m.formatLogged = nil
+	cmds := []tea.Cmd{scanParseErrors(idx, m.parseErrs.gen, idx.LineCount(), m.parser.Format(), m.logger)}
+	if m.regex != nil {
+		cmds = append(cmds, m.setRegexFilter(m.regex.pattern))
+	}
+	if m.highlight != nil {
+		cmds = append(cmds, m.setHighlight(m.highlight.pattern))
+	}
+	if m.indexing && !wasIndexing {
+		cmds = append(cmds, indexTick())
+	}
+	// Every line counts as new: the search and whole-file summaries are
+	// redone, and the visible rows are rebuilt from scratch
+	m.visible = nil
+	m.linesAppended(1)
+	m.applyFilters()
+	if count := idx.LineCount(); line > count {
+		line = count
+	}
+	m.gotoLine(line)
+
+	m.statusMsg = fmt.Sprintf("reloaded %s: %d lines", idx.Name(), idx.LineCount())
+	return tea.Batch(append(cmds, m.histogramCmd())...)
+}
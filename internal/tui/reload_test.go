@@ -0,0 +1,105 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lbe/jsonlogviewer/internal/index"
+)
+
+// runCmds runs cmd and any commands it batches, feeding each message back
+// to the model, as the program would for background scans.
+func runCmds(m *Model, cmd tea.Cmd) {
+	if cmd == nil {
+		return
+	}
+	switch msg := cmd().(type) {
+	case tea.BatchMsg:
+		for _, c := range msg {
+			runCmds(m, c)
+		}
+	case nil:
+	default:
+		m.Update(msg)
+	}
+}
+
+// TestReload verifies R re-reads a file rewritten in place, rematching the
+// regex filter and keeping the cursor line when it still exists, and that
+// a deleted file leaves the old contents in place.
+func TestReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	write := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(`{"level":"info","msg":"old 1"}
+{"level":"error","msg":"old 2"}
+{"level":"info","msg":"old 3"}
+{"level":"error","msg":"old 4"}
+`)
+	idx, err := index.Open(path)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	m := New(idx, WithReload(func() (*index.Index, error) { return index.Open(path) }))
+	defer func() { closeIndex(m.idx) }()
+	m.Update(tea.WindowSizeMsg{Width: 120, Height: 20})
+	submitRegexFilter(&m, "error")
+	sendKeys(&m, "j")
+	if m.cursorLine() != 4 {
+		t.Fatalf("expected the cursor on line 4, got %d", m.cursorLine())
+	}
+
+	// Rewritten, not appended: fewer lines and different matches
+	write(`{"level":"error","msg":"new 1"}
+{"level":"info","msg":"new 2"}
+{"level":"error","msg":"new 3"}
+`)
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("R")})
+	if m.statusMsg != "reloaded "+path+": 3 lines" {
+		t.Errorf("expected a reload status, got %q", m.statusMsg)
+	}
+	runCmds(&m, cmd)
+	if got := m.idx.LineCount(); got != 3 {
+		t.Fatalf("expected 3 lines after reload, got %d", got)
+	}
+	if !m.regexActive() || m.rowCount() != 2 || m.lineAt(1) != 1 || m.lineAt(2) != 3 {
+		t.Errorf("expected the filter to match new lines 1 and 3, got %d rows", m.rowCount())
+	}
+	if m.cursorLine() != 3 {
+		t.Errorf("expected the cursor moved to the last line, 3, got %d", m.cursorLine())
+	}
+	if raw, _ := m.idx.GetLine(1); !strings.Contains(string(raw), "new 1") {
+		t.Errorf("expected the new contents, got %q", raw)
+	}
+
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	sendKeys(&m, "R")
+	if !strings.HasPrefix(m.statusMsg, "reload failed") {
+		t.Errorf("expected a failed reload reported, got %q", m.statusMsg)
+	}
+	if raw, err := m.idx.GetLine(3); err != nil || !strings.Contains(string(raw), "new 3") {
+		t.Errorf("expected the old index kept after a failed reload, got %q, %v", raw, err)
+	}
+}
+
+// TestReloadStdin verifies R explains that a source without WithReload,
+// such as stdin, can't be reloaded.
+func TestReloadStdin(t *testing.T) {
+	idx := createTestIndex(t, `{"msg":"a"}`)
+	defer closeIndex(idx)
+
+	m := New(idx)
+	sendKeys(&m, "R")
+	if m.statusMsg != "test can't be reloaded" {
+		t.Errorf("unexpected status %q", m.statusMsg)
+	}
+}
@@ -0,0 +1,32 @@
+package tui
+
+import "github.com/lbe/jsonlogviewer/internal/parser"
+
+// cursorSchema returns the keys the cursor line's time, level, and message
+// were read from. The result is cached for the line, so redrawing the
+// status line doesn't re-parse it.
+func (m *Model) cursorSchema() (parser.Schema, bool) {
+	n := m.cursorLine()
+	if n != m.schemaLine {
+		raw, err := m.idx.GetLine(n)
+		if err != nil {
+			return parser.Schema{}, false
+		}
+		m.schema = m.parser.DetectSchema(raw)
+		m.schemaLine = n
+	}
+	return m.schema, true
+}
+
+// schemaState describes the cursor line's schema for the status line,
+// e.g. "schema: ts/severity/message".
+func (m *Model) schemaState() string {
+	if m.rowCount() == 0 {
+		return ""
+	}
+	schema, ok := m.cursorSchema()
+	if !ok {
+		return ""
+	}
+	return "schema: " + schema.String()
+}
@@ -0,0 +1,36 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lbe/jsonlogviewer/internal/parser"
+)
+
+// TestSchemaState verifies the status line shows the cursor line's schema
+// and reuses the cached schema until the cursor changes lines.
+func TestSchemaState(t *testing.T) {
+	content := `{"time":"2024-01-01T00:00:00Z","level":"info","msg":"a"}
+{"ts":1700000000,"severity":"warn","message":"b"}`
+	idx := createTestIndex(t, content)
+	defer closeIndex(idx)
+
+	m := New(idx)
+	m.width = 200
+	m.height = 20
+
+	if !strings.Contains(m.View(), "schema: time/level/msg") {
+		t.Error("expected the first line's schema in the status line")
+	}
+
+	// A cached schema is reused while the cursor stays on its line
+	m.schema = parser.Schema{Time: "cached"}
+	if got := m.schemaState(); got != "schema: cached/-/-" {
+		t.Errorf("expected the cached schema, got %q", got)
+	}
+
+	sendKeys(&m, "j")
+	if got := m.schemaState(); got != "schema: ts/severity/message" {
+		t.Errorf("expected the second line's schema, got %q", got)
+	}
+}
@@ -0,0 +1,42 @@
+package tui
+
+// scrollbarThumb returns the first track cell and the number of cells the
+// thumb covers on a track of height cells, for a view of height rows
+// starting at 1-indexed row offset out of total rows. The thumb fills the
+// track when everything fits, and is always at least one cell so it stays
+// visible in a million-line file.
+func scrollbarThumb(height, total, offset int) (top, size int) {
+	if height < 1 {
+		return 0, 0
+	}
+	if total <= height {
+		return 0, height
+	}
+	size = max(height*height/total, 1)
+	// Spread the offsets over the cells the thumb can start at, so the
+	// first row puts it at the top and the last page at the bottom
+	top = (offset - 1) * (height - size) / (total - height)
+	return min(max(top, 0), height-size), size
+}
+
+// separatorRows returns the column drawn between the panes for each data
+// row. With the scrollbar on, the rows the thumb covers are drawn heavy to
+// show where the view sits in the table; the column is the separator
+// either way, so the panes keep their widths. It's colored while the
+// detail pane has the keys.
+func (m *Model) separatorRows(height int) []string {
+	rows := make([]string, height)
+	top, size := -1, 0
+	if m.showScrollbar {
+		top, size = scrollbarThumb(height, m.rowCount(), m.viewport.Offset)
+	}
+	style := m.separatorStyle()
+	for i := range rows {
+		if i >= top && i < top+size {
+			rows[i] = style.Render("┃")
+		} else {
+			rows[i] = style.Render("│")
+		}
+	}
+	return rows
+}
@@ -0,0 +1,93 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// TestScrollbarThumb verifies the thumb spans the track when everything
+// fits, shrinks with the share of rows shown but never below one cell,
+// and runs from the top at the first row to the bottom on the last page.
+func TestScrollbarThumb(t *testing.T) {
+	tests := []struct {
+		name                  string
+		height, total, offset int
+		wantTop, wantSize     int
+	}{
+		{"fits", 10, 5, 1, 0, 10},
+		{"exact fit", 10, 10, 1, 0, 10},
+		{"top", 10, 100, 1, 0, 1},
+		{"middle", 10, 100, 46, 4, 1},
+		{"last page", 10, 100, 91, 9, 1},
+		{"half shown", 10, 20, 1, 0, 5},
+		{"half shown at end", 10, 20, 11, 5, 5},
+		{"million lines", 20, 1_000_000, 500_000, 9, 1},
+		{"no track", 0, 100, 1, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			top, size := scrollbarThumb(tt.height, tt.total, tt.offset)
+			if top != tt.wantTop || size != tt.wantSize {
+				t.Errorf("expected thumb at %d size %d, got %d size %d", tt.wantTop, tt.wantSize, top, size)
+			}
+		})
+	}
+}
+
+// TestScrollbar verifies zm draws the thumb on the separator where the
+// view sits, without changing the width of any screen line.
+func TestScrollbar(t *testing.T) {
+	var content strings.Builder
+	for i := 0; i < 200; i++ {
+		fmt.Fprintf(&content, `{"level":"info","msg":"line %d"}`+"\n", i+1)
+	}
+	idx := createTestIndex(t, content.String())
+	defer closeIndex(idx)
+
+	m := New(idx)
+	m.Update(tea.WindowSizeMsg{Width: 160, Height: 30})
+
+	widths := func() []int {
+		var w []int
+		for _, line := range strings.Split(m.View(), "\n") {
+			w = append(w, ansi.StringWidth(line))
+		}
+		return w
+	}
+	before := widths()
+	if strings.Contains(m.View(), "┃") {
+		t.Fatal("expected no scrollbar by default")
+	}
+
+	sendKeys(&m, "zm")
+	if fmt.Sprint(widths()) != fmt.Sprint(before) {
+		t.Errorf("expected line widths %v, got %v", before, widths())
+	}
+	thumbRows := func() []int {
+		var rows []int
+		for i, line := range strings.Split(m.View(), "\n") {
+			if strings.Contains(line, "┃") {
+				rows = append(rows, i)
+			}
+		}
+		return rows
+	}
+	// The data rows start below the app and column headers
+	if rows := thumbRows(); len(rows) == 0 || rows[0] != 2 {
+		t.Errorf("expected the thumb at the top of the track, got screen rows %v", rows)
+	}
+	sendKeys(&m, "G")
+	if rows := thumbRows(); len(rows) == 0 || rows[len(rows)-1] != 2+m.viewport.Height-1 {
+		t.Errorf("expected the thumb at the bottom of the track, got screen rows %v", rows)
+	}
+
+	sendKeys(&m, "zm")
+	if len(thumbRows()) != 0 {
+		t.Error("expected zm to hide the scrollbar again")
+	}
+}
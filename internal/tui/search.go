@@ -0,0 +1,117 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// search finds every line matching query (see searchPredicate) and moves
+// to the first match after the cursor. The matches are kept so n and N
+// step through them without rescanning the file. An empty query repeats
+// the last search, and an invalid one reopens the prompt with the error.
+func (m *Model) search(query string) {
+	if query == "" {
+		query = m.searchQuery
+	}
+	if query == "" {
+		return
+	}
+
+	if query != m.searchQuery || m.searchMatches == nil {
+		match, err := searchPredicate(query)
+		if err != nil {
+			m.openPrompt(promptSearch)
+			m.promptInput = query
+			m.promptErr = err.Error()
+			return
+		}
+		matches := make([]int, 0)
+		for n := 1; n <= m.idx.LineCount(); n++ {
+			line, err := m.idx.GetLine(n)
+			if err == nil && match(line) {
+				matches = append(matches, n)
+			}
+		}
+		m.searchQuery = query
+		m.searchMatches = matches
+	}
+	m.searchNext(1)
+}
+
+// searchNext moves to the next (dir > 0) or previous (dir < 0) match of
+// the last search among the lines shown, wrapping around the file like
+// vim and saying so in the status line. The match is scrolled to the
+// middle of the table so the lines before it are visible.
+func (m *Model) searchNext(dir int) {
+	if m.searchQuery == "" {
+		m.statusMsg = "no previous search (use / to search)"
+		return
+	}
+
+	if _, total := m.searchCount(); total == 0 {
+		m.statusMsg = "pattern not found: " + m.searchQuery
+		return
+	}
+
+	isMatch := func(row int) bool { return m.isSearchMatch(m.lineAt(row)) }
+	from := m.viewport.Cursor
+	if dir > 0 {
+		m.viewport.NextMatch(isMatch, true)
+	} else {
+		m.viewport.PrevMatch(isMatch, true)
+	}
+	to := m.viewport.Cursor
+	wrapped := (dir > 0 && to <= from) || (dir < 0 && to >= from)
+
+	m.viewport.Center(to)
+	i, total := m.searchCount()
+	m.statusMsg = fmt.Sprintf("/%s: match %d/%d", m.searchQuery, i, total)
+	if wrapped && dir > 0 {
+		m.statusMsg += " (search hit BOTTOM, continuing at TOP)"
+	} else if wrapped {
+		m.statusMsg += " (search hit TOP, continuing at BOTTOM)"
+	}
+}
+
+// isSearchMatch reports whether file line n matches the active search.
+func (m *Model) isSearchMatch(n int) bool {
+	i := sort.SearchInts(m.searchMatches, n)
+	return i < len(m.searchMatches) && m.searchMatches[i] == n
+}
+
+// clearSearch forgets the last search and its detail highlighting.
+func (m *Model) clearSearch() {
+	m.searchQuery = ""
+	m.searchMatches = nil
+}
+
+// markSearch wraps each case-insensitive occurrence of the search query,
+// or of the value a field search looks for, in s with the search match
+// style. s must be plain text.
+func (m *Model) markSearch(s string) string {
+	mark := searchMark(m.searchQuery)
+	if mark == "" {
+		return s
+	}
+	lower := strings.ToLower(s)
+	needle := strings.ToLower(mark)
+	if len(lower) != len(s) {
+		// Case folding changed byte offsets; leave the line unmarked
+		// rather than mark the wrong text
+		return s
+	}
+
+	var b strings.Builder
+	for {
+		i := strings.Index(lower, needle)
+		if i < 0 {
+			break
+		}
+		b.WriteString(s[:i])
+		b.WriteString(m.styles.SearchMatch.Render(s[i : i+len(needle)]))
+		s, lower = s[i+len(needle):], lower[i+len(needle):]
+	}
+	b.WriteString(s)
+	return b.String()
+}
@@ -0,0 +1,196 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+)
+
+const searchContent = `{"level":"info","msg":"Disk ok"}
+{"level":"info","msg":"tick"}
+{"level":"error","msg":"DISK full"}
+{"level":"info","msg":"tick"}
+{"level":"warn","msg":"disk slow"}`
+
+// TestSearch verifies / finds the next case-insensitive match after the
+// cursor and n/N step through matches, wrapping at either end.
+func TestSearch(t *testing.T) {
+	idx := createTestIndex(t, searchContent)
+	defer closeIndex(idx)
+
+	m := New(idx)
+	m.width = 120
+	m.height = 30
+
+	sendKeys(&m, "/disk\n")
+	if m.cursorLine() != 3 {
+		t.Fatalf("expected first match after the cursor on line 3, got %d", m.cursorLine())
+	}
+	if len(m.searchMatches) != 3 {
+		t.Errorf("expected 3 stored matches, got %v", m.searchMatches)
+	}
+
+	steps := []struct {
+		key     string
+		want    int
+		wrapped bool
+	}{
+		{"n", 5, false},
+		{"n", 1, true},
+		{"N", 5, true},
+		{"N", 3, false},
+	}
+	for _, s := range steps {
+		sendKeys(&m, s.key)
+		if m.cursorLine() != s.want {
+			t.Errorf("after %s: expected line %d, got %d", s.key, s.want, m.cursorLine())
+		}
+		if got := strings.Contains(m.statusMsg, "continuing"); got != s.wrapped {
+			t.Errorf("after %s: expected wrapped=%v, status %q", s.key, s.wrapped, m.statusMsg)
+		}
+	}
+
+	sendKeys(&m, "/nowhere\n")
+	if m.cursorLine() != 3 || !strings.Contains(m.statusMsg, "not found") {
+		t.Errorf("expected cursor to stay put with a not-found message, got line %d, %q", m.cursorLine(), m.statusMsg)
+	}
+}
+
+// TestSearchCenters verifies a search match is scrolled to the middle of
+// the table, with the lines before it in view.
+func TestSearchCenters(t *testing.T) {
+	tick := strings.Repeat(`{"level":"info","msg":"tick"}`+"\n", 30)
+	idx := createTestIndex(t, tick+`{"level":"error","msg":"boom"}`+"\n"+tick)
+	defer closeIndex(idx)
+
+	m := New(idx)
+	m.viewport.SetHeight(20)
+
+	sendKeys(&m, "/boom\n")
+	if m.cursorLine() != 31 {
+		t.Fatalf("expected the match on line 31, got %d", m.cursorLine())
+	}
+	if m.viewport.Offset != 21 {
+		t.Errorf("expected line 31 centered at offset 21, got %d", m.viewport.Offset)
+	}
+}
+
+// TestSearchSkipsFilteredLines verifies n only visits lines the filters
+// show.
+func TestSearchSkipsFilteredLines(t *testing.T) {
+	idx := createTestIndex(t, searchContent)
+	defer closeIndex(idx)
+
+	m := New(idx)
+	sendKeys(&m, "++++") // ≥WARN: lines 3 and 5
+
+	sendKeys(&m, "/disk\n")
+	if m.cursorLine() != 5 {
+		t.Errorf("expected the next shown match on line 5, got %d", m.cursorLine())
+	}
+	sendKeys(&m, "n")
+	if m.cursorLine() != 3 {
+		t.Errorf("expected to wrap to line 3, skipping hidden line 1, got %d", m.cursorLine())
+	}
+}
+
+// TestSearchRowStyle verifies rows matching the search get the match style
+// away from the cursor, the cursor row keeps the selected style, and the
+// style goes with the search.
+func TestSearchRowStyle(t *testing.T) {
+	idx := createTestIndex(t, searchContent)
+	defer closeIndex(idx)
+
+	m := New(idx)
+	m.width = 120
+	m.height = 30
+
+	sendKeys(&m, "/disk\n")
+	wantBg := m.styles.Match.GetBackground()
+	for n := 1; n <= idx.LineCount(); n++ {
+		got := m.rowStyle(n, mustParse(t, &m, n)).GetBackground()
+		switch {
+		case n == m.cursorLine():
+			if got != m.styles.Selected.GetBackground() {
+				t.Errorf("line %d: expected the cursor's selected style to win", n)
+			}
+		case n == 1 || n == 5:
+			if got != wantBg {
+				t.Errorf("line %d: expected the match background", n)
+			}
+		default:
+			if got == wantBg {
+				t.Errorf("line %d: unexpected match background", n)
+			}
+		}
+	}
+
+	m.clearSearch()
+	if m.rowStyle(5, mustParse(t, &m, 5)).GetBackground() == wantBg {
+		t.Error("expected no match background once the search is cleared")
+	}
+}
+
+// TestMarkSearch verifies matches are marked case-insensitively in the
+// detail pane.
+func TestMarkSearch(t *testing.T) {
+	idx := createTestIndex(t, searchContent)
+	defer closeIndex(idx)
+
+	m := New(idx)
+	m.searchQuery = "disk"
+
+	mark := m.styles.SearchMatch.Render("DISK")
+	got := m.markSearch(`"msg": "DISK full, disk"`)
+	want := `"msg": "` + mark + ` full, ` + m.styles.SearchMatch.Render("disk") + `"`
+	if got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+
+	m.clearSearch()
+	if got := m.markSearch("disk"); got != "disk" {
+		t.Errorf("expected no marks without a search, got %q", got)
+	}
+}
+
+// TestFieldSearch verifies field:path=value and field:path~regex match
+// only the named field, step with n, and reject a bad regex.
+func TestFieldSearch(t *testing.T) {
+	content := `{"request_id":"abc-1","msg":"start"}
+{"request_id":"xyz","msg":"retry of abc-1"}
+{"request_id":"abc-1","msg":"done"}
+{"request_id":"abc-2","msg":"start"}`
+	idx := createTestIndex(t, content)
+	defer closeIndex(idx)
+
+	m := New(idx)
+	m.width = 120
+	m.height = 30
+
+	sendKeys(&m, "/field:request_id=abc-1\n")
+	if len(m.searchMatches) != 2 || m.cursorLine() != 3 {
+		t.Fatalf("expected lines 1 and 3 to match with the cursor on 3, got %v on %d", m.searchMatches, m.cursorLine())
+	}
+	sendKeys(&m, "n")
+	if m.cursorLine() != 1 {
+		t.Errorf("expected n to wrap to line 1, got %d", m.cursorLine())
+	}
+	if got := searchMark(m.searchQuery); got != "abc-1" {
+		t.Errorf("expected the detail pane to mark the searched value, got %q", got)
+	}
+
+	sendKeys(&m, "/field:request_id~^abc-\\d$\n")
+	if len(m.searchMatches) != 3 {
+		t.Errorf("expected the regex to match lines 1, 3, and 4, got %v", m.searchMatches)
+	}
+
+	// The & filter's path=~regex form works the same way
+	sendKeys(&m, "/request_id=~^abc-\\d$\n")
+	if len(m.searchMatches) != 3 || searchMark(m.searchQuery) != "" {
+		t.Errorf("expected path=~regex to match lines 1, 3, and 4 unmarked, got %v", m.searchMatches)
+	}
+
+	sendKeys(&m, "/field:request_id~(\n")
+	if m.prompt != promptSearch || m.promptErr == "" {
+		t.Errorf("expected a bad regex to reopen the prompt with an error, got prompt %v, err %q", m.prompt, m.promptErr)
+	}
+}
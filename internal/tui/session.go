@@ -0,0 +1,103 @@
+package tui
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Session is the view state kept from one run to the next, read from and
+// written to a JSON file such as:
+//
+//	{"columns": "level,time,request_id:Request,msg"}
+type Session struct {
+	// Columns is the table layout in display order, in -columns form.
+	Columns string `json:"columns,omitempty"`
+}
+
+// LoadSession reads and validates the session file at path. A missing file
+// returns an error satisfying os.IsNotExist.
+func LoadSession(path string) (*Session, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseSession(data)
+}
+
+// ParseSession parses a JSON session, rejecting unknown keys and column
+// layouts that -columns wouldn't accept.
+func ParseSession(data []byte) (*Session, error) {
+	var session Session
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&session); err != nil {
+		return nil, fmt.Errorf("invalid session: %w", err)
+	}
+	if session.Columns != "" {
+		if _, err := parseColumns(session.Columns); err != nil {
+			return nil, fmt.Errorf("invalid session: columns: %w", err)
+		}
+	}
+	return &session, nil
+}
+
+// SaveSession writes s to path, creating its directory if needed.
+func SaveSession(path string, s Session) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// Session returns the view state worth restoring next time: the columns
+// as they are now, including any reordering done in column mode.
+func (m *Model) Session() Session {
+	return Session{Columns: columnSpec(m.columns)}
+}
+
+// WithSession starts the table with the columns saved in s, in their saved
+// order. Ctrl+r still resets to the layout from WithColumns or the
+// defaults. A nil session, or one without columns, changes nothing.
+func WithSession(s *Session) Option {
+	return func(m *Model) {
+		if s == nil || s.Columns == "" {
+			return
+		}
+		cols, err := parseColumns(s.Columns)
+		if err != nil {
+			return
+		}
+		m.columns = cols
+	}
+}
+
+// columnSpec is the inverse of parseColumns: it describes cols in -columns
+// form, adding a title only where it differs from the one parseColumns
+// would give.
+func columnSpec(cols []column) string {
+	titles := make(map[string]string)
+	for _, col := range defaultColumns() {
+		titles[col.key] = col.title
+	}
+	items := make([]string, 0, len(cols))
+	for _, col := range cols {
+		title, builtin := titles[col.key]
+		if !builtin {
+			title = col.key
+		}
+		item := col.key
+		if col.title != title {
+			item += ":" + col.title
+		}
+		items = append(items, item)
+	}
+	return strings.Join(items, ",")
+}
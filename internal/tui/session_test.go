@@ -0,0 +1,50 @@
+package tui
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestSessionColumnOrder verifies a column order set in column mode is
+// saved, read back, and restored by the next model, while Ctrl+r still
+// resets to the default layout.
+func TestSessionColumnOrder(t *testing.T) {
+	content := `{"time":"2024-01-01T00:00:00Z","level":"info","msg":"test message","req":"r1"}`
+	idx := createTestIndex(t, content)
+	defer closeIndex(idx)
+
+	m := New(idx)
+	if err := m.SetColumns("time,level,req:Request,msg"); err != nil {
+		t.Fatalf("SetColumns: %v", err)
+	}
+	sendKeys(&m, "C>>\n")
+
+	path := filepath.Join(t.TempDir(), "jsonlogviewer", "session.json")
+	if err := SaveSession(path, m.Session()); err != nil {
+		t.Fatalf("SaveSession: %v", err)
+	}
+	session, err := LoadSession(path)
+	if err != nil {
+		t.Fatalf("LoadSession: %v", err)
+	}
+	if want := "level,req:Request,time,msg"; session.Columns != want {
+		t.Errorf("expected saved columns %q, got %q", want, session.Columns)
+	}
+
+	restored := New(idx, WithSession(session))
+	header := restored.renderTableHeader()
+	lvl, req, tm := strings.Index(header, "Lvl"), strings.Index(header, "Request"), strings.Index(header, "Time")
+	if lvl < 0 || !(lvl < req && req < tm) {
+		t.Errorf("expected Lvl, Request, Time in order, got %q", header)
+	}
+
+	restored.resetView()
+	if got := columnSpec(restored.columns); got != "time,level,msg" {
+		t.Errorf("expected reset to the default columns, got %q", got)
+	}
+
+	if _, err := ParseSession([]byte(`{"columns":"time,,msg"}`)); err == nil {
+		t.Error("expected an invalid column layout to be rejected")
+	}
+}
@@ -0,0 +1,213 @@
+package tui
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+
+	"github.com/lbe/jsonlogviewer/internal/parser"
+)
+
+// sortOrder is a sort of the table rows by one column. The rows are still
+// the lines the filters show, only in another order; the detail pane and
+// everything naming a file line are unaffected.
+type sortOrder struct {
+	// column is the key of the column sorted by.
+	column string
+	// desc sorts from the largest value down.
+	desc bool
+	// entries holds the parsed entry of each file line, by line number;
+	// lines that don't parse are nil.
+	entries []*parser.LogEntry
+	// rows lists the file lines shown, in display order.
+	rows []int
+	// pos maps a file line to its 1-indexed row, or 0 when it isn't shown.
+	pos []int
+}
+
+// sortByColumn sorts the table by the selected column, or reverses the
+// sort when it's already sorted by it. Every line is parsed once, with
+// ParseAll, so sorting suits files that fit in memory.
+func (m *Model) sortByColumn() {
+	col := m.columns[m.selectedColumn]
+	line := m.cursorLine()
+	if m.sorted != nil && m.sorted.column == col.key {
+		m.sorted.desc = !m.sorted.desc
+	} else {
+		// Lines that don't parse are left out of entries and sort last,
+		// so the error listing them isn't needed
+		all, _ := m.parser.ParseAll(m.idx)
+		entries := make([]*parser.LogEntry, m.idx.LineCount()+1)
+		for _, e := range all {
+			if e.Row < len(entries) {
+				entries[e.Row] = e
+			}
+		}
+		m.sorted = &sortOrder{column: col.key, entries: entries}
+	}
+
+	m.resort()
+	m.viewport.SetTotalLines(m.rowCount())
+	if line > 0 {
+		m.gotoLine(line)
+	}
+	m.statusMsg = "sorted by " + m.sortState() + ": S in column mode returns to file order"
+}
+
+// clearSort puts the rows back in file order, keeping the cursor line.
+func (m *Model) clearSort() {
+	if m.sorted == nil {
+		return
+	}
+	line := m.cursorLine()
+	m.sorted = nil
+	m.viewport.SetTotalLines(m.rowCount())
+	if line > 0 {
+		m.gotoLine(line)
+	}
+	m.statusMsg = "rows in file order"
+}
+
+// sortColumn returns the column the rows are sorted by, reporting false
+// when it's no longer in the layout.
+func (m *Model) sortColumn() (column, bool) {
+	i := slices.IndexFunc(m.columns, func(c column) bool { return c.key == m.sorted.column })
+	if i < 0 {
+		return column{}, false
+	}
+	return m.columns[i], true
+}
+
+// resort rebuilds the sorted rows from the lines the filters show, for
+// when the sort, the filters, or the file change. Ties keep file order.
+// The caller updates the viewport.
+func (m *Model) resort() {
+	s := m.sorted
+	col, ok := m.sortColumn()
+	if !ok {
+		m.sorted = nil
+		return
+	}
+
+	var rows []int
+	if m.visible != nil {
+		rows = slices.Clone(m.visible)
+	} else {
+		rows = make([]int, m.idx.LineCount())
+		for i := range rows {
+			rows[i] = i + 1
+		}
+	}
+
+	// Cell text is extracted once per line rather than per comparison
+	var values []string
+	if col.key != "time" && col.key != "level" {
+		values = make([]string, len(s.entries))
+		for _, n := range rows {
+			if e := s.entry(n); e != nil {
+				values[n] = col.value(e)
+			}
+		}
+	}
+	slices.SortStableFunc(rows, func(a, b int) int {
+		return s.compare(col.key, values, a, b)
+	})
+
+	s.rows = rows
+	s.pos = make([]int, len(s.entries))
+	for i, n := range rows {
+		s.pos[n] = i + 1
+	}
+}
+
+// extendSort parses lines from onward into the sort after the index grew
+// and re-sorts. The caller updates the viewport.
+func (m *Model) extendSort(from int) {
+	s := m.sorted
+	s.entries = s.entries[:min(from, len(s.entries))]
+	for n := from; n <= m.idx.LineCount(); n++ {
+		var entry *parser.LogEntry
+		if raw, err := m.idx.GetLine(n); err == nil {
+			if e, err := m.parser.Parse(raw, n); err == nil {
+				entry = e
+			}
+		}
+		s.entries = append(s.entries, entry)
+	}
+	m.resort()
+}
+
+// entry returns the parsed entry of file line n, or nil if it didn't parse.
+func (s *sortOrder) entry(n int) *parser.LogEntry {
+	if n < len(s.entries) {
+		return s.entries[n]
+	}
+	return nil
+}
+
+// compare orders file lines a and b by the column with the given key:
+// time by parsed timestamp, level by severity, and other columns by the
+// text in values. Lines without a value, including those that don't
+// parse, go last whichever way the sort runs.
+func (s *sortOrder) compare(key string, values []string, a, b int) int {
+	ea, eb := s.entry(a), s.entry(b)
+	missA, missB := sortMissing(key, values, ea, a), sortMissing(key, values, eb, b)
+	if missA || missB {
+		if missA == missB {
+			return 0
+		}
+		if missA {
+			return 1
+		}
+		return -1
+	}
+
+	var c int
+	switch key {
+	case "time":
+		c = ea.Timestamp.Compare(eb.Timestamp)
+	case "level":
+		c = cmp.Compare(parser.LevelRank(ea.CanonicalLevel), parser.LevelRank(eb.CanonicalLevel))
+	default:
+		c = strings.Compare(values[a], values[b])
+	}
+	if s.desc {
+		return -c
+	}
+	return c
+}
+
+// sortMissing reports whether line n, parsed as e, has no value to sort
+// by in the column with the given key.
+func sortMissing(key string, values []string, e *parser.LogEntry, n int) bool {
+	switch {
+	case e == nil:
+		return true
+	case key == "time":
+		return e.Timestamp.IsZero()
+	case key == "level":
+		return parser.LevelRank(e.CanonicalLevel) == 0
+	}
+	return values[n] == ""
+}
+
+// arrow marks the sort direction, ▲ for ascending and ▼ for descending.
+func (s *sortOrder) arrow() string {
+	if s.desc {
+		return "▼"
+	}
+	return "▲"
+}
+
+// sortState describes the sort for the status line, e.g. "Time▲", or ""
+// when the rows are in file order.
+func (m *Model) sortState() string {
+	if m.sorted == nil {
+		return ""
+	}
+	col, ok := m.sortColumn()
+	if !ok {
+		return ""
+	}
+	return col.title + m.sorted.arrow()
+}
@@ -0,0 +1,135 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/lbe/jsonlogviewer/internal/index"
+)
+
+// sortContent has lines out of time order, a line that isn't JSON, and one
+// without a time.
+const sortContent = `{"time":"2024-01-01T00:00:03Z","level":"error","msg":"c"}
+{"time":"2024-01-01T00:00:01Z","level":"error","msg":"a"}
+not json
+{"level":"warn","msg":"untimed"}
+{"time":"2024-01-01T00:00:02Z","level":"debug","msg":"b"}`
+
+// sortedRows returns the file lines of the table rows in display order.
+func sortedRows(m *Model) []int {
+	rows := make([]int, m.rowCount())
+	for i := range rows {
+		rows[i] = m.lineAt(i + 1)
+	}
+	return rows
+}
+
+// TestSortByColumn verifies s in column mode sorts the rows by the
+// selected column with lines lacking a value last, s again reverses, and S
+// returns to file order, keeping the cursor line throughout.
+func TestSortByColumn(t *testing.T) {
+	idx := createTestIndex(t, sortContent)
+	defer closeIndex(idx)
+
+	m := New(idx)
+	m.width = 120
+	m.height = 30
+	m.gotoLine(1)
+
+	sendKeys(&m, "Cs")
+	if got, want := sortedRows(&m), []int{2, 5, 1, 3, 4}; !slices.Equal(got, want) {
+		t.Errorf("expected time order %v, got %v", want, got)
+	}
+	if m.cursorLine() != 1 {
+		t.Errorf("expected the cursor to stay on line 1, got %d", m.cursorLine())
+	}
+	if header := m.renderTableHeader(); !strings.Contains(header, "Time▲") {
+		t.Errorf("expected the header to mark the sorted column, got %q", header)
+	}
+	if state := m.activeState(); !strings.Contains(state, "sort:Time▲") {
+		t.Errorf("expected the sort in the active state, got %q", state)
+	}
+
+	// Reversing keeps lines without a time last
+	sendKeys(&m, "s")
+	if got, want := sortedRows(&m), []int{1, 5, 2, 3, 4}; !slices.Equal(got, want) {
+		t.Errorf("expected reverse time order %v, got %v", want, got)
+	}
+
+	// Level sorts by severity, ties in file order
+	sendKeys(&m, "ls")
+	if got, want := sortedRows(&m), []int{5, 4, 1, 2, 3}; !slices.Equal(got, want) {
+		t.Errorf("expected level order %v, got %v", want, got)
+	}
+
+	// The detail pane still shows the line under the cursor
+	m.gotoLine(4)
+	if m.viewport.Cursor != 2 {
+		t.Errorf("expected line 4 on row 2, got row %d", m.viewport.Cursor)
+	}
+	if detail := m.renderDetail(20); !strings.Contains(detail, "untimed") {
+		t.Errorf("expected the detail of line 4, got %q", detail)
+	}
+
+	sendKeys(&m, "S")
+	if got, want := sortedRows(&m), []int{1, 2, 3, 4, 5}; !slices.Equal(got, want) {
+		t.Errorf("expected file order %v, got %v", want, got)
+	}
+	if m.cursorLine() != 4 || m.activeState() != "" {
+		t.Errorf("expected line 4 and no active state, got line %d, state %q", m.cursorLine(), m.activeState())
+	}
+}
+
+// TestSortWithFilter verifies a sort orders only the lines the filters
+// show and follows the filters as they change.
+func TestSortWithFilter(t *testing.T) {
+	idx := createTestIndex(t, sortContent)
+	defer closeIndex(idx)
+
+	m := New(idx)
+	sendKeys(&m, "Cs\n")
+	sendKeys(&m, "+++++") // ≥ERROR
+	if got, want := sortedRows(&m), []int{2, 1}; !slices.Equal(got, want) {
+		t.Errorf("expected error lines in time order %v, got %v", want, got)
+	}
+
+	m.clearFilters()
+	if got, want := sortedRows(&m), []int{2, 5, 1, 3, 4}; !slices.Equal(got, want) {
+		t.Errorf("expected every line in time order %v, got %v", want, got)
+	}
+}
+
+// TestSortFollow verifies lines appended while sorted take their place in
+// the sort.
+func TestSortFollow(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	if err := os.WriteFile(path, []byte(sortContent+"\n"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	idx, err := index.Open(path)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer closeIndex(idx)
+
+	m := New(idx)
+	m.SetFollow(true)
+	sendKeys(&m, "Cs\n")
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatalf("failed to open file: %v", err)
+	}
+	if _, err := f.WriteString(`{"time":"2024-01-01T00:00:00Z","level":"info","msg":"first"}` + "\n"); err != nil {
+		t.Fatalf("failed to append: %v", err)
+	}
+	_ = f.Close()
+	m.Update(followTickMsg{})
+
+	if got, want := sortedRows(&m), []int{6, 2, 5, 1, 3, 4}; !slices.Equal(got, want) {
+		t.Errorf("expected the new line sorted first %v, got %v", want, got)
+	}
+}
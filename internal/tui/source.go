@@ -0,0 +1,76 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lbe/jsonlogviewer/internal/parser"
+)
+
+// editorDoneMsg is sent when the external editor started by gf exits.
+type editorDoneMsg struct {
+	err error
+}
+
+// SetSourceDir sets the directory relative source.file paths are resolved
+// against when opening them with gf. Empty means the working directory.
+func (m *Model) SetSourceDir(dir string) {
+	m.sourceDir = dir
+}
+
+// sourceLocation extracts the code location recorded in a log line's
+// source object (as written by log/slog's AddSource), resolving a relative
+// file against baseDir. Returns false if there's no source.file.
+func sourceLocation(raw []byte, baseDir string) (path string, line int, ok bool) {
+	path = parser.ExtractField(raw, "source.file")
+	if path == "" {
+		return "", 0, false
+	}
+	if !filepath.IsAbs(path) && baseDir != "" {
+		path = filepath.Join(baseDir, path)
+	}
+	line, err := strconv.Atoi(parser.ExtractField(raw, "source.line"))
+	if err != nil || line < 1 {
+		line = 1
+	}
+	return path, line, true
+}
+
+// editorCommand builds the command opening path at line in $EDITOR,
+// falling back to vi. Arguments in $EDITOR, as in "emacsclient -t", are
+// kept. The +line argument is understood by vi, vim, nano, and emacs.
+func editorCommand(path string, line int) *exec.Cmd {
+	args := strings.Fields(os.Getenv("EDITOR"))
+	if len(args) == 0 {
+		args = []string{"vi"}
+	}
+	return exec.Command(args[0], append(args[1:], "+"+strconv.Itoa(line), path)...)
+}
+
+// openSourceFile opens the cursor line's source.file in the editor,
+// suspending the TUI until it exits. Problems are reported in the status
+// line instead.
+func (m *Model) openSourceFile() tea.Cmd {
+	raw, err := m.idx.GetLine(m.cursorLine())
+	if err != nil {
+		m.statusMsg = fmt.Sprintf("cannot read line: %v", err)
+		return nil
+	}
+	path, line, ok := sourceLocation(raw, m.sourceDir)
+	if !ok {
+		m.statusMsg = "no source.file in this entry"
+		return nil
+	}
+	if _, err := os.Stat(path); err != nil {
+		m.statusMsg = fmt.Sprintf("source file not found: %s", path)
+		return nil
+	}
+	return tea.ExecProcess(editorCommand(path, line), func(err error) tea.Msg {
+		return editorDoneMsg{err: err}
+	})
+}
@@ -0,0 +1,95 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// TestSourceLocation verifies source.file and source.line extraction and
+// relative path resolution.
+func TestSourceLocation(t *testing.T) {
+	tests := []struct {
+		name     string
+		raw      string
+		baseDir  string
+		wantPath string
+		wantLine int
+		wantOK   bool
+	}{
+		{"absolute path", `{"source":{"file":"/src/app/main.go","line":42}}`, "/other", "/src/app/main.go", 42, true},
+		{"relative path", `{"source":{"file":"app/main.go","line":7}}`, "/src", "/src/app/main.go", 7, true},
+		{"relative without base", `{"source":{"file":"main.go","line":7}}`, "", "main.go", 7, true},
+		{"missing line", `{"source":{"file":"/src/main.go"}}`, "", "/src/main.go", 1, true},
+		{"no source", `{"msg":"x"}`, "", "", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, line, ok := sourceLocation([]byte(tt.raw), tt.baseDir)
+			if ok != tt.wantOK || path != tt.wantPath || line != tt.wantLine {
+				t.Errorf("expected (%q, %d, %v), got (%q, %d, %v)",
+					tt.wantPath, tt.wantLine, tt.wantOK, path, line, ok)
+			}
+		})
+	}
+}
+
+// TestOpenSourceFile verifies gf reports missing locations in the status
+// line and only starts the editor for files that exist.
+func TestOpenSourceFile(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	content := `{"msg":"found","source":{"file":"main.go","line":1}}
+{"msg":"missing","source":{"file":"gone.go","line":3}}
+{"msg":"none"}`
+	idx := createTestIndex(t, content)
+	defer closeIndex(idx)
+
+	m := New(idx)
+	m.SetSourceDir(dir)
+
+	if cmd := m.openSourceFile(); cmd == nil {
+		t.Error("expected an editor command for an existing file")
+	}
+
+	m.viewport.Goto(2)
+	if cmd := m.openSourceFile(); cmd != nil {
+		t.Error("expected no editor command for a missing file")
+	}
+	if !strings.Contains(m.statusMsg, "not found") {
+		t.Errorf("expected a not-found message, got %q", m.statusMsg)
+	}
+
+	m.viewport.Goto(3)
+	if cmd := m.openSourceFile(); cmd != nil {
+		t.Error("expected no editor command without source.file")
+	}
+	if m.statusMsg == "" {
+		t.Error("expected a status message without source.file")
+	}
+}
+
+// TestEditorCommand verifies arguments in $EDITOR are kept ahead of the
+// line and path, and vi is the fallback.
+func TestEditorCommand(t *testing.T) {
+	tests := []struct {
+		editor string
+		want   []string
+	}{
+		{"nano", []string{"nano", "+12", "main.go"}},
+		{"emacsclient -t", []string{"emacsclient", "-t", "+12", "main.go"}},
+		{" ", []string{"vi", "+12", "main.go"}},
+	}
+	for _, tt := range tests {
+		t.Setenv("EDITOR", tt.editor)
+		if got := editorCommand("main.go", 12).Args; !slices.Equal(got, tt.want) {
+			t.Errorf("EDITOR=%q: expected %q, got %q", tt.editor, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,206 @@
+package tui
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lbe/jsonlogviewer/internal/index"
+	"github.com/lbe/jsonlogviewer/internal/parser"
+)
+
+const (
+	// histogramBuckets is the number of time buckets (and sparkline cells).
+	histogramBuckets = 32
+	// histogramSamples caps how many lines are parsed to build the histogram,
+	// so very large files don't stall the first render.
+	histogramSamples = 2000
+)
+
+// sparkBars are the glyphs used to draw the sparkline, from empty to full.
+var sparkBars = []rune(" ▁▂▃▄▅▆▇█")
+
+// histogram holds log volume bucketed over the file's time span.
+type histogram struct {
+	// counts holds the number of sampled entries in each bucket.
+	counts []int
+	// start and end bound the time span covered by the buckets.
+	start time.Time
+	end   time.Time
+}
+
+// buildHistogram buckets the given times into n equal spans between the
+// earliest and latest time. Returns nil if there are no times.
+func buildHistogram(times []time.Time, n int) *histogram {
+	if len(times) == 0 || n < 1 {
+		return nil
+	}
+
+	h := &histogram{
+		counts: make([]int, n),
+		start:  times[0],
+		end:    times[0],
+	}
+	for _, t := range times {
+		if t.Before(h.start) {
+			h.start = t
+		}
+		if t.After(h.end) {
+			h.end = t
+		}
+	}
+	for _, t := range times {
+		h.counts[h.bucket(t)]++
+	}
+	return h
+}
+
+// bucket returns the bucket index for t, clamped to the histogram range.
+// The offset is scaled in floating point, since multiplying nanoseconds by
+// the bucket count overflows for spans of centuries, as epoch-0 or garbage
+// timestamps give.
+func (h *histogram) bucket(t time.Time) int {
+	span := h.end.Sub(h.start)
+	if span <= 0 {
+		return 0
+	}
+	i := int(float64(t.Sub(h.start)) / float64(span) * float64(len(h.counts)))
+	if i < 0 {
+		i = 0
+	}
+	if i >= len(h.counts) {
+		i = len(h.counts) - 1
+	}
+	return i
+}
+
+// sparkline converts bucket counts into block glyphs scaled to the
+// largest bucket. Empty buckets are blank; any non-empty bucket gets at
+// least the lowest bar so sparse activity stays visible.
+func sparkline(counts []int) []rune {
+	maxCount := 0
+	for _, c := range counts {
+		if c > maxCount {
+			maxCount = c
+		}
+	}
+
+	bars := make([]rune, len(counts))
+	top := len(sparkBars) - 1
+	for i, c := range counts {
+		if c == 0 || maxCount == 0 {
+			bars[i] = sparkBars[0]
+			continue
+		}
+		level := c * top / maxCount
+		if level < 1 {
+			level = 1
+		}
+		bars[i] = sparkBars[level]
+	}
+	return bars
+}
+
+// histogramDoneMsg carries the histogram built in the background as gen.
+type histogramDoneMsg struct {
+	gen       int
+	histogram *histogram
+}
+
+// histogramCmd returns a command building the histogram in the background
+// when the sparkline is shown and the histogram is missing or out of
+// date, so sampling a big file doesn't stall a render. It returns nil
+// when there's nothing to build or a build is already running.
+func (m *Model) histogramCmd() tea.Cmd {
+	if !m.showSparkline || m.histogramBuilt || m.histogramPending {
+		return nil
+	}
+	m.histogramPending = true
+	return buildHistogramCmd(m.idx, m.histogramGen, m.parser.Format(), m.parser.TimeField())
+}
+
+// buildHistogramCmd returns a command sampling lines evenly across idx,
+// reading lines in format with times from timeField, and bucketing their
+// timestamps as build gen. It parses with its own parser so it doesn't
+// share state with rendering.
+func buildHistogramCmd(idx *index.Index, gen int, format parser.Format, timeField string) tea.Cmd {
+	return func() tea.Msg {
+		p := parser.New()
+		p.SetFormat(format)
+		p.SetTimeField(timeField)
+
+		total := idx.LineCount()
+		step := max(total/histogramSamples, 1)
+		var times []time.Time
+		var entry parser.LogEntry
+		for n := 1; n <= total; n += step {
+			raw, err := idx.GetLine(n)
+			if err != nil || p.ParseInto(raw, n, &entry) != nil {
+				continue
+			}
+			if t, ok := parser.ParseTime(entry.Time); ok {
+				times = append(times, t)
+			}
+		}
+		return histogramDoneMsg{gen: gen, histogram: buildHistogram(times, histogramBuckets)}
+	}
+}
+
+// histogramDone installs a finished histogram, unless the data changed
+// since it was started.
+func (m *Model) histogramDone(msg histogramDoneMsg) {
+	if msg.gen != m.histogramGen {
+		return
+	}
+	m.histogram, m.histogramBuilt, m.histogramPending = msg.histogram, true, false
+}
+
+// invalidateHistogram discards the cached histogram, and any build in
+// progress, so it's built again, e.g. after the underlying index has grown.
+func (m *Model) invalidateHistogram() {
+	m.histogram = nil
+	m.histogramBuilt = false
+	m.histogramPending = false
+	m.histogramGen++
+}
+
+// lineTime returns the parsed timestamp of the given line.
+func (m *Model) lineTime(n int) (time.Time, bool) {
+	line, err := m.idx.GetLine(n)
+	if err != nil {
+		return time.Time{}, false
+	}
+	var entry parser.LogEntry
+	if err := m.parser.ParseInto(line, n, &entry); err != nil {
+		return time.Time{}, false
+	}
+	return parser.ParseTime(entry.Time)
+}
+
+// renderSparkline renders the header sparkline with the bucket containing
+// the cursor's entry highlighted. Returns an empty string when disabled,
+// while the histogram is being built, or when the file has no parseable
+// timestamps.
+func (m *Model) renderSparkline() string {
+	if !m.showSparkline {
+		return ""
+	}
+	h := m.histogram
+	if h == nil {
+		return ""
+	}
+
+	bars := sparkline(h.counts)
+	t, ok := m.lineTime(m.cursorLine())
+	if !ok {
+		return m.styles.Help.Render(string(bars))
+	}
+
+	mark := h.bucket(t)
+	cell := bars[mark]
+	if cell == sparkBars[0] {
+		cell = sparkBars[1]
+	}
+	return m.styles.Help.Render(string(bars[:mark])) +
+		m.styles.Title.Render(string(cell)) +
+		m.styles.Help.Render(string(bars[mark+1:]))
+}
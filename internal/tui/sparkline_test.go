@@ -0,0 +1,149 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// TestBuildHistogram verifies times are bucketed across the span.
+func TestBuildHistogram(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	times := []time.Time{
+		base,
+		base.Add(1 * time.Minute),
+		base.Add(2 * time.Minute),
+		base.Add(9 * time.Minute),
+		base.Add(10 * time.Minute),
+	}
+
+	h := buildHistogram(times, 5)
+	if h == nil {
+		t.Fatal("expected a histogram")
+	}
+	want := []int{2, 1, 0, 0, 2}
+	for i, c := range want {
+		if h.counts[i] != c {
+			t.Errorf("bucket %d: expected %d, got %d", i, c, h.counts[i])
+		}
+	}
+
+	if buildHistogram(nil, 5) != nil {
+		t.Error("expected nil histogram for no times")
+	}
+
+	// Spans of centuries, as epoch-0 timestamps give, don't overflow
+	wide := buildHistogram([]time.Time{time.Unix(0, 0), base, base.AddDate(-27, 0, 0)}, 32)
+	if wide.counts[0] != 1 || wide.counts[31] != 1 || wide.counts[16] != 1 {
+		t.Errorf("expected the ends and the middle bucket filled, got %v", wide.counts)
+	}
+
+	// A zero-length span puts everything in the first bucket
+	same := buildHistogram([]time.Time{base, base}, 4)
+	if same.counts[0] != 2 {
+		t.Errorf("expected both entries in bucket 0, got %v", same.counts)
+	}
+}
+
+// TestSparkline verifies counts scale to block glyphs.
+func TestSparkline(t *testing.T) {
+	got := string(sparkline([]int{0, 1, 4, 8}))
+	want := " ▁▄█"
+	if got != want {
+		t.Errorf("sparkline: expected %q, got %q", want, got)
+	}
+
+	// Small non-zero counts still render a visible bar
+	got = string(sparkline([]int{1, 100}))
+	if []rune(got)[0] == ' ' {
+		t.Errorf("expected non-empty bar for small count, got %q", got)
+	}
+}
+
+// TestRenderSparkline verifies the header sparkline and its toggle.
+func TestRenderSparkline(t *testing.T) {
+	content := ""
+	for i := 0; i < 10; i++ {
+		content += fmt.Sprintf(`{"time":"2024-01-01T00:%02d:00Z","level":"info","msg":"test"}`, i) + "\n"
+	}
+	idx := createTestIndex(t, content)
+	defer closeIndex(idx)
+
+	m := New(idx)
+	m.width = 120
+	m.height = 30
+
+	// The histogram is built in the background, off the render path
+	if m.renderSparkline() != "" {
+		t.Error("expected no sparkline before the histogram is built")
+	}
+	buildSparkline(t, &m)
+	spark := m.renderSparkline()
+	if spark == "" {
+		t.Fatal("expected sparkline for timestamped logs")
+	}
+	if !strings.ContainsAny(spark, "▁▂▃▄▅▆▇█") {
+		t.Errorf("expected block glyphs, got %q", spark)
+	}
+	if !strings.Contains(m.View(), spark) {
+		t.Error("expected sparkline in the header")
+	}
+
+	// T toggles it off
+	newM, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'T'}})
+	m = *newM.(*Model)
+	if m.renderSparkline() != "" {
+		t.Error("expected no sparkline after toggling off")
+	}
+}
+
+// TestRenderSparklineNoTimes verifies the sparkline degrades to nothing
+// when timestamps can't be parsed.
+func TestRenderSparklineNoTimes(t *testing.T) {
+	content := `{"time":"yesterday","level":"info","msg":"test"}
+{"level":"info","msg":"no time"}`
+	idx := createTestIndex(t, content)
+	defer closeIndex(idx)
+
+	m := New(idx)
+	buildSparkline(t, &m)
+	if got := m.renderSparkline(); got != "" {
+		t.Errorf("expected empty sparkline, got %q", got)
+	}
+}
+
+// buildSparkline runs the background histogram build and delivers its
+// result to m.
+func buildSparkline(t *testing.T, m *Model) {
+	t.Helper()
+	cmd := m.histogramCmd()
+	if cmd == nil {
+		t.Fatal("expected a histogram build")
+	}
+	m.Update(cmd())
+	if !m.histogramBuilt {
+		t.Fatal("expected the histogram installed")
+	}
+}
+
+// TestHistogramInvalidated verifies a build started before the data
+// changed is dropped and a new one is started.
+func TestHistogramInvalidated(t *testing.T) {
+	idx := createTestIndex(t, `{"time":"2024-01-01T00:00:00Z","msg":"a"}`+"\n"+`{"time":"2024-01-01T00:01:00Z","msg":"b"}`)
+	defer closeIndex(idx)
+
+	m := New(idx)
+	stale := m.histogramCmd()
+	if m.histogramCmd() != nil {
+		t.Error("expected no second build while one is running")
+	}
+	m.invalidateHistogram()
+	m.Update(stale())
+	if m.histogramBuilt {
+		t.Error("expected the stale histogram dropped")
+	}
+	buildSparkline(t, &m)
+}
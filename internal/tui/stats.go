@@ -0,0 +1,214 @@
+package tui
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/lbe/jsonlogviewer/internal/index"
+	"github.com/lbe/jsonlogviewer/internal/parser"
+)
+
+// maxStatsBar is the widest a bar of the stats overlay is drawn.
+const maxStatsBar = 40
+
+// statsView is the open stats overlay: how many of the lines shown have
+// each level and, when a field was asked for, each value of it.
+type statsView struct {
+	// field is the gjson path whose values are counted; "" counts levels
+	// only.
+	field string
+	// gen identifies the scan filling in the counts.
+	gen int
+	// filtered records that only the lines shown by filters were counted.
+	filtered bool
+	// done is set once the scan finished; levels and values are nil
+	// until then.
+	done   bool
+	err    error
+	levels map[string]int
+	values map[string]int
+	// offset is the first overlay line shown.
+	offset int
+}
+
+// statsScanDoneMsg carries the counts of the stats scan started as gen.
+type statsScanDoneMsg struct {
+	gen    int
+	levels map[string]int
+	values map[string]int
+	err    error
+}
+
+// openStats shows the stats overlay for field, counting the lines the
+// filters show in the background so a big file doesn't stall the UI.
+func (m *Model) openStats(field string) tea.Cmd {
+	m.statsGen++
+	m.stats = &statsView{field: field, gen: m.statsGen, filtered: m.visible != nil}
+	// The visible rows are extended in place as lines arrive, so the
+	// scan gets its own copy
+	return scanStats(m.idx, m.statsGen, m.parser.Format(), field, slices.Clone(m.visible))
+}
+
+// scanStats counts the levels, and the values of field when it's set, of
+// lines in idx; nil lines counts every line.
+func scanStats(idx *index.Index, gen int, format parser.Format, field string, lines []int) tea.Cmd {
+	return func() tea.Msg {
+		p := parser.New()
+		p.SetFormat(format)
+		msg := statsScanDoneMsg{gen: gen}
+		msg.levels, msg.err = p.LevelHistogram(idx, lines)
+		if msg.err == nil && field != "" {
+			msg.values, msg.err = parser.FieldHistogramLines(idx, field, lines)
+		}
+		return msg
+	}
+}
+
+// statsScanDone installs the counts of a finished scan, unless the overlay
+// was closed or another scan started since.
+func (m *Model) statsScanDone(msg statsScanDoneMsg) {
+	if m.stats == nil || m.stats.gen != msg.gen {
+		return
+	}
+	m.stats.done = true
+	m.stats.levels, m.stats.values, m.stats.err = msg.levels, msg.values, msg.err
+}
+
+// handleStatsKey handles input while the stats overlay is open.
+func (m *Model) handleStatsKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "j", "down":
+		m.stats.offset++
+	case "k", "up":
+		m.stats.offset--
+	case "ctrl+d", "pgdown":
+		m.stats.offset += m.viewport.Height
+	case "ctrl+u", "pgup":
+		m.stats.offset -= m.viewport.Height
+	case "g", "home":
+		m.stats.offset = 0
+	case "s":
+		m.openPrompt(promptStats)
+	case "q", "esc":
+		m.stats = nil
+	}
+	return m, nil
+}
+
+// statsBar is one counted value of the overlay with the color of its bar.
+type statsBar struct {
+	label string
+	count int
+	color string
+}
+
+// statsLines lays out the overlay: a section of bars per level, then one
+// per value of the field, largest first.
+func (m *Model) statsLines() []string {
+	s := m.stats
+	if !s.done {
+		return []string{m.styles.Help.Render("counting…")}
+	}
+	if s.err != nil {
+		return []string{m.styles.ErrorMsg.Render(fmt.Sprintf("stats failed: %v", s.err))}
+	}
+
+	total := 0
+	for _, n := range s.levels {
+		total += n
+	}
+	scope := "all lines"
+	if s.filtered {
+		scope = "lines shown by the filters"
+	}
+
+	levels := make([]statsBar, 0, len(s.levels))
+	for level, n := range s.levels {
+		label := level
+		if label == "" {
+			label = "(none)"
+		}
+		levels = append(levels, statsBar{label, n, m.levelColor(level)})
+	}
+	// Severity order reads more naturally than counts for levels
+	slices.SortFunc(levels, func(a, b statsBar) int {
+		return cmp.Or(cmp.Compare(parser.LevelRank(b.label), parser.LevelRank(a.label)), strings.Compare(a.label, b.label))
+	})
+
+	lines := []string{m.styles.Title.Render(fmt.Sprintf("Levels of %d %s", total, scope))}
+	lines = append(lines, m.statsBars(levels, total)...)
+	if s.field == "" {
+		return lines
+	}
+
+	values := make([]statsBar, 0, len(s.values))
+	for value, n := range s.values {
+		label := parser.NormalizeCell(value)
+		if value == "" {
+			label = "(missing)"
+		}
+		values = append(values, statsBar{label: label, count: n})
+	}
+	slices.SortFunc(values, func(a, b statsBar) int {
+		return cmp.Or(cmp.Compare(b.count, a.count), strings.Compare(a.label, b.label))
+	})
+	lines = append(lines, "", m.styles.Title.Render(fmt.Sprintf("%s: %d distinct values", s.field, len(values))))
+	return append(lines, m.statsBars(values, total)...)
+}
+
+// statsBars renders one line per bar: the label, a bar scaled to the
+// largest count, the count, and its share of total.
+func (m *Model) statsBars(bars []statsBar, total int) []string {
+	labelWidth, largest := 0, 0
+	for _, b := range bars {
+		labelWidth = max(labelWidth, ansi.StringWidth(b.label))
+		largest = max(largest, b.count)
+	}
+	labelWidth = min(labelWidth, max(m.width/3, 10))
+	countWidth := len(fmt.Sprint(largest))
+	barWidth := min(maxStatsBar, max(m.width-labelWidth-countWidth-12, 1))
+
+	lines := make([]string, 0, len(bars))
+	for _, b := range bars {
+		label := ansi.Truncate(b.label, labelWidth, "…")
+		label += strings.Repeat(" ", labelWidth-ansi.StringWidth(label))
+		filled := 0
+		if largest > 0 {
+			filled = max(b.count*barWidth/largest, 1)
+		}
+		bar := strings.Repeat("█", filled) + strings.Repeat(" ", barWidth-filled)
+		style := m.styles.Normal
+		if b.color != "" {
+			style = style.Foreground(lipgloss.Color(b.color))
+		}
+		share := 100 * float64(b.count) / float64(max(total, 1))
+		lines = append(lines, fmt.Sprintf("  %s %s %*d %5.1f%%", label, style.Render(bar), countWidth, b.count, share))
+	}
+	return lines
+}
+
+// renderStats renders height rows of the stats overlay, scrolled by its
+// offset.
+func (m *Model) renderStats(height int) []string {
+	lines := m.statsLines()
+	m.stats.offset = max(min(m.stats.offset, len(lines)-height), 0)
+	rows := lines[m.stats.offset:]
+	rows = rows[:min(len(rows), height)]
+	for i, row := range rows {
+		rows[i] = fitWidth(row, m.width)
+	}
+	for len(rows) < height {
+		rows = append(rows, "")
+	}
+	return rows
+}
+
+// statsStatus describes the open stats overlay for the status line.
+func (m *Model) statsStatus() string {
+	return " STATS: j/k scroll | s count another field | Esc close"
+}
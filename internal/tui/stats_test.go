@@ -0,0 +1,81 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// TestStatsOverlay verifies s counts levels and the values of a field over
+// the lines the filters show, largest first, and Esc closes the overlay.
+func TestStatsOverlay(t *testing.T) {
+	content := `{"level":"info","user":"alice"}
+{"level":"warning","user":"bob"}
+{"level":"error","user":"alice"}
+{"level":"error","user":"alice"}
+{"level":"error"}
+not json`
+	idx := createTestIndex(t, content)
+	defer closeIndex(idx)
+
+	m := New(idx)
+	m.Update(tea.WindowSizeMsg{Width: 120, Height: 20})
+
+	stats := func(field string) string {
+		t.Helper()
+		sendKeys(&m, "s"+field)
+		_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		if m.stats == nil || cmd == nil {
+			t.Fatal("expected s to open the overlay and start a scan")
+		}
+		if view := m.View(); !strings.Contains(view, "counting…") {
+			t.Errorf("expected the overlay to show the scan in progress, got:\n%s", view)
+		}
+		m.Update(cmd())
+		return ansi.Strip(m.View())
+	}
+
+	view := stats("user")
+	for _, want := range []string{
+		"Levels of 6 all lines",
+		"ERROR", "WARN", "INFO", "(none)",
+		"user: 3 distinct values",
+		"3  50.0%",
+		"(missing)",
+	} {
+		if !strings.Contains(view, want) {
+			t.Errorf("expected %q in the overlay, got:\n%s", want, view)
+		}
+	}
+	if strings.Index(view, "ERROR") > strings.Index(view, "INFO") {
+		t.Error("expected levels listed most severe first")
+	}
+	if alice, bob := strings.Index(view, "alice"), strings.Index(view, "bob"); alice < 0 || bob < alice {
+		t.Error("expected the most common value first")
+	}
+
+	// Filtered to ≥ERROR, only those lines count
+	m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.stats != nil {
+		t.Fatal("expected Esc to close the overlay")
+	}
+	sendKeys(&m, "+++++")
+	view = stats("")
+	if !strings.Contains(view, "Levels of 3 lines shown by the filters") || strings.Contains(view, "INFO") {
+		t.Errorf("expected only the filtered lines counted, got:\n%s", view)
+	}
+	if strings.Contains(view, "distinct values") {
+		t.Error("expected no field section without a field")
+	}
+
+	// A scan finishing after the overlay closed is dropped
+	sendKeys(&m, "suser")
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	sendKeys(&m, "q")
+	m.Update(cmd())
+	if m.stats != nil {
+		t.Error("expected a late scan not to reopen the overlay")
+	}
+}
@@ -0,0 +1,83 @@
+package tui
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/lbe/jsonlogviewer/internal/parser"
+)
+
+// tableHScrollStep is how many columns Shift+Left/Right move the table's
+// message text.
+const tableHScrollStep = 8
+
+// SetResetHScroll sets whether moving the cursor to another row scrolls
+// the table's messages back to their start. Off by default, so a scrolled
+// position carries over while stepping through similar messages.
+func (m *Model) SetResetHScroll(on bool) {
+	m.resetHScroll = on
+}
+
+// msgColumnWidth returns the width of the message column, or 0 when the
+// table doesn't show one.
+func (m *Model) msgColumnWidth() int {
+	for _, col := range m.columns {
+		if col.key == "msg" {
+			return m.colWidth(col)
+		}
+	}
+	return 0
+}
+
+// scrollTableH moves the table's message text by delta columns. The other
+// columns and all the labels stay put; the message label shows the offset.
+func (m *Model) scrollTableH(delta int) {
+	if m.msgColumnWidth() == 0 {
+		m.statusMsg = "no message column to scroll"
+		return
+	}
+	m.hScroll += delta
+	m.clampHScroll()
+}
+
+// clampHScroll keeps the message scroll within the longest message on
+// screen, stopping once its end is visible.
+func (m *Model) clampHScroll() {
+	maxOffset := m.visibleMsgWidth() - m.msgColumnWidth()
+	if m.hScroll > maxOffset {
+		m.hScroll = maxOffset
+	}
+	if m.hScroll < 0 {
+		m.hScroll = 0
+	}
+}
+
+// visibleMsgWidth returns the display width of the longest whole message
+// in the rows on screen.
+func (m *Model) visibleMsgWidth() int {
+	widest := 0
+	start, end := m.viewport.VisibleRange()
+	var entry parser.LogEntry
+	for pos := start; pos <= end && pos <= m.rowCount(); pos++ {
+		n := m.lineAt(pos)
+		line, err := m.idx.GetLine(n)
+		if err != nil || m.parser.ParseInto(line, n, &entry) != nil {
+			continue
+		}
+		widest = max(widest, ansi.StringWidth(parser.NormalizeCell(entry.Msg)))
+	}
+	return widest
+}
+
+// scrollMsg drops the first hScroll display columns of a message cell.
+func (m *Model) scrollMsg(text string) string {
+	if m.hScroll == 0 {
+		return text
+	}
+	return ansi.Cut(text, m.hScroll, ansi.StringWidth(text))
+}
+
+// hScrollState describes a scrolled message column, e.g. "msg +16".
+func (m *Model) hScrollState() string {
+	return fmt.Sprintf("msg +%d", m.hScroll)
+}
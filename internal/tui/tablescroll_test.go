@@ -0,0 +1,100 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// TestTableHScroll verifies Shift+Left/Right shift only the message text,
+// reveal the tail past the usual truncation, and clamp at both ends. The
+// header keeps every label in place and marks the message offset.
+func TestTableHScroll(t *testing.T) {
+	long := strings.Repeat("a", 150) + "TAIL"
+	content := `{"level":"info","msg":"` + long + `"}
+{"level":"info","msg":"short"}`
+	idx := createTestIndex(t, content)
+	defer closeIndex(idx)
+
+	m := New(idx)
+	m.Update(tea.WindowSizeMsg{Width: 300, Height: 20})
+	width := m.msgColumnWidth()
+
+	right := tea.KeyMsg{Type: tea.KeyShiftRight}
+	left := tea.KeyMsg{Type: tea.KeyShiftLeft}
+
+	m.Update(right)
+	if m.hScroll != tableHScrollStep {
+		t.Fatalf("expected scroll %d, got %d", tableHScrollStep, m.hScroll)
+	}
+	header := m.formatHeader()
+	msgAt := strings.Index(header, "Message")
+	if msgAt < 0 || !strings.Contains(header, "Message +8") {
+		t.Fatalf("expected the message label to show the offset, got %q", header)
+	}
+
+	// Scroll all the way right: the offset stops once the tail is visible
+	for range 50 {
+		m.Update(right)
+	}
+	if want := len(long) - width; m.hScroll != want {
+		t.Errorf("expected scroll clamped at %d, got %d", want, m.hScroll)
+	}
+	view := m.View()
+	if !strings.Contains(view, "TAIL") {
+		t.Error("expected the message tail in the table")
+	}
+	if !strings.Contains(view, "msg +") {
+		t.Error("expected the scroll offset in the status line")
+	}
+	scrolled := m.formatHeader()
+	if scrolled[:msgAt] != header[:msgAt] || strings.Index(scrolled, "Message") != msgAt {
+		t.Errorf("expected the labels to stay in place while scrolling, got %q", scrolled)
+	}
+	if want := fmt.Sprintf("Message +%d", m.hScroll); !strings.Contains(scrolled, want) {
+		t.Errorf("expected %q in the header, got %q", want, scrolled)
+	}
+
+	for range 50 {
+		m.Update(left)
+	}
+	if m.hScroll != 0 {
+		t.Errorf("expected scroll clamped at 0, got %d", m.hScroll)
+	}
+	if strings.Contains(m.formatHeader(), "Message +") {
+		t.Error("expected the plain message label once scrolled back")
+	}
+	if strings.Contains(m.formatRow(mustParse(t, &m, 1)), "TAIL") {
+		t.Error("expected the message cut to its column once scrolled back")
+	}
+}
+
+// TestTableHScrollReset verifies the scroll is kept across rows by default
+// and reset on row change when configured.
+func TestTableHScrollReset(t *testing.T) {
+	content := `{"level":"info","msg":"` + strings.Repeat("a", 200) + `"}
+{"level":"info","msg":"` + strings.Repeat("b", 200) + `"}`
+
+	for _, reset := range []bool{false, true} {
+		idx := createTestIndex(t, content)
+		m := New(idx)
+		m.SetResetHScroll(reset)
+		m.Update(tea.WindowSizeMsg{Width: 300, Height: 20})
+		m.View()
+
+		m.Update(tea.KeyMsg{Type: tea.KeyShiftRight})
+		sendKeys(&m, "j")
+		m.View()
+
+		want := tableHScrollStep
+		if reset {
+			want = 0
+		}
+		if m.hScroll != want {
+			t.Errorf("reset %v: expected scroll %d after moving rows, got %d", reset, want, m.hScroll)
+		}
+		closeIndex(idx)
+	}
+}
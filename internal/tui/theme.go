@@ -0,0 +1,157 @@
+package tui
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lbe/jsonlogviewer/internal/parser"
+)
+
+// Theme overrides the default colors. It is read from a JSON file such as:
+//
+//	{
+//	  "levels": {"info": "#005F00", "warn": "#875F00"},
+//	  "ui": {"header": {"fg": "#000000", "bg": "#D0D0D0"}}
+//	}
+//
+// Colors left out keep their defaults.
+type Theme struct {
+	// Levels maps level names, matched case-insensitively, to the hex color
+	// of their table rows.
+	Levels map[string]string `json:"levels"`
+	// UI holds the colors of the main interface elements.
+	UI UIColors `json:"ui"`
+}
+
+// UIColors holds the colors of the interface elements a theme can change.
+type UIColors struct {
+	Header      ElementColors `json:"header"`
+	Selected    ElementColors `json:"selected"`
+	Highlight   ElementColors `json:"highlight"`
+	Normal      ElementColors `json:"normal"`
+	SearchMatch ElementColors `json:"search_match"`
+	Match       ElementColors `json:"match"`
+	Visual      ElementColors `json:"visual"`
+	ErrorMsg    ElementColors `json:"error_msg"`
+	ParseError  ElementColors `json:"parse_error"`
+	Detail      ElementColors `json:"detail"`
+	Title       ElementColors `json:"title"`
+	Help        ElementColors `json:"help"`
+	Separator   ElementColors `json:"separator"`
+}
+
+// ElementColors is the foreground and background of one element; empty
+// values keep the default.
+type ElementColors struct {
+	Foreground string `json:"fg"`
+	Background string `json:"bg"`
+}
+
+// hexColor matches the #RGB and #RRGGBB forms accepted in a theme.
+var hexColor = regexp.MustCompile(`^#([0-9A-Fa-f]{3}|[0-9A-Fa-f]{6})$`)
+
+// LoadTheme reads and validates the theme file at path. A missing file
+// returns an error satisfying os.IsNotExist.
+func LoadTheme(path string) (*Theme, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseTheme(data)
+}
+
+// ParseTheme parses a JSON theme, rejecting unknown keys and colors that
+// aren't hex values so a typo doesn't silently leave the defaults.
+func ParseTheme(data []byte) (*Theme, error) {
+	var theme Theme
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&theme); err != nil {
+		return nil, fmt.Errorf("invalid theme: %w", err)
+	}
+
+	levels := make(map[string]string, len(theme.Levels))
+	for level, color := range theme.Levels {
+		if !hexColor.MatchString(color) {
+			return nil, fmt.Errorf("invalid theme: level %q: color %q is not #RGB or #RRGGBB", level, color)
+		}
+		levels[strings.ToUpper(level)] = color
+	}
+	theme.Levels = levels
+
+	for _, e := range theme.UI.elements(nil) {
+		for _, color := range []string{e.colors.Foreground, e.colors.Background} {
+			if color != "" && !hexColor.MatchString(color) {
+				return nil, fmt.Errorf("invalid theme: ui %q: color %q is not #RGB or #RRGGBB", e.name, color)
+			}
+		}
+	}
+	return &theme, nil
+}
+
+// themeElement pairs an element's theme colors with the style they apply to.
+type themeElement struct {
+	name   string
+	colors ElementColors
+	style  *lipgloss.Style
+}
+
+// elements lists the UI colors by JSON name next to the matching field of
+// s; s may be nil when only the colors are needed.
+func (u UIColors) elements(s *Styles) []themeElement {
+	if s == nil {
+		s = &Styles{}
+	}
+	return []themeElement{
+		{"header", u.Header, &s.Header},
+		{"selected", u.Selected, &s.Selected},
+		{"highlight", u.Highlight, &s.Highlight},
+		{"normal", u.Normal, &s.Normal},
+		{"search_match", u.SearchMatch, &s.SearchMatch},
+		{"match", u.Match, &s.Match},
+		{"visual", u.Visual, &s.Visual},
+		{"error_msg", u.ErrorMsg, &s.ErrorMsg},
+		{"parse_error", u.ParseError, &s.ParseError},
+		{"detail", u.Detail, &s.Detail},
+		{"title", u.Title, &s.Title},
+		{"help", u.Help, &s.Help},
+		{"separator", u.Separator, &s.Separator},
+	}
+}
+
+// SetTheme applies a theme's colors on top of the default styles for the
+// model's background. A nil theme restores the defaults.
+func (m *Model) SetTheme(theme *Theme) {
+	m.theme = theme
+	m.styles = DefaultStyles(m.background)
+	if theme == nil {
+		return
+	}
+	for _, e := range theme.UI.elements(m.styles) {
+		if e.colors.Foreground != "" {
+			*e.style = e.style.Foreground(lipgloss.Color(e.colors.Foreground))
+		}
+		if e.colors.Background != "" {
+			*e.style = e.style.Background(lipgloss.Color(e.colors.Background))
+		}
+	}
+}
+
+// levelColor returns the row color for a level: the theme's color when it
+// sets one for the level as written or for its canonical name, otherwise
+// the default for the background.
+func (m *Model) levelColor(level string) string {
+	if m.theme != nil {
+		for _, name := range []string{strings.ToUpper(level), parser.NormalizeLevel(level)} {
+			if color, ok := m.theme.Levels[name]; ok {
+				return color
+			}
+		}
+	}
+	return m.defaultLevelColor(level)
+}
@@ -0,0 +1,136 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// TestParseTheme verifies level names are matched case-insensitively and
+// that unknown keys and non-hex colors are rejected.
+func TestParseTheme(t *testing.T) {
+	theme, err := ParseTheme([]byte(`{"levels":{"Info":"#005F00"},"ui":{"header":{"fg":"#000","bg":"#D0D0D0"}}}`))
+	if err != nil {
+		t.Fatalf("ParseTheme: %v", err)
+	}
+	if got := theme.Levels["INFO"]; got != "#005F00" {
+		t.Errorf("expected INFO color #005F00, got %q", got)
+	}
+	if theme.UI.Header.Background != "#D0D0D0" {
+		t.Errorf("expected header background #D0D0D0, got %q", theme.UI.Header.Background)
+	}
+
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"syntax", `{"levels":`, "invalid theme"},
+		{"unknown key", `{"colours":{}}`, "colours"},
+		{"level color", `{"levels":{"error":"red"}}`, `"error"`},
+		{"ui color", `{"ui":{"title":{"fg":"#12345"}}}`, `"title"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseTheme([]byte(tt.data))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+// TestLoadThemeMissing verifies a missing file is reported as not existing
+// so callers can fall back to the defaults quietly.
+func TestLoadThemeMissing(t *testing.T) {
+	_, err := LoadTheme(filepath.Join(t.TempDir(), "theme.json"))
+	if !os.IsNotExist(err) {
+		t.Errorf("expected a not-exist error, got %v", err)
+	}
+}
+
+// TestSetTheme verifies a theme recolors its levels and UI elements while
+// everything it leaves out keeps the default.
+func TestSetTheme(t *testing.T) {
+	idx := createTestIndex(t, levelContent)
+	defer closeIndex(idx)
+
+	m := New(idx)
+	theme, err := ParseTheme([]byte(`{"levels":{"error":"#AF0000"},"ui":{"selected":{"bg":"#FFFFAF"}}}`))
+	if err != nil {
+		t.Fatalf("ParseTheme: %v", err)
+	}
+	m.SetTheme(theme)
+
+	if got := m.levelColor("ERROR"); got != "#AF0000" {
+		t.Errorf("expected themed ERROR color, got %q", got)
+	}
+	if got := m.levelColor("err"); got != "#AF0000" {
+		t.Errorf("expected the ERROR color for its alias err, got %q", got)
+	}
+	if got := m.levelColor("info"); got != "#00FF00" {
+		t.Errorf("expected default INFO color, got %q", got)
+	}
+	if got := m.styles.Selected.GetBackground(); got != lipgloss.Color("#FFFFAF") {
+		t.Errorf("expected themed selected background, got %v", got)
+	}
+	if got := m.styles.Selected.GetForeground(); got != lipgloss.Color("#FFFFFF") {
+		t.Errorf("expected default selected foreground, got %v", got)
+	}
+
+	m.SetTheme(nil)
+	if got := m.levelColor("ERROR"); got != "#FF0000" {
+		t.Errorf("expected nil theme to restore the default ERROR color, got %q", got)
+	}
+	if got := m.styles.Selected.GetBackground(); got != lipgloss.Color("#5C5C5C") {
+		t.Errorf("expected nil theme to restore the default selected background, got %v", got)
+	}
+}
+
+// TestBackground verifies the light background swaps in darker level
+// colors and pale tints, keeps a theme's colors on top whatever order the
+// options come in, and that ParseBackground rejects unknown names.
+func TestBackground(t *testing.T) {
+	idx := createTestIndex(t, `{"level":"error","msg":"x"}`)
+	defer closeIndex(idx)
+
+	m := New(idx, WithBackground(BackgroundLight))
+	if got := m.levelColor("error"); got != lightLevelColors["ERROR"] {
+		t.Errorf("expected the light error color, got %q", got)
+	}
+	if got := m.levelTintColor("warning"); got != lightLevelTints["WARN"] {
+		t.Errorf("expected the light warn tint, got %q", got)
+	}
+	if got := m.styles.Normal.GetForeground(); got != lightStyles().Normal.GetForeground() {
+		t.Errorf("expected the light normal style, got %v", got)
+	}
+
+	theme := &Theme{
+		Levels: map[string]string{"ERROR": "#AF0000"},
+		UI:     UIColors{Selected: ElementColors{Background: "#FFD700"}},
+	}
+	for name, opts := range map[string][]Option{
+		"theme first":      {WithTheme(theme), WithBackground(BackgroundLight)},
+		"background first": {WithBackground(BackgroundLight), WithTheme(theme)},
+	} {
+		m := New(idx, opts...)
+		if m.levelColor("error") != "#AF0000" || m.styles.Selected.GetBackground() != lipgloss.Color("#FFD700") {
+			t.Errorf("%s: expected the theme's colors kept", name)
+		}
+		if m.levelColor("info") != lightLevelColors["INFO"] {
+			t.Errorf("%s: expected the light default for levels the theme leaves out", name)
+		}
+	}
+
+	for name, want := range map[string]Background{"dark": BackgroundDark, "light": BackgroundLight} {
+		if got, err := ParseBackground(name); err != nil || got != want {
+			t.Errorf("ParseBackground(%q): expected %v, got %v (%v)", name, want, got, err)
+		}
+	}
+	if _, err := ParseBackground("solarized"); err == nil {
+		t.Error("expected an unknown name to be rejected")
+	}
+}
@@ -0,0 +1,53 @@
+package tui
+
+import (
+	"time"
+
+	"github.com/lbe/jsonlogviewer/internal/parser"
+)
+
+// SetTimeRange shows only entries whose timestamps fall between since and
+// until, inclusive; a zero bound leaves that end open. Entries without a
+// recognized timestamp are kept unless excludeUntimed is set.
+func (m *Model) SetTimeRange(since, until time.Time, excludeUntimed bool) {
+	m.since, m.until, m.excludeUntimed = since, until, excludeUntimed
+	m.applyFilters()
+}
+
+// timeFiltering reports whether the time range is hiding lines.
+func (m *Model) timeFiltering() bool {
+	return !m.since.IsZero() || !m.until.IsZero() || m.excludeUntimed
+}
+
+// inTimeRange reports whether a parsed line survives the time range.
+func (m *Model) inTimeRange(entry *parser.LogEntry) bool {
+	return parser.InTimeRange(entry.Timestamp, m.since, m.until, m.excludeUntimed)
+}
+
+// clearTimeRange removes the time range without rebuilding the rows.
+func (m *Model) clearTimeRange() {
+	m.since, m.until, m.excludeUntimed = time.Time{}, time.Time{}, false
+}
+
+// timeState describes the time range for the status line, e.g.
+// "2024-01-15T10:00:00Z..2024-01-15T11:00:00Z" or "≥2024-01-15T10:00:00Z",
+// with "-untimed" when lines without a timestamp are hidden. Empty when no
+// range is set.
+func (m *Model) timeState() string {
+	var state string
+	switch {
+	case !m.since.IsZero() && !m.until.IsZero():
+		state = m.since.Format(time.RFC3339) + ".." + m.until.Format(time.RFC3339)
+	case !m.since.IsZero():
+		state = "≥" + m.since.Format(time.RFC3339)
+	case !m.until.IsZero():
+		state = "≤" + m.until.Format(time.RFC3339)
+	}
+	if m.excludeUntimed {
+		if state != "" {
+			state += " "
+		}
+		state += "-untimed"
+	}
+	return state
+}
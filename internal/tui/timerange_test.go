@@ -0,0 +1,72 @@
+package tui
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+const timedContent = `{"time":"2024-01-15T09:00:00Z","level":"info","msg":"a"}
+{"time":"2024-01-15T10:00:00Z","level":"error","msg":"b"}
+{"msg":"no time"}
+{"time":"2024-01-15T10:30:00Z","level":"info","msg":"c"}
+{"time":"1705316400","level":"error","msg":"d"}
+{"time":"2024-01-15T12:00:00Z","level":"info","msg":"e"}`
+
+// TestTimeRange verifies -since and -until keep only lines in the range,
+// inclusive, along with untimed lines unless those are excluded, and that
+// the range combines with level filters and clears with f0.
+func TestTimeRange(t *testing.T) {
+	idx := createTestIndex(t, timedContent)
+	defer closeIndex(idx)
+
+	m := New(idx)
+	m.width = 120
+	m.height = 30
+
+	at := func(hour, minute int) time.Time {
+		return time.Date(2024, 1, 15, hour, minute, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		name     string
+		since    time.Time
+		until    time.Time
+		untimed  bool
+		want     []int
+		wantTime string
+	}{
+		{"since", at(10, 0), time.Time{}, false, []int{2, 3, 4, 5, 6}, "≥2024-01-15T10:00:00Z"},
+		{"until", time.Time{}, at(10, 30), false, []int{1, 2, 3, 4}, "≤2024-01-15T10:30:00Z"},
+		{"both", at(10, 0), at(11, 0), false, []int{2, 3, 4, 5}, "2024-01-15T10:00:00Z..2024-01-15T11:00:00Z"},
+		{"exclude untimed", at(10, 0), at(11, 0), true, []int{2, 4, 5}, "2024-01-15T10:00:00Z..2024-01-15T11:00:00Z -untimed"},
+		{"untimed only", time.Time{}, time.Time{}, true, []int{1, 2, 4, 5, 6}, "-untimed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m.SetTimeRange(tt.since, tt.until, tt.untimed)
+			var got []int
+			for pos := 1; pos <= m.rowCount(); pos++ {
+				got = append(got, m.lineAt(pos))
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("rows = %v, want %v", got, tt.want)
+			}
+			if state := m.activeState(); !strings.Contains(state, "time:"+tt.wantTime) {
+				t.Errorf("status %q lacks time:%s", state, tt.wantTime)
+			}
+		})
+	}
+
+	m.SetTimeRange(at(10, 0), at(11, 0), false)
+	sendKeys(&m, "+++++")
+	if got := m.rowCount(); got != 2 {
+		t.Errorf("range with ≥ERROR: expected 2 rows, got %d", got)
+	}
+
+	sendKeys(&m, "f0")
+	if m.filtering() || m.rowCount() != 6 {
+		t.Errorf("after f0: expected every line shown, got %d rows (filtering %v)", m.rowCount(), m.filtering())
+	}
+}
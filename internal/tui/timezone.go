@@ -0,0 +1,66 @@
+package tui
+
+import (
+	"time"
+
+	"github.com/lbe/jsonlogviewer/internal/parser"
+)
+
+// displayTimeLayout is the default table layout for parsed timestamps,
+// kept to milliseconds so entries within the same second can be told
+// apart. The zone is left out because it's shown in the status line.
+const displayTimeLayout = "2006-01-02 15:04:05.000"
+
+// displayLocation returns the zone table timestamps are shown in.
+func (m *Model) displayLocation() *time.Location {
+	if m.displayLocal {
+		return time.Local
+	}
+	return time.UTC
+}
+
+// displayTime converts a raw timestamp into the active display zone.
+// Values that don't parse are returned unchanged.
+func (m *Model) displayTime(raw string) string {
+	t, ok := parser.ParseTime(raw)
+	if !ok {
+		return raw
+	}
+	return t.In(m.displayLocation()).Format(m.timeLayout)
+}
+
+// SetTimeFormat sets the Go time layout used to show parsed timestamps in
+// the table, resizing time columns to fit it. An empty layout keeps the
+// default.
+func (m *Model) SetTimeFormat(layout string) {
+	if layout == "" {
+		return
+	}
+	m.timeLayout = layout
+
+	// Size for the longest rendering: two-digit fields and full fractions
+	width := len(time.Date(2006, 12, 28, 23, 59, 59, 999999999, time.UTC).Format(layout))
+	for _, cols := range [][]column{m.columns, m.initialColumns} {
+		for i := range cols {
+			if cols[i].key == "time" {
+				cols[i].width = width
+			}
+		}
+	}
+}
+
+// zoneName returns the abbreviation of the active display zone, e.g. "UTC"
+// or "CET".
+func (m *Model) zoneName() string {
+	name, _ := time.Now().In(m.displayLocation()).Zone()
+	return name
+}
+
+// SetTimeField makes timestamps come from the given gjson path instead of
+// the auto-detected field, for the time column and everything built on
+// parsed times such as the histogram. An empty path restores detection.
+func (m *Model) SetTimeField(path string) {
+	m.parser.SetTimeField(path)
+	m.invalidateHistogram()
+	m.invalidateEntries()
+}
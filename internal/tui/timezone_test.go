@@ -0,0 +1,92 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestTimeZoneToggle verifies t shifts the table time by the local offset
+// and leaves the raw detail value alone.
+func TestTimeZoneToggle(t *testing.T) {
+	saved := time.Local
+	time.Local = time.FixedZone("XST", 2*60*60)
+	defer func() { time.Local = saved }()
+
+	content := `{"time":"2024-01-01T10:00:00Z","level":"info","msg":"test"}`
+	idx := createTestIndex(t, content)
+	defer closeIndex(idx)
+
+	m := New(idx)
+	m.width = 120
+	m.height = 30
+
+	row := m.formatRow(mustParse(t, &m, 1))
+	if !strings.Contains(row, "2024-01-01 10:00:00") {
+		t.Errorf("expected UTC time in row, got %q", row)
+	}
+	if !strings.Contains(m.View(), "UTC") {
+		t.Error("expected UTC in the status line")
+	}
+
+	sendKeys(&m, "t")
+	if !m.displayLocal {
+		t.Fatal("expected t to switch to local time")
+	}
+	row = m.formatRow(mustParse(t, &m, 1))
+	if !strings.Contains(row, "2024-01-01 12:00:00") {
+		t.Errorf("expected local time shifted by +2h, got %q", row)
+	}
+	view := m.View()
+	if !strings.Contains(view, "XST") {
+		t.Error("expected local zone abbreviation in the status line")
+	}
+	if !strings.Contains(view, `"2024-01-01T10:00:00Z"`) {
+		t.Error("expected the detail pane to keep the raw time value")
+	}
+
+	sendKeys(&m, "t")
+	if m.displayLocal {
+		t.Error("expected second t to switch back to UTC")
+	}
+}
+
+// TestDisplayTimeUnparsed verifies unrecognized timestamps are shown as-is.
+func TestDisplayTimeUnparsed(t *testing.T) {
+	m := Model{displayLocal: true}
+	if got := m.displayTime("yesterday-ish"); got != "yesterday-ish" {
+		t.Errorf("expected raw value, got %q", got)
+	}
+}
+
+// TestTimeFormat verifies epoch timestamps show as readable times, the
+// default layout keeps milliseconds, and -time-format changes the layout
+// and the time column width.
+func TestTimeFormat(t *testing.T) {
+	content := `{"ts":1705315800,"level":"info","msg":"epoch"}
+{"ts":"2024-01-15T10:50:00.123456Z","level":"info","msg":"fraction"}`
+	idx := createTestIndex(t, content)
+	defer closeIndex(idx)
+
+	m := New(idx)
+	if row := m.formatRow(mustParse(t, &m, 1)); !strings.Contains(row, "2024-01-15 10:50:00.000") {
+		t.Errorf("expected the epoch shown as a date, got %q", row)
+	}
+	if row := m.formatRow(mustParse(t, &m, 2)); !strings.Contains(row, "2024-01-15 10:50:00.123 ") {
+		t.Errorf("expected the default layout to keep milliseconds, got %q", row)
+	}
+
+	m.SetTimeFormat(time.Kitchen)
+	if row := m.formatRow(mustParse(t, &m, 1)); !strings.Contains(row, "10:50AM") {
+		t.Errorf("expected the kitchen layout, got %q", row)
+	}
+	if got := m.columns[0].width; got != len("11:59PM") {
+		t.Errorf("expected the time column sized to the layout, got %d", got)
+	}
+
+	// The layout survives a view reset
+	m.resetView()
+	if m.columns[0].width != len("11:59PM") || m.timeLayout != time.Kitchen {
+		t.Error("expected reset to keep the configured time format")
+	}
+}
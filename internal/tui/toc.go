@@ -0,0 +1,177 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lbe/jsonlogviewer/internal/parser"
+)
+
+// tocMode selects which lines the outline lists as anchors.
+type tocMode int
+
+const (
+	// tocErrors lists every ERROR, FATAL, or PANIC entry.
+	tocErrors tocMode = iota
+	// tocLevelChanges lists every entry whose level differs from the
+	// entry before it, plus the first entry.
+	tocLevelChanges
+	// tocModeCount is the number of outline modes c cycles through.
+	tocModeCount
+)
+
+// tocModeNames are the status-line names of each outline mode.
+var tocModeNames = [tocModeCount]string{"errors", "level changes"}
+
+// isAnchor reports whether an entry at a canonical level, following one
+// at prev, is an outline anchor in the given mode. first marks the file's
+// first entry, which has no prev.
+func isAnchor(mode tocMode, first bool, prev, level string) bool {
+	switch mode {
+	case tocErrors:
+		return parser.LevelRank(level) >= parser.LevelRank("ERROR")
+	case tocLevelChanges:
+		return first || prev != level
+	}
+	return false
+}
+
+// tocAnchors returns the anchor lines for mode in file order, scanning the
+// whole file the first time each mode is asked for and caching the result.
+func (m *Model) tocAnchors(mode tocMode) []int {
+	if anchors, ok := m.tocCache[mode]; ok {
+		return anchors
+	}
+
+	anchors := make([]int, 0)
+	var entry parser.LogEntry
+	prev, first := "", true
+	for n := 1; n <= m.idx.LineCount(); n++ {
+		line, err := m.idx.GetLine(n)
+		if err != nil || m.parser.ParseInto(line, n, &entry) != nil {
+			continue
+		}
+		if isAnchor(mode, first, prev, entry.CanonicalLevel) {
+			anchors = append(anchors, n)
+		}
+		prev, first = entry.CanonicalLevel, false
+	}
+
+	if m.tocCache == nil {
+		m.tocCache = make(map[tocMode][]int)
+	}
+	m.tocCache[mode] = anchors
+	return anchors
+}
+
+// openTOC shows the outline with the last anchor at or before the cursor
+// selected, so it opens where the reader already is.
+func (m *Model) openTOC() {
+	m.showTOC = true
+	m.tocOffset = 0
+	m.selectTOCAt(m.cursorLine())
+	m.pendingNumber = ""
+	m.lastG = false
+	m.resizeMode = false
+}
+
+// selectTOCAt selects the last anchor at or before line n.
+func (m *Model) selectTOCAt(n int) {
+	anchors := m.tocAnchors(m.tocMode)
+	i := sort.SearchInts(anchors, n+1) - 1
+	m.tocSelected = max(i, 0)
+}
+
+// moveTOC moves the outline selection by delta, clamped to the anchors.
+func (m *Model) moveTOC(delta int) {
+	last := len(m.tocAnchors(m.tocMode)) - 1
+	m.tocSelected = max(min(m.tocSelected+delta, last), 0)
+}
+
+// handleTOCKey handles input while the outline is open. Enter jumps the
+// main view to the selected anchor and closes the outline.
+func (m *Model) handleTOCKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "j", "down":
+		m.moveTOC(1)
+	case "k", "up":
+		m.moveTOC(-1)
+	case "ctrl+d", "pgdown":
+		m.moveTOC(m.viewport.Height)
+	case "ctrl+u", "pgup":
+		m.moveTOC(-m.viewport.Height)
+	case "g", "home":
+		m.tocSelected = 0
+	case "G", "end":
+		m.moveTOC(len(m.tocAnchors(m.tocMode)))
+	case "c":
+		line := m.cursorLine()
+		if anchors := m.tocAnchors(m.tocMode); len(anchors) > 0 {
+			line = anchors[m.tocSelected]
+		}
+		m.tocMode = (m.tocMode + 1) % tocModeCount
+		m.selectTOCAt(line)
+	case "enter":
+		if anchors := m.tocAnchors(m.tocMode); len(anchors) > 0 {
+			m.gotoLine(anchors[m.tocSelected])
+		}
+		m.showTOC = false
+	case "o", "q", "esc":
+		m.showTOC = false
+	}
+	return m, nil
+}
+
+// renderTOC renders height rows of the outline, one anchor per row with its
+// line number, time, level, and message, scrolled to keep the selection
+// in view.
+func (m *Model) renderTOC(height int) []string {
+	anchors := m.tocAnchors(m.tocMode)
+	rows := make([]string, 0, height)
+	if len(anchors) == 0 {
+		rows = append(rows, m.styles.Normal.Render("No "+tocModeNames[m.tocMode]+" found"))
+	}
+
+	if m.tocSelected < m.tocOffset {
+		m.tocOffset = m.tocSelected
+	}
+	if m.tocSelected >= m.tocOffset+height {
+		m.tocOffset = m.tocSelected - height + 1
+	}
+
+	var entry parser.LogEntry
+	for i := m.tocOffset; i < len(anchors) && len(rows) < height; i++ {
+		n := anchors[i]
+		text := fmt.Sprintf("%*d", rowNumWidth, n)
+		style := m.styles.Normal
+		if line, err := m.idx.GetLine(n); err == nil && m.parser.ParseInto(line, n, &entry) == nil {
+			text += fmt.Sprintf(" %-20s %-3s %s", m.displayTime(entry.Time),
+				parser.ShortenLevel(entry.Level), parser.NormalizeCell(entry.Msg))
+			if color := m.levelColor(entry.Level); color != "" {
+				style = style.Foreground(lipgloss.Color(color))
+			}
+		}
+		if i == m.tocSelected {
+			style = m.styles.Selected
+		}
+		rows = append(rows, style.Render(fitWidth(text, m.width)))
+	}
+
+	for len(rows) < height {
+		rows = append(rows, "")
+	}
+	return rows
+}
+
+// tocStatus describes the open outline for the status line.
+func (m *Model) tocStatus() string {
+	anchors := m.tocAnchors(m.tocMode)
+	pos := 0
+	if len(anchors) > 0 {
+		pos = m.tocSelected + 1
+	}
+	return fmt.Sprintf(" OUTLINE (%s %d/%d): j/k move | Enter jump | c %s | Esc close",
+		tocModeNames[m.tocMode], pos, len(anchors), tocModeNames[(m.tocMode+1)%tocModeCount])
+}
@@ -0,0 +1,80 @@
+package tui
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+const tocContent = `{"time":"2024-01-01T00:00:00Z","level":"info","msg":"start"}
+{"time":"2024-01-01T00:00:01Z","level":"INFO","msg":"tick"}
+{"time":"2024-01-01T00:00:02Z","level":"error","msg":"disk full"}
+{"time":"2024-01-01T00:00:03Z","level":"err","msg":"disk still full"}
+{"time":"2024-01-01T00:00:04Z","level":"warn","msg":"retrying"}
+{"time":"2024-01-01T00:00:05Z","level":"info","msg":"recovered"}
+{"time":"2024-01-01T00:00:06Z","level":"fatal","msg":"gave up"}`
+
+// TestTOCAnchors verifies anchor detection for both outline modes, with
+// level aliases such as err and error counting as one level.
+func TestTOCAnchors(t *testing.T) {
+	idx := createTestIndex(t, tocContent)
+	defer closeIndex(idx)
+
+	m := New(idx)
+
+	tests := []struct {
+		mode tocMode
+		want []int
+	}{
+		{tocErrors, []int{3, 4, 7}},
+		{tocLevelChanges, []int{1, 3, 5, 6, 7}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tocModeNames[tt.mode], func(t *testing.T) {
+			if got := m.tocAnchors(tt.mode); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected anchors %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+// TestTOCNavigation verifies the outline opens at the cursor, switches
+// criteria with c, and jumps the main view on Enter.
+func TestTOCNavigation(t *testing.T) {
+	idx := createTestIndex(t, tocContent)
+	defer closeIndex(idx)
+
+	m := New(idx)
+	m.width = 120
+	m.height = 30
+
+	m.viewport.Goto(5)
+	sendKeys(&m, "o")
+	if !m.showTOC || m.tocSelected != 1 {
+		t.Fatalf("expected outline open on the last error before line 5, got selected %d", m.tocSelected)
+	}
+	if view := ansi.Strip(m.View()); !strings.Contains(view, "disk still full") {
+		t.Error("expected the outline to list the error messages")
+	}
+
+	sendKeys(&m, "j\n")
+	if m.showTOC {
+		t.Error("expected Enter to close the outline")
+	}
+	if m.cursorLine() != 7 {
+		t.Errorf("expected to jump to line 7, got %d", m.cursorLine())
+	}
+
+	// c switches to level changes, keeping the selection near line 7
+	sendKeys(&m, "okc")
+	if m.tocMode != tocLevelChanges {
+		t.Fatal("expected c to switch to level changes")
+	}
+	sendKeys(&m, "\n")
+	if m.cursorLine() != 3 {
+		t.Errorf("expected to jump to level change at line 3, got %d", m.cursorLine())
+	}
+}
@@ -0,0 +1,83 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lbe/jsonlogviewer/internal/parser"
+)
+
+// treeLayout reports whether raw is shown as a tree: JSON in tree mode.
+// logfmt lines have no nesting and keep the key/value table.
+func (m *Model) treeLayout(raw []byte) bool {
+	return m.detailMode == detailTree && !m.parser.IsLogfmt(raw)
+}
+
+// treeNodes returns the tree of raw with the branches folded so far left
+// closed. Returns nil when raw isn't a JSON object.
+func (m *Model) treeNodes(raw []byte) []parser.TreeNode {
+	return parser.FormatTree(raw, func(path string) bool { return m.treeFolds[path] })
+}
+
+// treeLines renders raw as an indented tree: objects and arrays marked ▾
+// when open or ▸ when folded, followed by their size, and leaves as
+// key: value. Returns nil when raw isn't a JSON object.
+func (m *Model) treeLines(raw []byte) []string {
+	nodes := m.treeNodes(raw)
+	if nodes == nil {
+		return nil
+	}
+	lines := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		indent := strings.Repeat("  ", node.Depth)
+		if !node.Branch {
+			lines = append(lines, indent+"  "+m.styles.Help.Render(parser.NormalizeCell(node.Key)+":")+" "+m.markSearch(parser.NormalizeCell(node.Value)))
+			continue
+		}
+		marker, size := "▾ ", fmt.Sprintf("{%d}", node.Size)
+		if node.Collapsed {
+			marker = "▸ "
+		}
+		if node.Array {
+			size = fmt.Sprintf("[%d]", node.Size)
+		}
+		lines = append(lines, indent+marker+m.markSearch(parser.NormalizeCell(node.Key))+" "+m.styles.Help.Render(size))
+	}
+	return lines
+}
+
+// treeFields returns the tree's nodes as the fields the detail cursor
+// moves through, one per line: leaves with their values and branches with
+// their JSON.
+func treeFields(nodes []parser.TreeNode) []parser.KV {
+	kvs := make([]parser.KV, len(nodes))
+	for i, node := range nodes {
+		kvs[i] = parser.KV{Key: node.Path, Value: node.Value}
+	}
+	return kvs
+}
+
+// toggleFold folds or unfolds the object or array at path in the cursor
+// line's tree, reporting false when path isn't one. Folds are kept by path
+// as the cursor moves, so a noisy object stays folded on every row.
+func (m *Model) toggleFold(path string) bool {
+	raw, err := m.idx.GetLine(m.cursorLine())
+	if err != nil || !m.treeLayout(raw) {
+		return false
+	}
+	for _, node := range m.treeNodes(raw) {
+		if node.Path != path || !node.Branch {
+			continue
+		}
+		if node.Collapsed {
+			delete(m.treeFolds, path)
+		} else {
+			if m.treeFolds == nil {
+				m.treeFolds = make(map[string]bool)
+			}
+			m.treeFolds[path] = true
+		}
+		return true
+	}
+	return false
+}
@@ -0,0 +1,101 @@
+package tui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// toggleVisual starts a visual line selection anchored at the cursor line,
+// or cancels the one in progress. Moving the cursor extends the selection.
+func (m *Model) toggleVisual() {
+	m.pendingNumber = ""
+	m.lastG = false
+	m.resizeMode = false
+	if m.visual {
+		m.visual = false
+		return
+	}
+	if m.rowCount() == 0 {
+		m.statusMsg = "no lines to select"
+		return
+	}
+	m.visual = true
+	m.visualAnchor = m.cursorLine()
+}
+
+// handleVisualKey handles the keys that act on a visual selection and
+// reports whether it did; every other key, such as the motions that extend
+// the selection, goes through the usual handling.
+func (m *Model) handleVisualKey(msg tea.KeyMsg) bool {
+	switch msg.String() {
+	case "V", "esc":
+		m.visual = false
+	case "y":
+		m.yankSelection()
+	case "w":
+		m.openPrompt(promptExportSelection)
+	default:
+		return false
+	}
+	return true
+}
+
+// selection returns the first and last file lines of the visual selection,
+// the anchor and the cursor line in order. The selection is the block of
+// file lines between them, including any the filters hide.
+func (m *Model) selection() (start, end int) {
+	anchor, cursor := m.visualAnchor, m.cursorLine()
+	return min(anchor, cursor), max(anchor, cursor)
+}
+
+// inSelection reports whether file line n is in the visual selection.
+func (m *Model) inSelection(n int) bool {
+	if !m.visual {
+		return false
+	}
+	start, end := m.selection()
+	return n >= start && n <= end
+}
+
+// yankSelection copies the selected lines as written in the file and ends
+// the selection.
+func (m *Model) yankSelection() {
+	start, end := m.selection()
+	raw, err := m.idx.GetRawRange(start, end)
+	if err != nil {
+		m.statusMsg = fmt.Sprintf("cannot read lines: %v", err)
+		return
+	}
+	m.visual = false
+	m.copyOut(string(raw), selectionName(start, end))
+}
+
+// exportSelection writes the selected lines to path, asking before
+// overwriting an existing file, and ends the selection.
+func (m *Model) exportSelection(path string) {
+	if path == "" || !m.visual {
+		return
+	}
+	start, end := m.selection()
+	lines := make([]int, 0, end-start+1)
+	for n := start; n <= end; n++ {
+		lines = append(lines, n)
+	}
+	m.visual = false
+	m.confirmOverwrite(path, false, func() { m.exportLines(path, lines) })
+}
+
+// selectionName describes the lines start through end for status messages.
+func selectionName(start, end int) string {
+	if start == end {
+		return fmt.Sprintf("line %d", start)
+	}
+	return fmt.Sprintf("lines %d-%d", start, end)
+}
+
+// visualStatus describes the visual selection for the status line.
+func (m *Model) visualStatus() string {
+	start, end := m.selection()
+	return fmt.Sprintf(" VISUAL %s (%d): j/k extend | y copy | w write | Esc cancel", selectionName(start, end), end-start+1)
+}
@@ -0,0 +1,78 @@
+package tui
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// TestVisualSelection verifies V anchors a selection that follows the
+// cursor either way, y copies the block as written, w writes it, and Esc
+// cancels without asking to quit.
+func TestVisualSelection(t *testing.T) {
+	content := `{"level":"info","msg":"one"}
+{"level":"warn","msg":"two"}
+{"level":"error","msg":"three"}
+{"level":"info","msg":"four"}`
+	lines := strings.Split(content, "\n")
+	idx := createTestIndex(t, content)
+	defer closeIndex(idx)
+
+	m := New(idx)
+	m.Update(tea.WindowSizeMsg{Width: 160, Height: 20})
+	var copied string
+	m.copyText = func(s string) error {
+		copied = s
+		return nil
+	}
+
+	sendKeys(&m, "jVj")
+	if !m.visual {
+		t.Fatal("expected V to start a selection")
+	}
+	for n, want := range []bool{false, true, true, false} {
+		if got := m.inSelection(n + 1); got != want {
+			t.Errorf("line %d: expected selected %v, got %v", n+1, want, got)
+		}
+	}
+	if view := m.View(); !strings.Contains(view, "VISUAL lines 2-3 (2)") {
+		t.Errorf("expected the selection in the status line, got:\n%s", view)
+	}
+
+	// Moving past the anchor selects upward from it
+	sendKeys(&m, "kk")
+	if start, end := m.selection(); start != 1 || end != 2 {
+		t.Errorf("expected lines 1-2 selected, got %d-%d", start, end)
+	}
+
+	sendKeys(&m, "jjy")
+	if want := lines[1] + "\n" + lines[2]; copied != want {
+		t.Errorf("expected lines 2-3 copied, got %q", copied)
+	}
+	if m.visual || m.statusMsg != "copied lines 2-3" {
+		t.Errorf("expected the selection ended and reported, got visual=%v status %q", m.visual, m.statusMsg)
+	}
+
+	// y outside a selection copies the cursor line as before
+	sendKeys(&m, "y")
+	if copied != lines[2] {
+		t.Errorf("expected the cursor line copied, got %q", copied)
+	}
+
+	path := filepath.Join(t.TempDir(), "selection.log")
+	sendKeys(&m, "Vjw"+path+"\n")
+	if got := readFile(t, path); got != lines[2]+"\n"+lines[3]+"\n" {
+		t.Errorf("unexpected selection export: %q", got)
+	}
+	if m.visual {
+		t.Error("expected w to end the selection")
+	}
+
+	sendKeys(&m, "V")
+	m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.visual || m.confirmExit {
+		t.Errorf("expected Esc to cancel the selection only, got visual=%v confirmExit=%v", m.visual, m.confirmExit)
+	}
+}
@@ -0,0 +1,62 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+)
+
+// yank copies the cursor line's JSON to the clipboard, raw or
+// pretty-printed, and confirms in the status line. Without a clipboard,
+// as over SSH or on a headless machine, the text goes to a temp file and
+// its path is reported instead.
+func (m *Model) yank(pretty bool) {
+	n := m.cursorLine()
+	raw, err := m.idx.GetLine(n)
+	if err != nil {
+		m.statusMsg = fmt.Sprintf("cannot read line: %v", err)
+		return
+	}
+
+	text, form := string(raw), "raw"
+	if pretty {
+		formatted, err := m.parser.FormatPretty(raw)
+		if err != nil {
+			m.statusMsg = fmt.Sprintf("cannot format line %d: %v", n, err)
+			return
+		}
+		text, form = formatted, "pretty"
+	}
+	m.copyOut(text, fmt.Sprintf("line %d (%s JSON)", n, form))
+}
+
+// copyOut copies text to the clipboard, falling back to a temp file, and
+// reports which happened in the status line; what describes the text.
+func (m *Model) copyOut(text, what string) {
+	if err := m.copyText(text); err == nil {
+		m.statusMsg = "copied " + what
+		return
+	}
+
+	path, err := writeTempCopy(text)
+	if err != nil {
+		m.statusMsg = fmt.Sprintf("copy failed: no clipboard, and %v", err)
+		return
+	}
+	m.statusMsg = fmt.Sprintf("no clipboard; %s written to %s", what, path)
+}
+
+// writeTempCopy writes text to a new temp file and returns its path.
+func writeTempCopy(text string) (string, error) {
+	f, err := os.CreateTemp("", "jsonlogviewer-*.json")
+	if err != nil {
+		return "", fmt.Errorf("cannot create temp file: %w", err)
+	}
+	if _, err := f.WriteString(text + "\n"); err != nil {
+		_ = f.Close()
+		return "", fmt.Errorf("cannot write %s: %w", f.Name(), err)
+	}
+	if err := f.Close(); err != nil {
+		return "", fmt.Errorf("cannot write %s: %w", f.Name(), err)
+	}
+	return f.Name(), nil
+}
@@ -0,0 +1,48 @@
+package tui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestYank verifies y copies the raw line, Y the pretty-printed one, and
+// a missing clipboard falls back to a temp file whose path is reported.
+func TestYank(t *testing.T) {
+	content := `{"level":"info","msg":"first"}
+{"level":"error","msg":"second"}`
+	idx := createTestIndex(t, content)
+	defer closeIndex(idx)
+
+	m := New(idx)
+	var copied string
+	m.copyText = func(s string) error {
+		copied = s
+		return nil
+	}
+
+	sendKeys(&m, "jy")
+	if copied != `{"level":"error","msg":"second"}` {
+		t.Errorf("expected the raw cursor line copied, got %q", copied)
+	}
+	if m.statusMsg != "copied line 2 (raw JSON)" {
+		t.Errorf("unexpected status %q", m.statusMsg)
+	}
+
+	sendKeys(&m, "Y")
+	if want := "{\n  \"level\": \"error\",\n  \"msg\": \"second\"\n}"; copied != want {
+		t.Errorf("expected pretty JSON copied, got %q", copied)
+	}
+
+	// No clipboard: the text lands in a temp file instead
+	t.Setenv("TMPDIR", t.TempDir())
+	m.copyText = func(string) error { return errors.New("no clipboard utilities available") }
+	sendKeys(&m, "ky")
+	path, ok := strings.CutPrefix(m.statusMsg, "no clipboard; line 1 (raw JSON) written to ")
+	if !ok {
+		t.Fatalf("expected the temp file path in the status, got %q", m.statusMsg)
+	}
+	if got := readFile(t, path); got != `{"level":"info","msg":"first"}`+"\n" {
+		t.Errorf("expected the raw line in %s, got %q", path, got)
+	}
+}